@@ -1,8 +1,12 @@
 package commands
 
 import (
+	"context"
 	"os"
+	"os/signal"
+	"syscall"
 
+	"github.com/dietrichm/admirer/domain"
 	"github.com/spf13/cobra"
 )
 
@@ -15,9 +19,28 @@ var (
 	page  int
 )
 
+// pageOptions selects the loved tracks for the --limit and --page flags.
+// A limit of 0 selects all tracks from the page on, which are then requested in pages of the default page size.
+func pageOptions(limit int, page int) domain.LovedTracksOptions {
+	if limit == 0 {
+		return domain.LovedTracksOptions{PageSize: domain.DefaultPageSize, Offset: (page - 1) * domain.DefaultPageSize}
+	}
+	return domain.LovedTracksOptions{PageSize: limit, Offset: (page - 1) * limit, Limit: limit}
+}
+
 // Execute runs the requested CLI command.
+// Interrupting or terminating Admirer cancels the context of the command, so it can stop cleanly.
+// Once cancelled, the signals are no longer caught, so interrupting again terminates Admirer right away.
 func Execute() {
-	err := rootCommand.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	err := rootCommand.ExecuteContext(ctx)
 	if err != nil {
 		os.Exit(1)
 	}
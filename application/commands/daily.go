@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"github.com/dietrichm/admirer/infrastructure/config"
 	"github.com/dietrichm/admirer/infrastructure/services/spotify"
@@ -18,11 +19,11 @@ var dailyCommand = &cobra.Command{
 	Use:   "daily",
 	Short: "Create Discover Daily playlist from Spotify recommendations",
 	RunE: func(command *cobra.Command, args []string) error {
-		return daily(config.SecretsLoader, command.OutOrStdout())
+		return daily(command.Context(), config.SecretsLoader, command.OutOrStdout())
 	},
 }
 
-func daily(secretsLoader config.Loader, writer io.Writer) error {
+func daily(ctx context.Context, secretsLoader config.Loader, writer io.Writer) error {
 	serviceName := "spotify"
 	replaceRegex := regexp.MustCompile("[^a-zA-Z0-9]")
 	internalServiceName := strings.ToLower(replaceRegex.ReplaceAllString(serviceName, ""))
@@ -43,5 +44,5 @@ func daily(secretsLoader config.Loader, writer io.Writer) error {
 		return fmt.Errorf("not logged in on %s", service.Name())
 	}
 
-	return service.DiscoverDailyPlaylist(writer)
+	return service.DiscoverDailyPlaylist(ctx, writer)
 }
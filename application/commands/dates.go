@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dietrichm/admirer/domain"
+	"github.com/spf13/cobra"
+)
+
+const dateLayout = "2006-01-02"
+
+var (
+	sinceDate    string
+	untilDate    string
+	sinceLastRun bool
+)
+
+// addDateFlags adds the flags selecting loved tracks by the time they were loved.
+func addDateFlags(command *cobra.Command) {
+	command.Flags().StringVar(&sinceDate, "since", "", "Only include tracks loved on or after this date (such as 2024-01-31) or time (such as 2024-01-31T12:00:00Z)")
+	command.Flags().StringVar(&untilDate, "until", "", "Only include tracks loved before this date or time")
+	command.Flags().BoolVar(&sinceLastRun, "since-last-run", false, "Only include tracks loved since the start of the previous run using this flag")
+}
+
+// dateWindow selects loved tracks by the time they were loved.
+type dateWindow struct {
+	since        time.Time
+	until        time.Time
+	sinceLastRun bool
+}
+
+func dateWindowFromFlags() (window dateWindow, err error) {
+	if window.since, err = parseDate(sinceDate); err != nil {
+		return
+	}
+	if window.until, err = parseDate(untilDate); err != nil {
+		return
+	}
+	if !window.since.IsZero() && !window.until.IsZero() && !window.since.Before(window.until) {
+		return window, fmt.Errorf("--since %s is not before --until %s", sinceDate, untilDate)
+	}
+
+	window.sinceLastRun = sinceLastRun
+	return
+}
+
+// parseDate parses a date in local time or a time in RFC 3339 format. An empty value is the zero time.
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if date, err := time.ParseInLocation(dateLayout, value, time.Local); err == nil {
+		return date, nil
+	}
+
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected a date such as 2024-01-31 or a time such as 2024-01-31T12:00:00Z", value)
+	}
+
+	return date, nil
+}
+
+// apply sets the window on the options for reading loved tracks.
+// Using --since-last-run, tracks loved before the start of the last run with the key are skipped as well.
+func (w dateWindow) apply(options domain.LovedTracksOptions, runs domain.RunStore, key string, messages io.Writer) domain.LovedTracksOptions {
+	options.Since = w.since
+	options.Until = w.until
+
+	if !w.sinceLastRun {
+		return options
+	}
+
+	lastRun, exists := runs.LastRun(key)
+	if !exists {
+		fmt.Fprintln(messages, "No previous run found")
+		return options
+	}
+
+	fmt.Fprintf(messages, "Including tracks loved since previous run at %s\n", lastRun.Local().Format(time.RFC3339))
+	if lastRun.After(options.Since) {
+		options.Since = lastRun
+	}
+	return options
+}
+
+// record saves the start of this run, when using --since-last-run.
+func (w dateWindow) record(runs domain.RunStore, key string, startedAt time.Time) error {
+	if !w.sinceLastRun {
+		return nil
+	}
+
+	if err := runs.Record(key, startedAt); err != nil {
+		return fmt.Errorf("failed to save last run: %w", err)
+	}
+	return nil
+}
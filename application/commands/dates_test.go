@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDateFlags(t *testing.T) {
+	setFlags := func(t *testing.T, since string, until string) {
+		t.Cleanup(func() { sinceDate, untilDate = "", "" })
+		sinceDate, untilDate = since, until
+	}
+
+	t.Run("parses dates in local time and times in RFC 3339", func(t *testing.T) {
+		setFlags(t, "2024-01-02", "2024-02-01T12:30:00Z")
+
+		window, err := dateWindowFromFlags()
+
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local), window.since)
+		assert.Equal(t, time.Date(2024, 2, 1, 12, 30, 0, 0, time.UTC), window.until.UTC())
+	})
+
+	t.Run("returns error for invalid date", func(t *testing.T) {
+		setFlags(t, "yesterday", "")
+
+		_, err := dateWindowFromFlags()
+
+		assert.EqualError(t, err, `invalid date "yesterday", expected a date such as 2024-01-31 or a time such as 2024-01-31T12:00:00Z`)
+	})
+
+	t.Run("returns error when since is not before until", func(t *testing.T) {
+		setFlags(t, "2024-02-01", "2024-01-01")
+
+		_, err := dateWindowFromFlags()
+
+		assert.EqualError(t, err, "--since 2024-02-01 is not before --until 2024-01-01")
+	})
+}
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"github.com/dietrichm/admirer/infrastructure/config"
 	"github.com/dietrichm/admirer/infrastructure/services/spotify"
@@ -18,11 +19,11 @@ var dumpCommand = &cobra.Command{
 	Use:   "dump",
 	Short: "Back up your Spotify Discover Weekly playlist for the current week",
 	RunE: func(command *cobra.Command, args []string) error {
-		return dump(config.SecretsLoader, command.OutOrStdout())
+		return dump(command.Context(), config.SecretsLoader, command.OutOrStdout())
 	},
 }
 
-func dump(secretsLoader config.Loader, writer io.Writer) error {
+func dump(ctx context.Context, secretsLoader config.Loader, writer io.Writer) error {
 	serviceName := "spotify"
 	replaceRegex := regexp.MustCompile("[^a-zA-Z0-9]")
 	internalServiceName := strings.ToLower(replaceRegex.ReplaceAllString(serviceName, ""))
@@ -43,5 +44,5 @@ func dump(secretsLoader config.Loader, writer io.Writer) error {
 		return fmt.Errorf("not logged in on %s", service.Name())
 	}
 
-	return service.DumpDiscoverWeeklyTracksToNewPlaylist(writer)
+	return service.DumpDiscoverWeeklyTracksToNewPlaylist(ctx, writer)
 }
@@ -1,17 +1,26 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"strings"
+	"text/template"
+	"time"
 
 	"github.com/dietrichm/admirer/domain"
 	"github.com/dietrichm/admirer/infrastructure/services"
+	"github.com/dietrichm/admirer/infrastructure/state"
 	"github.com/spf13/cobra"
 )
 
+var listFormat string
+
 func init() {
+	listCommand.Flags().StringVar(&listFormat, "format", "", `Go template for each listed track, such as "{{.Artist}}\t{{.Name}}\t{{.LovedAt}}" (defaults to artist and name)`)
 	listCommand.Flags().IntVarP(&limit, "limit", "l", 10, "Limit the number of tracks to be displayed. Specify 0 to output all tracks without limitations. In this case, the default limit for a group of tracks will be 50 (note: important for accurate page counting)")
 	listCommand.Flags().IntVarP(&page, "page", "p", 1, "Page number to start displaying from")
+	addDateFlags(listCommand)
 	rootCommand.AddCommand(listCommand)
 }
 
@@ -20,17 +29,49 @@ var listCommand = &cobra.Command{
 	Short: "List loved tracks on specified service",
 	Args:  cobra.MinimumNArgs(1),
 	RunE: func(command *cobra.Command, args []string) error {
-		return list(services.AvailableServices, limit, page, command.OutOrStdout(), args)
+		window, err := dateWindowFromFlags()
+		if err != nil {
+			return err
+		}
+
+		runs, err := state.LoadRuns()
+		if err != nil {
+			return err
+		}
+
+		options := listOptions{
+			limit:  limit,
+			page:   page,
+			format: listFormat,
+			window: window,
+		}
+
+		output := newPrinter(outputFormat, command.OutOrStdout())
+		return output.finish(list(command.Context(), services.AvailableServices, runs, options, output, args))
 	},
 }
 
-func list(serviceLoader domain.ServiceLoader, limit int, page int, writer io.Writer, args []string) error {
+type listOptions struct {
+	limit  int
+	page   int
+	format string
+	window dateWindow
+}
+
+func list(ctx context.Context, serviceLoader domain.ServiceLoader, runs domain.RunStore, options listOptions, output *printer, args []string) error {
 	serviceName := args[0]
+	startedAt := time.Now()
 
-	continuously := false
-	if limit == 0 {
-		limit = 50
-		continuously = true
+	var trackTemplate *template.Template
+	if options.format != "" {
+		if output.format != outputText {
+			return fmt.Errorf("--format can only be used with text output")
+		}
+
+		var err error
+		if trackTemplate, err = newTrackTemplate(options.format); err != nil {
+			return err
+		}
 	}
 
 	service, err := serviceLoader.ForName(serviceName)
@@ -44,19 +85,55 @@ func list(serviceLoader domain.ServiceLoader, limit int, page int, writer io.Wri
 		return fmt.Errorf("not logged in on %s", service.Name())
 	}
 
-	for ; ; page++ {
-		tracks, err := service.GetLovedTracks(limit, page)
-		if err != nil {
-			return err
-		}
+	name := service.Name()
 
-		for _, track := range tracks {
-			fmt.Fprintln(writer, track.String())
-		}
-		if !continuously || len(tracks) < limit {
-			break
+	// Listed tracks are often processed further, so the window is applied without showing messages.
+	runKey := "list:" + name
+	lovedTracksOptions := options.window.apply(pageOptions(options.limit, options.page), runs, runKey, io.Discard)
+
+	lovedTracks := domain.NewLovedTracks(service, lovedTracksOptions)
+	for lovedTracks.Next(ctx) {
+		for _, track := range lovedTracks.Tracks() {
+			record := trackRecord{Service: name, trackFields: fieldsFromTrack(track)}
+			if trackTemplate == nil {
+				output.print(record)
+				continue
+			}
+
+			formatted := new(strings.Builder)
+			if err := trackTemplate.Execute(formatted, track); err != nil {
+				return fmt.Errorf("failed to format %s: %w", track.String(), err)
+			}
+			output.print(formattedRecord{trackRecord: record, formatted: formatted.String()})
 		}
 	}
 
-	return nil
+	if err := lovedTracks.Err(); err != nil {
+		return err
+	}
+
+	return options.window.record(runs, runKey, startedAt)
+}
+
+// newTrackTemplate parses the template of the --format flag, executed for every domain.Track.
+// Escaped tabs and newlines are replaced, and join concatenates lists such as the artists of a track.
+func newTrackTemplate(format string) (*template.Template, error) {
+	format = strings.NewReplacer(`\t`, "\t", `\n`, "\n").Replace(format)
+
+	trackTemplate, err := template.New("format").Funcs(template.FuncMap{"join": strings.Join}).Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid format: %w", err)
+	}
+
+	return trackTemplate, nil
+}
+
+// formattedRecord is a listed track written using the template of the --format flag.
+type formattedRecord struct {
+	trackRecord
+	formatted string
+}
+
+func (r formattedRecord) text() string {
+	return r.formatted
 }
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"go.uber.org/mock/gomock"
 	"testing"
@@ -26,14 +27,15 @@ func TestList(t *testing.T) {
 		}
 
 		service := domain.NewMockService(ctrl)
+		service.EXPECT().Name().AnyTimes().Return("Foo")
 		service.EXPECT().Authenticated().Return(true)
-		service.EXPECT().GetLovedTracks(5, 1).Return(tracks, nil)
+		service.EXPECT().GetLovedTracks(gomock.Any(), 5, 1).Return(tracks, nil)
 		service.EXPECT().Close()
 
 		serviceLoader := domain.NewMockServiceLoader(ctrl)
 		serviceLoader.EXPECT().ForName("foo").Return(service, nil)
 
-		got, err := executeList(serviceLoader, 5, 1, "foo")
+		got, err := executeList(serviceLoader, 5, 1, "", "foo")
 
 		expected := `Awesome Artist - Blam (Instrumental)
 Foo & Bar - Mr. Testy
@@ -43,6 +45,51 @@ Foo & Bar - Mr. Testy
 		assert.Equal(t, expected, got)
 	})
 
+	t.Run("lists loved tracks using format template", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		tracks := []domain.Track{
+			{
+				Artist:  "Foo",
+				Artists: []string{"Foo", "Bar"},
+				Name:    "Mr. Testy",
+				Album:   "Testing",
+			},
+		}
+
+		service := domain.NewMockService(ctrl)
+		service.EXPECT().Name().AnyTimes().Return("Foo")
+		service.EXPECT().Authenticated().Return(true)
+		service.EXPECT().GetLovedTracks(gomock.Any(), 5, 1).Return(tracks, nil)
+		service.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("foo").Return(service, nil)
+
+		got, err := executeList(serviceLoader, 5, 1, `{{join .Artists ", "}}\t{{.Name}}\t{{.Album}}`, "foo")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Foo, Bar\tMr. Testy\tTesting\n", got)
+	})
+
+	t.Run("returns error for invalid format template", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		output, err := executeList(domain.NewMockServiceLoader(ctrl), 5, 1, "{{.Name", "foo")
+
+		assert.ErrorContains(t, err, "invalid format: template: format:1: unclosed action")
+		assert.Empty(t, output)
+	})
+
+	t.Run("returns error for format template with structured output", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		output := newPrinter(outputJSON, new(bytes.Buffer))
+
+		err := list(context.Background(), domain.NewMockServiceLoader(ctrl), nil, listOptions{limit: 5, page: 1, format: "{{.Name}}"}, output, []string{"foo"})
+
+		assert.EqualError(t, err, "--format can only be used with text output")
+	})
+
 	t.Run("returns error for unknown service", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 
@@ -50,7 +97,7 @@ Foo & Bar - Mr. Testy
 		serviceLoader := domain.NewMockServiceLoader(ctrl)
 		serviceLoader.EXPECT().ForName(gomock.Any()).Return(nil, errors.New(expected))
 
-		output, err := executeList(serviceLoader, 5, 1, "foobar")
+		output, err := executeList(serviceLoader, 5, 1, "", "foobar")
 
 		assert.EqualError(t, err, expected)
 		assert.Empty(t, output)
@@ -67,7 +114,7 @@ Foo & Bar - Mr. Testy
 		serviceLoader := domain.NewMockServiceLoader(ctrl)
 		serviceLoader.EXPECT().ForName("foo").Return(service, nil)
 
-		output, err := executeList(serviceLoader, 3, 1, "foo")
+		output, err := executeList(serviceLoader, 3, 1, "", "foo")
 
 		assert.Error(t, err)
 		assert.Empty(t, output)
@@ -77,22 +124,24 @@ Foo & Bar - Mr. Testy
 		ctrl := gomock.NewController(t)
 
 		service := domain.NewMockService(ctrl)
+		service.EXPECT().Name().AnyTimes().Return("Foo")
 		service.EXPECT().Authenticated().Return(true)
-		service.EXPECT().GetLovedTracks(3, 1).Return(nil, errors.New("load error"))
+		service.EXPECT().GetLovedTracks(gomock.Any(), 3, 1).Return(nil, errors.New("load error"))
 		service.EXPECT().Close()
 
 		serviceLoader := domain.NewMockServiceLoader(ctrl)
 		serviceLoader.EXPECT().ForName("foo").Return(service, nil)
 
-		output, err := executeList(serviceLoader, 3, 1, "foo")
+		output, err := executeList(serviceLoader, 3, 1, "", "foo")
 
 		assert.Error(t, err)
 		assert.Empty(t, output)
 	})
 }
 
-func executeList(serviceLoader domain.ServiceLoader, limit int, page int, args ...string) (string, error) {
+func executeList(serviceLoader domain.ServiceLoader, limit int, page int, format string, args ...string) (string, error) {
 	buffer := new(bytes.Buffer)
-	err := list(serviceLoader, limit, page, buffer, args)
+	output := newPrinter(outputText, buffer)
+	err := output.finish(list(context.Background(), serviceLoader, nil, listOptions{limit: limit, page: page, format: format}, output, args))
 	return buffer.String(), err
 }
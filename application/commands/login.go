@@ -1,8 +1,10 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/dietrichm/admirer/domain"
 	"github.com/dietrichm/admirer/infrastructure/authentication"
@@ -10,20 +12,34 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	callbackPort    int
+	callbackTimeout time.Duration
+	manualLogin     bool
+)
+
 func init() {
+	loginCommand.Flags().IntVar(&callbackPort, "port", authentication.DefaultCallbackPort, "Local port to listen on for the authentication callback")
+	loginCommand.Flags().DurationVar(&callbackTimeout, "timeout", authentication.DefaultCallbackTimeout, "Maximum duration to wait for the authentication callback")
+	loginCommand.Flags().BoolVar(&manualLogin, "manual", false, "Paste the authentication code manually instead of receiving the authentication callback locally")
 	rootCommand.AddCommand(loginCommand)
 }
 
 var loginCommand = &cobra.Command{
-	Use:   "login <service> [oauth-code]",
+	Use:   "login <service> [oauth-code|token|credentials]",
 	Short: "Log in on external service",
 	Args:  cobra.MinimumNArgs(1),
 	RunE: func(command *cobra.Command, args []string) error {
-		return login(services.AvailableServices, authentication.DefaultCallbackProvider, command.OutOrStdout(), args)
+		var callbackProvider authentication.CallbackProvider = authentication.ManualCallbackProvider
+		if !manualLogin {
+			callbackProvider = authentication.NewHTTPCallbackProvider(callbackPort, callbackTimeout)
+		}
+
+		return login(command.Context(), services.AvailableServices, callbackProvider, authentication.TerminalPasswordReader, command.OutOrStdout(), args)
 	},
 }
 
-func login(serviceLoader domain.ServiceLoader, callbackProvider authentication.CallbackProvider, writer io.Writer, args []string) error {
+func login(ctx context.Context, serviceLoader domain.ServiceLoader, callbackProvider authentication.CallbackProvider, passwordReader authentication.PasswordReader, writer io.Writer, args []string) error {
 	serviceName := args[0]
 
 	service, err := serviceLoader.ForName(serviceName)
@@ -32,12 +48,20 @@ func login(serviceLoader domain.ServiceLoader, callbackProvider authentication.C
 	}
 
 	defer service.Close()
-	redirectURL := "https://admirer.test"
+	redirectURL := callbackProvider.RedirectURL()
+
+	if tokenService, ok := service.(domain.TokenService); ok && len(args) < 2 {
+		return fmt.Errorf("please provide your %s user token, found on %s: admirer login %s <token>", service.Name(), tokenService.TokenURL(), serviceName)
+	}
+
+	if credentialsService, ok := service.(domain.CredentialsService); ok && len(args) < 2 {
+		return fmt.Errorf("please provide your %s credentials: admirer login %s %s", service.Name(), serviceName, credentialsService.CredentialsUsage())
+	}
 
 	if len(args) < 2 {
 		fmt.Fprintln(writer, service.Name(), "authentication URL:", service.CreateAuthURL(redirectURL))
 
-		code, err := callbackProvider.ReadCode(service.CodeParam(), writer)
+		code, err := callbackProvider.ReadCode(ctx, service.CodeParam(), writer)
 		if err != nil {
 			return fmt.Errorf("failed reading authentication code: %w", err)
 		}
@@ -45,11 +69,20 @@ func login(serviceLoader domain.ServiceLoader, callbackProvider authentication.C
 		args = append(args, code)
 	}
 
-	if err := service.Authenticate(args[1], redirectURL); err != nil {
+	if passwordService, ok := service.(domain.PasswordService); ok {
+		password, err := passwordReader.ReadPassword(fmt.Sprintf("%s password: ", service.Name()), writer)
+		if err != nil {
+			return fmt.Errorf("failed reading password: %w", err)
+		}
+
+		passwordService.SetPassword(password)
+	}
+
+	if err := service.Authenticate(ctx, args[1], redirectURL); err != nil {
 		return err
 	}
 
-	username, err := service.GetUsername()
+	username, err := service.GetUsername(ctx)
 	if err != nil {
 		return err
 	}
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"go.uber.org/mock/gomock"
 	"testing"
@@ -17,17 +18,18 @@ func TestLogin(t *testing.T) {
 
 		service := domain.NewMockService(ctrl)
 		service.EXPECT().Name().AnyTimes().Return("Service")
-		service.EXPECT().CreateAuthURL("https://admirer.test").Return("https://service.test/auth")
+		service.EXPECT().CreateAuthURL("http://127.0.0.1:8888/callback").Return("https://service.test/auth")
 		service.EXPECT().CodeParam().Return("codeparam")
-		service.EXPECT().Authenticate("authcode", "https://admirer.test")
-		service.EXPECT().GetUsername().Return("Joe", nil)
+		service.EXPECT().Authenticate(gomock.Any(), "authcode", "http://127.0.0.1:8888/callback")
+		service.EXPECT().GetUsername(gomock.Any()).Return("Joe", nil)
 		service.EXPECT().Close()
 
 		serviceLoader := domain.NewMockServiceLoader(ctrl)
 		serviceLoader.EXPECT().ForName("foobar").Return(service, nil)
 
 		callbackProvider := authentication.NewMockCallbackProvider(ctrl)
-		callbackProvider.EXPECT().ReadCode("codeparam", gomock.Any()).Return("authcode", nil)
+		callbackProvider.EXPECT().RedirectURL().Return("http://127.0.0.1:8888/callback")
+		callbackProvider.EXPECT().ReadCode(gomock.Any(), "codeparam", gomock.Any()).Return("authcode", nil)
 
 		got, err := executeLogin(serviceLoader, callbackProvider, "foobar")
 		expected := `Service authentication URL: https://service.test/auth
@@ -42,15 +44,16 @@ Logged in on Service as Joe
 		ctrl := gomock.NewController(t)
 
 		service := domain.NewMockService(ctrl)
-		service.EXPECT().Authenticate("authcode", "https://admirer.test")
+		service.EXPECT().Authenticate(gomock.Any(), "authcode", "https://admirer.test")
 		service.EXPECT().Name().AnyTimes().Return("Service")
-		service.EXPECT().GetUsername().Return("Joe", nil)
+		service.EXPECT().GetUsername(gomock.Any()).Return("Joe", nil)
 		service.EXPECT().Close()
 
 		serviceLoader := domain.NewMockServiceLoader(ctrl)
 		serviceLoader.EXPECT().ForName(gomock.Any()).Return(service, nil)
 
 		callbackProvider := authentication.NewMockCallbackProvider(ctrl)
+		callbackProvider.EXPECT().RedirectURL().Return("https://admirer.test")
 
 		got, err := executeLogin(serviceLoader, callbackProvider, "foobar", "authcode")
 		expected := "Logged in on Service as Joe\n"
@@ -59,6 +62,120 @@ Logged in on Service as Joe
 		assert.Equal(t, expected, got)
 	})
 
+	t.Run("authenticates token service with provided token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		service := domain.NewMockService(ctrl)
+		service.EXPECT().Authenticate(gomock.Any(), "usertoken", "https://admirer.test")
+		service.EXPECT().Name().AnyTimes().Return("Service")
+		service.EXPECT().GetUsername(gomock.Any()).Return("Joe", nil)
+		service.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("foobar").Return(tokenService{service, domain.NewMockTokenService(ctrl)}, nil)
+
+		callbackProvider := authentication.NewMockCallbackProvider(ctrl)
+		callbackProvider.EXPECT().RedirectURL().Return("https://admirer.test")
+
+		got, err := executeLogin(serviceLoader, callbackProvider, "foobar", "usertoken")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Logged in on Service as Joe\n", got)
+	})
+
+	t.Run("returns error when token is missing for token service", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		service := domain.NewMockService(ctrl)
+		service.EXPECT().Name().AnyTimes().Return("Service")
+		service.EXPECT().Close()
+
+		tokenURLService := domain.NewMockTokenService(ctrl)
+		tokenURLService.EXPECT().TokenURL().Return("https://service.test/settings")
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("foobar").Return(tokenService{service, tokenURLService}, nil)
+
+		callbackProvider := authentication.NewMockCallbackProvider(ctrl)
+		callbackProvider.EXPECT().RedirectURL().Return("http://127.0.0.1:8888/callback")
+
+		output, err := executeLogin(serviceLoader, callbackProvider, "foobar")
+
+		assert.EqualError(t, err, "please provide your Service user token, found on https://service.test/settings: admirer login foobar <token>")
+		assert.Empty(t, output)
+	})
+
+	t.Run("returns error when credentials are missing for credentials service", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		service := domain.NewMockService(ctrl)
+		service.EXPECT().Name().AnyTimes().Return("Service")
+		service.EXPECT().Close()
+
+		usageService := domain.NewMockCredentialsService(ctrl)
+		usageService.EXPECT().CredentialsUsage().Return("<user:password>")
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("foobar").Return(credentialsService{service, usageService}, nil)
+
+		callbackProvider := authentication.NewMockCallbackProvider(ctrl)
+		callbackProvider.EXPECT().RedirectURL().Return("http://127.0.0.1:8888/callback")
+
+		output, err := executeLogin(serviceLoader, callbackProvider, "foobar")
+
+		assert.EqualError(t, err, "please provide your Service credentials: admirer login foobar <user:password>")
+		assert.Empty(t, output)
+	})
+
+	t.Run("reads password for password service", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		service := domain.NewMockService(ctrl)
+		service.EXPECT().Name().AnyTimes().Return("Service")
+		service.EXPECT().Authenticate(gomock.Any(), "https://joe@music.test", "https://admirer.test")
+		service.EXPECT().GetUsername(gomock.Any()).Return("joe", nil)
+		service.EXPECT().Close()
+
+		passwordSetter := domain.NewMockPasswordService(ctrl)
+		passwordSetter.EXPECT().SetPassword("sesame")
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("foobar").Return(passwordService{service, passwordSetter}, nil)
+
+		callbackProvider := authentication.NewMockCallbackProvider(ctrl)
+		callbackProvider.EXPECT().RedirectURL().Return("https://admirer.test")
+
+		passwordReader := authentication.NewMockPasswordReader(ctrl)
+		passwordReader.EXPECT().ReadPassword("Service password: ", gomock.Any()).Return("sesame", nil)
+
+		buffer := new(bytes.Buffer)
+		err := login(context.Background(), serviceLoader, callbackProvider, passwordReader, buffer, []string{"foobar", "https://joe@music.test"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Logged in on Service as joe\n", buffer.String())
+	})
+
+	t.Run("returns error when failing to read password", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		service := domain.NewMockService(ctrl)
+		service.EXPECT().Name().AnyTimes().Return("Service")
+		service.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("foobar").Return(passwordService{service, domain.NewMockPasswordService(ctrl)}, nil)
+
+		callbackProvider := authentication.NewMockCallbackProvider(ctrl)
+		callbackProvider.EXPECT().RedirectURL().Return("https://admirer.test")
+
+		passwordReader := authentication.NewMockPasswordReader(ctrl)
+		passwordReader.EXPECT().ReadPassword(gomock.Any(), gomock.Any()).Return("", errors.New("EOF"))
+
+		err := login(context.Background(), serviceLoader, callbackProvider, passwordReader, new(bytes.Buffer), []string{"foobar", "https://joe@music.test"})
+
+		assert.EqualError(t, err, "failed reading password: EOF")
+	})
+
 	t.Run("returns error for unknown service", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 
@@ -87,7 +204,8 @@ Logged in on Service as Joe
 		serviceLoader.EXPECT().ForName("foobar").Return(service, nil)
 
 		callbackProvider := authentication.NewMockCallbackProvider(ctrl)
-		callbackProvider.EXPECT().ReadCode(gomock.Any(), gomock.Any()).Return("", errors.New("read error"))
+		callbackProvider.EXPECT().RedirectURL().Return("http://127.0.0.1:8888/callback")
+		callbackProvider.EXPECT().ReadCode(gomock.Any(), gomock.Any(), gomock.Any()).Return("", errors.New("read error"))
 
 		_, err := executeLogin(serviceLoader, callbackProvider, "foobar")
 
@@ -99,13 +217,14 @@ Logged in on Service as Joe
 
 		expected := "failed authentication"
 		service := domain.NewMockService(ctrl)
-		service.EXPECT().Authenticate(gomock.Any(), gomock.Any()).Return(errors.New(expected))
+		service.EXPECT().Authenticate(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New(expected))
 		service.EXPECT().Close()
 
 		serviceLoader := domain.NewMockServiceLoader(ctrl)
 		serviceLoader.EXPECT().ForName(gomock.Any()).Return(service, nil)
 
 		callbackProvider := authentication.NewMockCallbackProvider(ctrl)
+		callbackProvider.EXPECT().RedirectURL().Return("https://admirer.test")
 
 		output, err := executeLogin(serviceLoader, callbackProvider, "foobar", "authcode")
 
@@ -118,14 +237,15 @@ Logged in on Service as Joe
 
 		expected := "failed username retrieval"
 		service := domain.NewMockService(ctrl)
-		service.EXPECT().Authenticate(gomock.Any(), gomock.Any()).Return(nil)
-		service.EXPECT().GetUsername().Return("", errors.New(expected))
+		service.EXPECT().Authenticate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		service.EXPECT().GetUsername(gomock.Any()).Return("", errors.New(expected))
 		service.EXPECT().Close()
 
 		serviceLoader := domain.NewMockServiceLoader(ctrl)
 		serviceLoader.EXPECT().ForName(gomock.Any()).Return(service, nil)
 
 		callbackProvider := authentication.NewMockCallbackProvider(ctrl)
+		callbackProvider.EXPECT().RedirectURL().Return("https://admirer.test")
 
 		output, err := executeLogin(serviceLoader, callbackProvider, "foobar", "authcode")
 
@@ -136,6 +256,21 @@ Logged in on Service as Joe
 
 func executeLogin(serviceLoader domain.ServiceLoader, callbackProvider authentication.CallbackProvider, args ...string) (string, error) {
 	buffer := new(bytes.Buffer)
-	err := login(serviceLoader, callbackProvider, buffer, args)
+	err := login(context.Background(), serviceLoader, callbackProvider, nil, buffer, args)
 	return buffer.String(), err
 }
+
+type tokenService struct {
+	*domain.MockService
+	*domain.MockTokenService
+}
+
+type credentialsService struct {
+	*domain.MockService
+	*domain.MockCredentialsService
+}
+
+type passwordService struct {
+	*domain.MockService
+	*domain.MockPasswordService
+}
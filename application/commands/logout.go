@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/dietrichm/admirer/domain"
+	"github.com/dietrichm/admirer/infrastructure/config"
+	"github.com/dietrichm/admirer/infrastructure/services"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCommand.AddCommand(logoutCommand)
+}
+
+var logoutCommand = &cobra.Command{
+	Use:   "logout <service>",
+	Short: "Log out from external service by removing its locally stored secrets",
+	Long:  "Log out from external service by removing its locally stored secrets from the keyring.\nAccess granted to Admirer is not revoked on the service itself, which can be done in the account settings of the service.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(command *cobra.Command, args []string) error {
+		return logout(services.AvailableServices, config.SecretsLoader, command.OutOrStdout(), args)
+	},
+}
+
+// logout removes the secrets of the service from the keyring, without revoking them on the service.
+// The service itself is not loaded, as closing it would save its tokens again.
+func logout(serviceLoader domain.ServiceLoader, secretsLoader config.Loader, writer io.Writer, args []string) error {
+	serviceName := args[0]
+	internalServiceName := services.InternalName(serviceName)
+
+	if !containsName(serviceLoader.Names(), internalServiceName) {
+		return fmt.Errorf("unknown service %q", serviceName)
+	}
+
+	secrets, err := secretsLoader.Load("secrets-" + internalServiceName)
+	if err != nil {
+		return err
+	}
+
+	keys, err := secrets.Keys()
+	if err != nil {
+		return fmt.Errorf("failed to log out from %s: %w", internalServiceName, err)
+	}
+
+	if len(keys) == 0 {
+		fmt.Fprintln(writer, "Not logged in on", internalServiceName)
+		return nil
+	}
+
+	for _, key := range keys {
+		secrets.Remove(key)
+	}
+
+	if err := secrets.Save(); err != nil {
+		return fmt.Errorf("failed to log out from %s: %w", internalServiceName, err)
+	}
+
+	fmt.Fprintf(writer, "Logged out from %s, removed %d stored secrets\n", internalServiceName, len(keys))
+	return nil
+}
+
+func containsName(names []string, name string) bool {
+	for _, candidate := range names {
+		if candidate == name {
+			return true
+		}
+	}
+	return false
+}
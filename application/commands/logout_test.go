@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"testing"
+
+	"github.com/dietrichm/admirer/domain"
+	"github.com/dietrichm/admirer/infrastructure/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogout(t *testing.T) {
+	t.Run("removes stored secrets of service", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().Names().Return([]string{"lastfm", "spotify"})
+
+		secrets := config.NewMockConfig(ctrl)
+		secrets.EXPECT().Keys().Return([]string{"access_token", "refresh_token"}, nil)
+		secrets.EXPECT().Remove("access_token")
+		secrets.EXPECT().Remove("refresh_token")
+		secrets.EXPECT().Save()
+
+		secretsLoader := config.NewMockLoader(ctrl)
+		secretsLoader.EXPECT().Load("secrets-spotify").Return(secrets, nil)
+
+		got, err := executeLogout(serviceLoader, secretsLoader, "Spotify")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Logged out from spotify, removed 2 stored secrets\n", got)
+	})
+
+	t.Run("reports service without stored secrets", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().Names().Return([]string{"lastfm"})
+
+		secrets := config.NewMockConfig(ctrl)
+		secrets.EXPECT().Keys().Return(nil, nil)
+
+		secretsLoader := config.NewMockLoader(ctrl)
+		secretsLoader.EXPECT().Load("secrets-lastfm").Return(secrets, nil)
+
+		got, err := executeLogout(serviceLoader, secretsLoader, "last.fm")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Not logged in on lastfm\n", got)
+	})
+
+	t.Run("returns error for unknown service", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().Names().Return([]string{"spotify"})
+
+		got, err := executeLogout(serviceLoader, config.NewMockLoader(ctrl), "foobar")
+
+		assert.EqualError(t, err, `unknown service "foobar"`)
+		assert.Empty(t, got)
+	})
+
+	t.Run("returns error when failing to load secrets", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().Names().Return([]string{"spotify"})
+
+		secretsLoader := config.NewMockLoader(ctrl)
+		secretsLoader.EXPECT().Load("secrets-spotify").Return(nil, errors.New("keyring error"))
+
+		_, err := executeLogout(serviceLoader, secretsLoader, "spotify")
+
+		assert.EqualError(t, err, "keyring error")
+	})
+
+	t.Run("returns error when failing to remove secrets", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().Names().Return([]string{"spotify"})
+
+		secrets := config.NewMockConfig(ctrl)
+		secrets.EXPECT().Keys().Return([]string{"access_token"}, nil)
+		secrets.EXPECT().Remove("access_token")
+		secrets.EXPECT().Save().Return(errors.New("write error"))
+
+		secretsLoader := config.NewMockLoader(ctrl)
+		secretsLoader.EXPECT().Load("secrets-spotify").Return(secrets, nil)
+
+		got, err := executeLogout(serviceLoader, secretsLoader, "spotify")
+
+		assert.EqualError(t, err, "failed to log out from spotify: write error")
+		assert.Empty(t, got)
+	})
+}
+
+func executeLogout(serviceLoader domain.ServiceLoader, secretsLoader config.Loader, args ...string) (string, error) {
+	buffer := new(bytes.Buffer)
+	err := logout(serviceLoader, secretsLoader, buffer, args)
+	return buffer.String(), err
+}
@@ -0,0 +1,307 @@
+package commands
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/dietrichm/admirer/domain"
+	"github.com/dietrichm/admirer/infrastructure/services/file"
+	"github.com/spf13/cobra"
+)
+
+const (
+	outputText  = "text"
+	outputJSON  = "json"
+	outputJSONL = "jsonl"
+	outputCSV   = "csv"
+	outputTSV   = "tsv"
+)
+
+var outputFormat string
+
+func init() {
+	rootCommand.PersistentFlags().StringVarP(&outputFormat, "output", "o", outputText, `Output format: "text", "json", "jsonl", "csv" or "tsv"`)
+	rootCommand.PersistentPreRunE = func(command *cobra.Command, args []string) error {
+		switch outputFormat {
+		case outputText, outputJSON, outputJSONL, outputCSV, outputTSV:
+			return nil
+		}
+		return fmt.Errorf("unknown output format %q, expected %q, %q, %q, %q or %q", outputFormat, outputText, outputJSON, outputJSONL, outputCSV, outputTSV)
+	}
+}
+
+// record is a line of command output, written as text or as a structured record in the other formats.
+type record interface {
+	text() string
+	columns() []string
+	values() []string
+}
+
+// printer writes the records of a command in the selected output format.
+// JSON records are collected and written as one array when finishing, while other formats are written immediately.
+type printer struct {
+	format    string
+	writer    io.Writer
+	records   []record
+	csvWriter *csv.Writer
+	err       error
+}
+
+func newPrinter(format string, writer io.Writer) *printer {
+	output := &printer{format: format, writer: writer}
+
+	if format == outputCSV || format == outputTSV {
+		output.csvWriter = csv.NewWriter(writer)
+		if format == outputTSV {
+			output.csvWriter.Comma = '\t'
+		}
+	}
+
+	return output
+}
+
+// print writes the record. Write errors are returned when finishing.
+func (p *printer) print(r record) {
+	if p.err != nil {
+		return
+	}
+
+	switch p.format {
+	case outputJSON:
+		p.records = append(p.records, r)
+	case outputJSONL:
+		p.err = json.NewEncoder(p.writer).Encode(r)
+	case outputCSV, outputTSV:
+		if p.records == nil {
+			p.records = []record{r}
+			p.err = p.csvWriter.Write(r.columns())
+		}
+		if p.err == nil {
+			p.err = p.csvWriter.Write(r.values())
+		}
+	default:
+		_, p.err = fmt.Fprintln(p.writer, r.text())
+	}
+}
+
+// messages returns the writer for informational text, which is only shown in text output.
+func (p *printer) messages() io.Writer {
+	if p.format == outputText {
+		return p.writer
+	}
+	return io.Discard
+}
+
+// finish writes any pending records and returns the error of the command, or otherwise the first write error.
+func (p *printer) finish(err error) error {
+	if p.err == nil {
+		switch p.format {
+		case outputJSON:
+			if p.records == nil {
+				p.records = []record{}
+			}
+			encoder := json.NewEncoder(p.writer)
+			encoder.SetIndent("", "  ")
+			p.err = encoder.Encode(p.records)
+		case outputCSV, outputTSV:
+			p.csvWriter.Flush()
+			p.err = p.csvWriter.Error()
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+	return p.err
+}
+
+// trackFields are the fields of a file.Record, which are always included in structured records.
+type trackFields struct {
+	Artist     string     `json:"artist"`
+	Name       string     `json:"name"`
+	Album      string     `json:"album"`
+	Artists    []string   `json:"artists"`
+	DurationMs int64      `json:"duration_ms"`
+	ISRC       string     `json:"isrc"`
+	MBID       string     `json:"mbid"`
+	ArtistMBID string     `json:"artist_mbid"`
+	ID         string     `json:"id"`
+	URI        string     `json:"uri"`
+	LovedAt    *time.Time `json:"loved_at"`
+}
+
+// fieldsFromTrack converts a track to fields, in the format of the file service.
+func fieldsFromTrack(track domain.Track) trackFields {
+	return trackFields(file.RecordFromTrack(track))
+}
+
+func (f trackFields) String() string {
+	return fmt.Sprintf("%s - %s", f.Artist, f.Name)
+}
+
+func (f trackFields) values() []string {
+	return file.Record(f).Values()
+}
+
+// trackRecord is a loved track on a service.
+type trackRecord struct {
+	Service string `json:"service"`
+	trackFields
+}
+
+func (r trackRecord) text() string {
+	return r.trackFields.String()
+}
+
+func (r trackRecord) columns() []string {
+	return append([]string{"service"}, file.Columns...)
+}
+
+func (r trackRecord) values() []string {
+	return append([]string{r.Service}, r.trackFields.values()...)
+}
+
+// statusRecord is the authentication status of a service.
+type statusRecord struct {
+	Service       string `json:"service"`
+	Authenticated bool   `json:"authenticated"`
+	Username      string `json:"username"`
+	// Error is the reason why a service which could not be loaded is not configured.
+	Error string `json:"error,omitempty"`
+}
+
+func (r statusRecord) text() string {
+	if r.Error != "" {
+		return r.Service + "\n\tNot configured: " + r.Error
+	}
+	if !r.Authenticated {
+		return r.Service + "\n\tNot logged in"
+	}
+	return r.Service + "\n\tAuthenticated as " + r.Username
+}
+
+func (r statusRecord) columns() []string {
+	return []string{"service", "authenticated", "username", "error"}
+}
+
+func (r statusRecord) values() []string {
+	return []string{r.Service, strconv.FormatBool(r.Authenticated), r.Username, r.Error}
+}
+
+// Outcomes of syncing a track.
+const (
+	outcomeSynced           = "synced"
+	outcomeLoved            = "loved"
+	outcomeWouldLove        = "would_love"
+	outcomeAlreadyLoved     = "already_loved"
+	outcomeNotFound         = "not_found"
+	outcomeReview           = "review"
+	outcomeFailed           = "failed"
+	outcomePreviouslySynced = "previously_synced"
+	outcomeUnloved          = "unloved"
+	outcomeWouldUnlove      = "would_unlove"
+	outcomeConflict         = "conflict"
+)
+
+// syncRecord is the outcome of syncing a track from the source to the target service.
+// The match fields describe the track found on the target service, or the best candidate for tracks to review.
+type syncRecord struct {
+	Source  string `json:"source"`
+	Target  string `json:"target"`
+	Outcome string `json:"outcome"`
+	trackFields
+	MatchID     string  `json:"match_id"`
+	MatchArtist string  `json:"match_artist"`
+	MatchName   string  `json:"match_name"`
+	MatchMethod string  `json:"match_method"`
+	Confidence  float64 `json:"confidence"`
+	Error       string  `json:"error"`
+}
+
+func newSyncRecord(source string, target string, outcome string, track domain.Track) syncRecord {
+	return syncRecord{
+		Source:      source,
+		Target:      target,
+		Outcome:     outcome,
+		trackFields: fieldsFromTrack(track),
+	}
+}
+
+func (r syncRecord) withMatch(match domain.Match) syncRecord {
+	r.MatchID = match.Track.ID
+	r.MatchArtist = match.Track.Artist
+	r.MatchName = match.Track.Name
+	r.MatchMethod = string(match.Method)
+	r.Confidence = match.Confidence
+	return r
+}
+
+func (r syncRecord) withError(err error) syncRecord {
+	r.Error = err.Error()
+	return r
+}
+
+func (r syncRecord) text() string {
+	label, detail := r.describe()
+	return label + ": " + detail
+}
+
+// describe returns the label of the outcome and the details following it in text output.
+func (r syncRecord) describe() (label string, detail string) {
+	track := r.trackFields.String()
+
+	switch r.Outcome {
+	case outcomeSynced:
+		return "Synced", fmt.Sprintf("%s (%s)", track, r.MatchMethod)
+	case outcomeLoved:
+		return "Loved", fmt.Sprintf("%s (%s)", track, r.MatchMethod)
+	case outcomeWouldLove:
+		return "Would love", fmt.Sprintf("%s (%s)", track, r.MatchMethod)
+	case outcomeAlreadyLoved:
+		return "Already loved", track
+	case outcomeNotFound:
+		return "Not found", track
+	case outcomeReview:
+		return "Review", fmt.Sprintf("%s (best match %s - %s with confidence %.2f)", track, r.MatchArtist, r.MatchName, r.Confidence)
+	case outcomeFailed:
+		return "Failed", fmt.Sprintf("%s (%s)", track, r.Error)
+	case outcomePreviouslySynced:
+		return "Reached previously synced track", track
+	case outcomeUnloved:
+		return "Unloved", track
+	case outcomeWouldUnlove:
+		return "Would unlove", track
+	case outcomeConflict:
+		return "Conflict", fmt.Sprintf("%s (not synced from %s, keeping it loved)", track, r.Source)
+	}
+	return r.Outcome, track
+}
+
+func (r syncRecord) columns() []string {
+	columns := append([]string{"source", "target", "outcome"}, file.Columns...)
+	return append(columns, "match_id", "match_artist", "match_name", "match_method", "confidence", "error")
+}
+
+func (r syncRecord) values() []string {
+	var confidence string
+	if r.Confidence > 0 {
+		confidence = strconv.FormatFloat(r.Confidence, 'f', -1, 64)
+	}
+
+	values := append([]string{r.Source, r.Target, r.Outcome}, r.trackFields.values()...)
+	return append(values, r.MatchID, r.MatchArtist, r.MatchName, r.MatchMethod, confidence, r.Error)
+}
+
+// reconcileRecord is the outcome of loving a track missing on the target service while reconciling.
+type reconcileRecord struct {
+	syncRecord
+}
+
+func (r reconcileRecord) text() string {
+	label, detail := r.describe()
+	return label + " on " + r.Target + ": " + detail
+}
@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dietrichm/admirer/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrinter(t *testing.T) {
+	track := domain.Track{
+		ID:       "1",
+		Artist:   "Foo",
+		Artists:  []string{"Foo", "Bar"},
+		Name:     "Mr. Testy - Remastered",
+		Duration: 3 * time.Minute,
+		LovedAt:  time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	match := domain.Match{Track: domain.Track{ID: "target", Artist: "Foo", Name: "Mr. Testy"}, Confidence: 0.75, Method: domain.MatchBySearch}
+
+	write := func(format string, records ...record) (string, error) {
+		buffer := new(bytes.Buffer)
+		output := newPrinter(format, buffer)
+		for _, record := range records {
+			output.print(record)
+		}
+		fmt.Fprintln(output.messages(), "Summary")
+		err := output.finish(nil)
+		return buffer.String(), err
+	}
+
+	t.Run("writes records as text", func(t *testing.T) {
+		got, err := write(outputText,
+			trackRecord{Service: "Foo", trackFields: fieldsFromTrack(track)},
+			newSyncRecord("Source", "Target", outcomeReview, track).withMatch(match),
+			reconcileRecord{newSyncRecord("Source", "Target", outcomeFailed, track).withError(errors.New("love error"))},
+		)
+
+		expected := `Foo - Mr. Testy - Remastered
+Review: Foo - Mr. Testy - Remastered (best match Foo - Mr. Testy with confidence 0.75)
+Failed on Target: Foo - Mr. Testy - Remastered (love error)
+Summary
+`
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got)
+	})
+
+	t.Run("writes records as JSON array", func(t *testing.T) {
+		got, err := write(outputJSON, statusRecord{Service: "Foo", Authenticated: true, Username: "user303"}, statusRecord{Service: "Bar"})
+
+		expected := `[
+  {
+    "service": "Foo",
+    "authenticated": true,
+    "username": "user303"
+  },
+  {
+    "service": "Bar",
+    "authenticated": false,
+    "username": ""
+  }
+]
+`
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got)
+	})
+
+	t.Run("writes empty JSON array without records", func(t *testing.T) {
+		got, err := write(outputJSON)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "[]\n", got)
+	})
+
+	t.Run("writes records as JSON lines", func(t *testing.T) {
+		got, err := write(outputJSONL, newSyncRecord("Source", "Target", outcomeSynced, track).withMatch(match))
+
+		expected := `{"source":"Source","target":"Target","outcome":"synced","artist":"Foo","name":"Mr. Testy - Remastered","album":"","artists":["Foo","Bar"],"duration_ms":180000,"isrc":"","mbid":"","artist_mbid":"","id":"1","uri":"","loved_at":"2020-01-02T03:04:05Z","match_id":"target","match_artist":"Foo","match_name":"Mr. Testy","match_method":"search","confidence":0.75,"error":""}
+`
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got)
+	})
+
+	t.Run("writes records as CSV with header", func(t *testing.T) {
+		got, err := write(outputCSV, trackRecord{Service: "Foo", trackFields: fieldsFromTrack(track)}, trackRecord{Service: "Foo", trackFields: fieldsFromTrack(domain.Track{Artist: "Bar", Name: "Blam, Again"})})
+
+		expected := `service,artist,name,album,artists,duration_ms,isrc,mbid,artist_mbid,id,uri,loved_at
+Foo,Foo,Mr. Testy - Remastered,,Foo; Bar,180000,,,,1,,2020-01-02T03:04:05Z
+Foo,Bar,"Blam, Again",,,,,,,,,
+`
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got)
+	})
+
+	t.Run("writes records as TSV with header", func(t *testing.T) {
+		got, err := write(outputTSV, statusRecord{Service: "Foo", Authenticated: true, Username: "user303"})
+
+		expected := "service\tauthenticated\tusername\terror\nFoo\ttrue\tuser303\t\n"
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got)
+	})
+
+	t.Run("returns command error before write error", func(t *testing.T) {
+		output := newPrinter(outputJSON, new(bytes.Buffer))
+		commandError := errors.New("command error")
+
+		err := output.finish(commandError)
+
+		assert.Equal(t, commandError, err)
+	})
+}
+
+func TestOutputFlag(t *testing.T) {
+	t.Run("returns error for unknown output format", func(t *testing.T) {
+		t.Cleanup(func() { outputFormat = outputText })
+		outputFormat = "xml"
+
+		err := rootCommand.PersistentPreRunE(rootCommand, nil)
+
+		assert.EqualError(t, err, `unknown output format "xml", expected "text", "json", "jsonl", "csv" or "tsv"`)
+	})
+}
@@ -0,0 +1,298 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/dietrichm/admirer/domain"
+	"github.com/dietrichm/admirer/infrastructure/services"
+	"github.com/dietrichm/admirer/infrastructure/state"
+	"github.com/spf13/cobra"
+)
+
+const (
+	policyUnion      = "union"
+	policySourceWins = "source-wins"
+)
+
+var (
+	reconcilePolicy string
+	dryRunReconcile bool
+)
+
+func init() {
+	reconcileCommand.Flags().StringVar(&reconcilePolicy, "policy", policyUnion, `How to merge loved tracks: "union" loves missing tracks on both services, "source-wins" loves tracks of the first service on the second and unloves tracks on the second which were synced from the first but are no longer loved there`)
+	reconcileCommand.Flags().BoolVar(&dryRunReconcile, "dry-run", false, "Show which tracks would be loved or unloved without changing anything")
+	reconcileCommand.Flags().Float64Var(&minConfidence, "min-confidence", domain.DefaultThreshold, "Minimum confidence (0 to 1) for accepting a track found on the other service")
+	rootCommand.AddCommand(reconcileCommand)
+}
+
+var reconcileCommand = &cobra.Command{
+	Use:   "reconcile <service> <service>",
+	Short: "Love the tracks missing on either of two services",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(command *cobra.Command, args []string) error {
+		ledger, err := state.LoadLedger()
+		if err != nil {
+			return err
+		}
+
+		options := reconcileOptions{
+			policy:        reconcilePolicy,
+			dryRun:        dryRunReconcile,
+			minConfidence: minConfidence,
+		}
+
+		output := newPrinter(outputFormat, command.OutOrStdout())
+		return output.finish(reconcile(command.Context(), services.AvailableServices, ledger, options, output, args))
+	},
+}
+
+type reconcileOptions struct {
+	policy        string
+	dryRun        bool
+	minConfidence float64
+}
+
+type reconcileRow struct {
+	service       string
+	loved         int
+	missing       int
+	added         int
+	notFound      int
+	lowConfidence int
+	unloved       int
+	conflicts     int
+	failed        int
+}
+
+func reconcile(ctx context.Context, serviceLoader domain.ServiceLoader, ledger domain.Ledger, options reconcileOptions, output *printer, args []string) error {
+	if options.policy != policyUnion && options.policy != policySourceWins {
+		return fmt.Errorf("unknown policy %q, expected %q or %q", options.policy, policyUnion, policySourceWins)
+	}
+
+	firstService, err := serviceLoader.ForName(args[0])
+	if err != nil {
+		return err
+	}
+
+	secondService, err := serviceLoader.ForName(args[1])
+	if err != nil {
+		return err
+	}
+
+	defer firstService.Close()
+	defer secondService.Close()
+
+	for _, service := range []domain.Service{firstService, secondService} {
+		if !service.Authenticated() {
+			return fmt.Errorf("not logged in on %s", service.Name())
+		}
+
+		if matchingService, ok := service.(domain.MatchingService); ok {
+			matchingService.SetMatcher(domain.NewMatcher(options.minConfidence))
+		}
+	}
+
+	firstTracks, err := domain.NewLovedTracks(firstService, domain.LovedTracksOptions{}).All(ctx)
+	if err != nil {
+		return err
+	}
+
+	secondTracks, err := domain.NewLovedTracks(secondService, domain.LovedTracksOptions{}).All(ctx)
+	if err != nil {
+		return err
+	}
+
+	firstMissing := missingTracks(secondTracks, firstTracks)
+	secondMissing := missingTracks(firstTracks, secondTracks)
+
+	firstRow := &reconcileRow{service: firstService.Name(), loved: len(firstTracks), missing: len(firstMissing)}
+	secondRow := &reconcileRow{service: secondService.Name(), loved: len(secondTracks), missing: len(secondMissing)}
+
+	reconcileTracks(ctx, firstService, secondService, ledger, options.dryRun, secondMissing, secondRow, output)
+	if options.policy == policyUnion {
+		reconcileTracks(ctx, secondService, firstService, ledger, options.dryRun, firstMissing, firstRow, output)
+	} else {
+		removeTracks(ctx, firstService, secondService, ledger, options.dryRun, firstMissing, secondRow, output)
+	}
+
+	if !options.dryRun {
+		if err := ledger.Save(); err != nil {
+			return fmt.Errorf("failed to save sync ledger: %w", err)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("reconcile interrupted: %w", err)
+	}
+
+	addedHeader := "Added"
+	unlovedHeader := "Unloved"
+	if options.dryRun {
+		addedHeader = "To add"
+		unlovedHeader = "To unlove"
+	}
+
+	fmt.Fprintln(output.messages())
+	table := tabwriter.NewWriter(output.messages(), 0, 4, 2, ' ', 0)
+	if options.policy == policyUnion {
+		fmt.Fprintf(table, "Service\tLoved\tMissing\t%s\tNot found\tReview\tFailed\n", addedHeader)
+		for _, row := range []*reconcileRow{firstRow, secondRow} {
+			fmt.Fprintf(table, "%s\t%d\t%d\t%d\t%d\t%d\t%d\n", row.service, row.loved, row.missing, row.added, row.notFound, row.lowConfidence, row.failed)
+		}
+	} else {
+		fmt.Fprintf(table, "Service\tLoved\tMissing\t%s\tNot found\tReview\t%s\tConflicts\tFailed\n", addedHeader, unlovedHeader)
+		for _, row := range []*reconcileRow{firstRow, secondRow} {
+			fmt.Fprintf(table, "%s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\n", row.service, row.loved, row.missing, row.added, row.notFound, row.lowConfidence, row.unloved, row.conflicts, row.failed)
+		}
+	}
+	table.Flush()
+
+	if failed := firstRow.failed + secondRow.failed; failed > 0 {
+		return fmt.Errorf("failed to reconcile %d tracks", failed)
+	}
+
+	return nil
+}
+
+func reconcileTracks(ctx context.Context, sourceService domain.Service, targetService domain.Service, ledger domain.Ledger, dryRun bool, tracks []domain.Track, row *reconcileRow, output *printer) {
+	source := sourceService.Name()
+	target := targetService.Name()
+
+	results := findTracks(ctx, targetService, tracks, 1)
+	if !dryRun {
+		loveTracks(ctx, targetService, results)
+	}
+
+	for _, result := range results {
+		track := result.track
+
+		if result.err != nil && ctx.Err() != nil {
+			continue
+		}
+
+		var lowConfidence *domain.LowConfidenceError
+		if errors.As(result.err, &lowConfidence) {
+			row.lowConfidence++
+			output.print(reconcileRecord{newSyncRecord(source, target, outcomeReview, track).withMatch(lowConfidence.Match)})
+			continue
+		}
+		if errors.Is(result.err, domain.ErrTrackNotFound) {
+			row.notFound++
+			output.print(reconcileRecord{newSyncRecord(source, target, outcomeNotFound, track)})
+			continue
+		}
+		if result.err != nil {
+			row.failed++
+			output.print(reconcileRecord{newSyncRecord(source, target, outcomeFailed, track).withMatch(result.match).withError(result.err)})
+			continue
+		}
+
+		row.added++
+		if dryRun {
+			output.print(reconcileRecord{newSyncRecord(source, target, outcomeWouldLove, track).withMatch(result.match)})
+			continue
+		}
+
+		ledger.Record(source, target, track, result.match.Track.ID)
+		output.print(reconcileRecord{newSyncRecord(source, target, outcomeLoved, track).withMatch(result.match)})
+	}
+}
+
+// removeTracks unloves the tracks only loved on the target service which were synced from the source service according to the ledger.
+// Other tracks only loved on the target service were not loved through admirer and are reported as conflicts, leaving them loved.
+func removeTracks(ctx context.Context, sourceService domain.Service, targetService domain.Service, ledger domain.Ledger, dryRun bool, tracks []domain.Track, row *reconcileRow, output *printer) {
+	source := sourceService.Name()
+	target := targetService.Name()
+	entries := ledger.Entries(source, target)
+
+	for _, track := range tracks {
+		if ctx.Err() != nil {
+			return
+		}
+
+		entry, synced := syncedEntry(entries, track)
+		if !synced {
+			row.conflicts++
+			output.print(reconcileRecord{newSyncRecord(source, target, outcomeConflict, track)})
+			continue
+		}
+
+		if dryRun {
+			row.unloved++
+			output.print(reconcileRecord{newSyncRecord(source, target, outcomeWouldUnlove, track)})
+			continue
+		}
+
+		if err := targetService.UnloveTrack(ctx, track); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			row.failed++
+			output.print(reconcileRecord{newSyncRecord(source, target, outcomeFailed, track).withError(err)})
+			continue
+		}
+
+		row.unloved++
+		ledger.Remove(source, target, entry.Track)
+		output.print(reconcileRecord{newSyncRecord(source, target, outcomeUnloved, track)})
+	}
+}
+
+// syncedEntry returns the ledger entry of the track on the target service, matching it by its ID on the target service or its keys.
+// Tracks which were already loved on the target service before syncing were not loved by admirer, so they have no synced entry.
+func syncedEntry(entries []domain.LedgerEntry, track domain.Track) (domain.LedgerEntry, bool) {
+	for _, entry := range entries {
+		if track.ID != "" && entry.TargetID == track.ID {
+			return entry, !entry.Preexisting
+		}
+	}
+	for _, entry := range entries {
+		if trackKeys([]domain.Track{entry.Track}).contains(track) {
+			return entry, !entry.Preexisting
+		}
+	}
+	return domain.LedgerEntry{}, false
+}
+
+// missingTracks returns the tracks which do not share a key with any of the existing tracks.
+func missingTracks(tracks []domain.Track, existing []domain.Track) (missing []domain.Track) {
+	keys := trackKeys(existing)
+	for _, track := range tracks {
+		if !keys.contains(track) {
+			missing = append(missing, track)
+		}
+	}
+	return
+}
+
+// keySet holds the keys and IDs identifying a set of tracks.
+type keySet map[string]bool
+
+func trackKeys(tracks []domain.Track) keySet {
+	keys := keySet{}
+	for _, track := range tracks {
+		if track.ID != "" {
+			keys["id:"+track.ID] = true
+		}
+		for _, key := range domain.TrackKeys(track) {
+			keys[key] = true
+		}
+	}
+	return keys
+}
+
+func (k keySet) contains(track domain.Track) bool {
+	if track.ID != "" && k["id:"+track.ID] {
+		return true
+	}
+	for _, key := range domain.TrackKeys(track) {
+		if k[key] {
+			return true
+		}
+	}
+	return false
+}
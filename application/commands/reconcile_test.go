@@ -0,0 +1,287 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"testing"
+
+	"github.com/dietrichm/admirer/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconcile(t *testing.T) {
+	shared := domain.Track{
+		Artist: "Foo & Bar",
+		Name:   "Mr. Testy",
+	}
+	onlyFirst := domain.Track{
+		Artist: "Awesome Artist",
+		Name:   "Blam (Instrumental)",
+	}
+	onlySecond := domain.Track{
+		Artist: "Lonely",
+		Name:   "Only Here",
+	}
+
+	t.Run("loves missing tracks on both services", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		firstService := domain.NewMockService(ctrl)
+		firstService.EXPECT().Name().AnyTimes().Return("First")
+		firstService.EXPECT().Authenticated().Return(true)
+		firstService.EXPECT().GetLovedTracks(gomock.Any(), 50, 1).Return([]domain.Track{shared, onlyFirst}, nil)
+		firstService.EXPECT().FindTrack(gomock.Any(), onlySecond).Return(domain.Match{Track: domain.Track{ID: "firstID"}, Method: domain.MatchByName}, nil)
+		firstService.EXPECT().LoveTrack(gomock.Any(), domain.Track{ID: "firstID"})
+		firstService.EXPECT().Close()
+
+		secondService := domain.NewMockService(ctrl)
+		secondService.EXPECT().Name().AnyTimes().Return("Second")
+		secondService.EXPECT().Authenticated().Return(true)
+		secondService.EXPECT().GetLovedTracks(gomock.Any(), 50, 1).Return([]domain.Track{{Artist: "Foo", Name: "Mr Testy - Remastered 2011"}, onlySecond}, nil)
+		secondService.EXPECT().FindTrack(gomock.Any(), onlyFirst).Return(domain.Match{Track: domain.Track{ID: "secondID"}, Method: domain.MatchBySearch}, nil)
+		secondService.EXPECT().LoveTrack(gomock.Any(), domain.Track{ID: "secondID"})
+		secondService.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("first").Return(firstService, nil)
+		serviceLoader.EXPECT().ForName("second").Return(secondService, nil)
+
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Record("First", "Second", onlyFirst, "secondID")
+		ledger.EXPECT().Record("Second", "First", onlySecond, "firstID")
+		ledger.EXPECT().Save()
+
+		got, err := executeReconcile(serviceLoader, ledger, reconcileOptions{policy: policyUnion}, "first", "second")
+
+		expected := `Loved on Second: Awesome Artist - Blam (Instrumental) (search)
+Loved on First: Lonely - Only Here (name)
+
+Service  Loved  Missing  Added  Not found  Review  Failed
+First    2      1        1      0          0       0
+Second   2      1        1      0          0       0
+`
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got)
+	})
+
+	t.Run("unloves tracks synced from first service on second service when source wins", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		synced := domain.Track{ID: "syncedID", Artist: "Previously", Name: "Synced"}
+
+		firstService := domain.NewMockService(ctrl)
+		firstService.EXPECT().Name().AnyTimes().Return("First")
+		firstService.EXPECT().Authenticated().Return(true)
+		firstService.EXPECT().GetLovedTracks(gomock.Any(), 50, 1).Return([]domain.Track{onlyFirst}, nil)
+		firstService.EXPECT().Close()
+
+		secondService := domain.NewMockService(ctrl)
+		secondService.EXPECT().Name().AnyTimes().Return("Second")
+		secondService.EXPECT().Authenticated().Return(true)
+		secondService.EXPECT().GetLovedTracks(gomock.Any(), 50, 1).Return([]domain.Track{onlySecond, synced}, nil)
+		secondService.EXPECT().FindTrack(gomock.Any(), onlyFirst).Return(domain.Match{}, domain.ErrTrackNotFound)
+		secondService.EXPECT().UnloveTrack(gomock.Any(), synced)
+		secondService.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("first").Return(firstService, nil)
+		serviceLoader.EXPECT().ForName("second").Return(secondService, nil)
+
+		syncedSource := domain.Track{Artist: "Previously", Name: "Synced (Remastered)"}
+
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Entries("First", "Second").Return([]domain.LedgerEntry{{Track: syncedSource, TargetID: "syncedID"}})
+		ledger.EXPECT().Remove("First", "Second", syncedSource)
+		ledger.EXPECT().Save()
+
+		got, err := executeReconcile(serviceLoader, ledger, reconcileOptions{policy: policySourceWins}, "first", "second")
+
+		expected := `Not found on Second: Awesome Artist - Blam (Instrumental)
+Conflict on Second: Lonely - Only Here (not synced from First, keeping it loved)
+Unloved on Second: Previously - Synced
+
+Service  Loved  Missing  Added  Not found  Review  Unloved  Conflicts  Failed
+First    1      2        0      0          0       0        0          0
+Second   2      1        0      1          0       1        1          0
+`
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got)
+	})
+
+	t.Run("does not unlove tracks on second service in dry run when source wins", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		synced := domain.Track{Artist: "Previously", Name: "Synced"}
+
+		firstService := domain.NewMockService(ctrl)
+		firstService.EXPECT().Name().AnyTimes().Return("First")
+		firstService.EXPECT().Authenticated().Return(true)
+		firstService.EXPECT().GetLovedTracks(gomock.Any(), 50, 1).Return(nil, nil)
+		firstService.EXPECT().Close()
+
+		secondService := domain.NewMockService(ctrl)
+		secondService.EXPECT().Name().AnyTimes().Return("Second")
+		secondService.EXPECT().Authenticated().Return(true)
+		secondService.EXPECT().GetLovedTracks(gomock.Any(), 50, 1).Return([]domain.Track{synced}, nil)
+		secondService.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("first").Return(firstService, nil)
+		serviceLoader.EXPECT().ForName("second").Return(secondService, nil)
+
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Entries("First", "Second").Return([]domain.LedgerEntry{{Track: synced, TargetID: "syncedID"}})
+
+		got, err := executeReconcile(serviceLoader, ledger, reconcileOptions{policy: policySourceWins, dryRun: true}, "first", "second")
+
+		expected := `Would unlove on Second: Previously - Synced
+
+Service  Loved  Missing  To add  Not found  Review  To unlove  Conflicts  Failed
+First    0      1        0       0          0       0          0          0
+Second   1      0        0       0          0       1          0          0
+`
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got)
+	})
+
+	t.Run("returns error when failing to unlove tracks when source wins", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		synced := domain.Track{ID: "syncedID", Artist: "Previously", Name: "Synced"}
+
+		firstService := domain.NewMockService(ctrl)
+		firstService.EXPECT().Name().AnyTimes().Return("First")
+		firstService.EXPECT().Authenticated().Return(true)
+		firstService.EXPECT().GetLovedTracks(gomock.Any(), 50, 1).Return(nil, nil)
+		firstService.EXPECT().Close()
+
+		secondService := domain.NewMockService(ctrl)
+		secondService.EXPECT().Name().AnyTimes().Return("Second")
+		secondService.EXPECT().Authenticated().Return(true)
+		secondService.EXPECT().GetLovedTracks(gomock.Any(), 50, 1).Return([]domain.Track{synced}, nil)
+		secondService.EXPECT().UnloveTrack(gomock.Any(), synced).Return(errors.New("api error"))
+		secondService.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("first").Return(firstService, nil)
+		serviceLoader.EXPECT().ForName("second").Return(secondService, nil)
+
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Entries("First", "Second").Return([]domain.LedgerEntry{{Track: synced, TargetID: "syncedID"}})
+		ledger.EXPECT().Save()
+
+		got, err := executeReconcile(serviceLoader, ledger, reconcileOptions{policy: policySourceWins}, "first", "second")
+
+		assert.EqualError(t, err, "failed to reconcile 1 tracks")
+		assert.Contains(t, got, "Failed on Second: Previously - Synced (api error)")
+	})
+
+	t.Run("does not love tracks in dry run", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		firstService := domain.NewMockService(ctrl)
+		firstService.EXPECT().Name().AnyTimes().Return("First")
+		firstService.EXPECT().Authenticated().Return(true)
+		firstService.EXPECT().GetLovedTracks(gomock.Any(), 50, 1).Return([]domain.Track{onlyFirst}, nil)
+		firstService.EXPECT().FindTrack(gomock.Any(), onlySecond).Return(domain.Match{Method: domain.MatchByName}, nil)
+		firstService.EXPECT().Close()
+
+		secondService := domain.NewMockService(ctrl)
+		secondService.EXPECT().Name().AnyTimes().Return("Second")
+		secondService.EXPECT().Authenticated().Return(true)
+		secondService.EXPECT().GetLovedTracks(gomock.Any(), 50, 1).Return([]domain.Track{onlySecond}, nil)
+		secondService.EXPECT().FindTrack(gomock.Any(), onlyFirst).Return(domain.Match{Method: domain.MatchBySearch}, nil)
+		secondService.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("first").Return(firstService, nil)
+		serviceLoader.EXPECT().ForName("second").Return(secondService, nil)
+
+		got, err := executeReconcile(serviceLoader, domain.NewMockLedger(ctrl), reconcileOptions{policy: policyUnion, dryRun: true}, "first", "second")
+
+		expected := `Would love on Second: Awesome Artist - Blam (Instrumental) (search)
+Would love on First: Lonely - Only Here (name)
+
+Service  Loved  Missing  To add  Not found  Review  Failed
+First    1      1        1       0          0       0
+Second   1      1        1       0          0       0
+`
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got)
+	})
+
+	t.Run("returns error when failing to love tracks", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		firstService := domain.NewMockService(ctrl)
+		firstService.EXPECT().Name().AnyTimes().Return("First")
+		firstService.EXPECT().Authenticated().Return(true)
+		firstService.EXPECT().GetLovedTracks(gomock.Any(), 50, 1).Return([]domain.Track{onlyFirst}, nil)
+		firstService.EXPECT().Close()
+
+		secondService := domain.NewMockService(ctrl)
+		secondService.EXPECT().Name().AnyTimes().Return("Second")
+		secondService.EXPECT().Authenticated().Return(true)
+		secondService.EXPECT().GetLovedTracks(gomock.Any(), 50, 1).Return(nil, nil)
+		secondService.EXPECT().FindTrack(gomock.Any(), onlyFirst).Return(domain.Match{}, nil)
+		secondService.EXPECT().LoveTrack(gomock.Any(), gomock.Any()).Return(errors.New("api error"))
+		secondService.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("first").Return(firstService, nil)
+		serviceLoader.EXPECT().ForName("second").Return(secondService, nil)
+
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Save()
+
+		got, err := executeReconcile(serviceLoader, ledger, reconcileOptions{policy: policyUnion}, "first", "second")
+
+		assert.EqualError(t, err, "failed to reconcile 1 tracks")
+		assert.Contains(t, got, "Failed on Second: Awesome Artist - Blam (Instrumental) (api error)")
+	})
+
+	t.Run("returns error for unknown policy", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+
+		_, err := executeReconcile(serviceLoader, domain.NewMockLedger(ctrl), reconcileOptions{policy: "target-wins"}, "first", "second")
+
+		assert.EqualError(t, err, `unknown policy "target-wins", expected "union" or "source-wins"`)
+	})
+
+	t.Run("returns error when service is not authenticated", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		firstService := domain.NewMockService(ctrl)
+		firstService.EXPECT().Name().AnyTimes().Return("First")
+		firstService.EXPECT().Authenticated().Return(true)
+		firstService.EXPECT().Close()
+
+		secondService := domain.NewMockService(ctrl)
+		secondService.EXPECT().Name().AnyTimes().Return("Second")
+		secondService.EXPECT().Authenticated().Return(false)
+		secondService.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("first").Return(firstService, nil)
+		serviceLoader.EXPECT().ForName("second").Return(secondService, nil)
+
+		_, err := executeReconcile(serviceLoader, domain.NewMockLedger(ctrl), reconcileOptions{policy: policyUnion}, "first", "second")
+
+		assert.EqualError(t, err, "not logged in on Second")
+	})
+}
+
+func executeReconcile(serviceLoader domain.ServiceLoader, ledger domain.Ledger, options reconcileOptions, args ...string) (string, error) {
+	buffer := new(bytes.Buffer)
+	output := newPrinter(outputText, buffer)
+	err := output.finish(reconcile(context.Background(), serviceLoader, ledger, options, output, args))
+	return buffer.String(), err
+}
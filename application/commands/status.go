@@ -1,8 +1,7 @@
 package commands
 
 import (
-	"fmt"
-	"io"
+	"context"
 
 	"github.com/dietrichm/admirer/domain"
 	"github.com/dietrichm/admirer/infrastructure/services"
@@ -17,20 +16,24 @@ var statusCommand = &cobra.Command{
 	Use:   "status",
 	Short: "Retrieve status for services",
 	RunE: func(command *cobra.Command, args []string) error {
-		return status(services.AvailableServices, command.OutOrStdout())
+		output := newPrinter(outputFormat, command.OutOrStdout())
+		return output.finish(status(command.Context(), services.AvailableServices, output))
 	},
 }
 
-func status(serviceLoader domain.ServiceLoader, writer io.Writer) error {
+// status prints the authentication status of every service.
+// Services which cannot be loaded, such as services missing environment variables, are reported as not configured.
+func status(ctx context.Context, serviceLoader domain.ServiceLoader, output *printer) error {
 	for _, serviceName := range serviceLoader.Names() {
 		service, err := serviceLoader.ForName(serviceName)
 		if err != nil {
-			return err
+			output.print(statusRecord{Service: serviceName, Error: err.Error()})
+			continue
 		}
 
 		defer service.Close()
 
-		if err := statusForService(service, writer); err != nil {
+		if err := statusForService(ctx, service, output); err != nil {
 			return err
 		}
 	}
@@ -38,19 +41,17 @@ func status(serviceLoader domain.ServiceLoader, writer io.Writer) error {
 	return nil
 }
 
-func statusForService(service domain.Service, writer io.Writer) error {
+func statusForService(ctx context.Context, service domain.Service, output *printer) error {
 	if !service.Authenticated() {
-		fmt.Fprintln(writer, service.Name())
-		fmt.Fprintln(writer, "\tNot logged in")
+		output.print(statusRecord{Service: service.Name()})
 		return nil
 	}
 
-	username, err := service.GetUsername()
+	username, err := service.GetUsername(ctx)
 	if err != nil {
 		return err
 	}
 
-	fmt.Fprintln(writer, service.Name())
-	fmt.Fprintln(writer, "\tAuthenticated as", username)
+	output.print(statusRecord{Service: service.Name(), Authenticated: true, Username: username})
 	return nil
 }
@@ -2,11 +2,13 @@ package commands
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"go.uber.org/mock/gomock"
 	"testing"
 
 	"github.com/dietrichm/admirer/domain"
+	"github.com/dietrichm/admirer/infrastructure/services/local"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -17,13 +19,13 @@ func TestStatus(t *testing.T) {
 		fooService := domain.NewMockService(ctrl)
 		fooService.EXPECT().Name().Return("Foo")
 		fooService.EXPECT().Authenticated().Return(true)
-		fooService.EXPECT().GetUsername().Return("user303", nil)
+		fooService.EXPECT().GetUsername(gomock.Any()).Return("user303", nil)
 		fooService.EXPECT().Close()
 
 		barService := domain.NewMockService(ctrl)
 		barService.EXPECT().Name().Return("Bar")
 		barService.EXPECT().Authenticated().Return(true)
-		barService.EXPECT().GetUsername().Return("user808", nil)
+		barService.EXPECT().GetUsername(gomock.Any()).Return("user808", nil)
 		barService.EXPECT().Close()
 
 		serviceLoader := domain.NewMockServiceLoader(ctrl)
@@ -42,18 +44,47 @@ Bar
 		assert.Equal(t, expected, got)
 	})
 
-	t.Run("returns error when failing to load service", func(t *testing.T) {
+	t.Run("reports service which fails to load as not configured and continues", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 
-		expected := "failed to load"
+		barService := domain.NewMockService(ctrl)
+		barService.EXPECT().Name().Return("Bar")
+		barService.EXPECT().Authenticated().Return(true)
+		barService.EXPECT().GetUsername(gomock.Any()).Return("user808", nil)
+		barService.EXPECT().Close()
+
 		serviceLoader := domain.NewMockServiceLoader(ctrl)
-		serviceLoader.EXPECT().Names().Return([]string{"foo"})
-		serviceLoader.EXPECT().ForName("foo").Return(nil, errors.New(expected))
+		serviceLoader.EXPECT().Names().Return([]string{"foo", "bar"})
+		serviceLoader.EXPECT().ForName("foo").Return(nil, errors.New("please set FOO_CLIENT_ID environment variable"))
+		serviceLoader.EXPECT().ForName("bar").Return(barService, nil)
 
-		output, err := executeStatus(serviceLoader)
+		expected := `foo
+	Not configured: please set FOO_CLIENT_ID environment variable
+Bar
+	Authenticated as user808
+`
+		got, err := executeStatus(serviceLoader)
 
-		assert.EqualError(t, err, expected)
-		assert.Empty(t, output)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got)
+	})
+
+	t.Run("reports local library without directory as not configured", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		_, err := local.NewLocal("")
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().Names().Return([]string{"local"})
+		serviceLoader.EXPECT().ForName("local").Return(nil, err)
+
+		expected := `local
+	Not configured: please set LOCAL_MUSIC_DIRECTORY environment variable or use local:<directory> as service name
+`
+		got, err := executeStatus(serviceLoader)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got)
 	})
 
 	t.Run("returns message when not authenticated", func(t *testing.T) {
@@ -83,7 +114,7 @@ Bar
 		expected := "auth error"
 		fooService := domain.NewMockService(ctrl)
 		fooService.EXPECT().Authenticated().Return(true)
-		fooService.EXPECT().GetUsername().Return("", errors.New(expected))
+		fooService.EXPECT().GetUsername(gomock.Any()).Return("", errors.New(expected))
 		fooService.EXPECT().Close()
 
 		serviceLoader := domain.NewMockServiceLoader(ctrl)
@@ -99,6 +130,7 @@ Bar
 
 func executeStatus(serviceLoader domain.ServiceLoader) (string, error) {
 	buffer := new(bytes.Buffer)
-	err := status(serviceLoader, buffer)
+	output := newPrinter(outputText, buffer)
+	err := output.finish(status(context.Background(), serviceLoader, output))
 	return buffer.String(), err
 }
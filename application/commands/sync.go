@@ -1,17 +1,42 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"io"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/dietrichm/admirer/domain"
 	"github.com/dietrichm/admirer/infrastructure/services"
+	"github.com/dietrichm/admirer/infrastructure/state"
 	"github.com/spf13/cobra"
 )
 
+var (
+	fullSync      bool
+	resumeSync    bool
+	dryRunSync    bool
+	mirrorRemoval bool
+	unmatchedFile string
+	minConfidence float64
+	concurrency   int
+	cacheTTL      time.Duration
+)
+
 func init() {
 	syncCommand.Flags().IntVarP(&limit, "limit", "l", 10, "Limit number of tracks for syncing. Specify 0 to sync all tracks without limitations. In this case, the default limit for a group of tracks will be 50 (note: important for accurate page counting)")
 	syncCommand.Flags().IntVarP(&page, "page", "p", 1, "Page number to start syncing from")
+	syncCommand.Flags().BoolVar(&fullSync, "full", false, "Sync all tracks, including tracks which were synced before")
+	syncCommand.Flags().BoolVar(&resumeSync, "resume", false, "Resume an unfinished sync from the page after its last checkpoint")
+	syncCommand.Flags().BoolVar(&dryRunSync, "dry-run", false, "Show which tracks would be loved on the target service without changing anything")
+	syncCommand.Flags().BoolVar(&mirrorRemoval, "mirror-removals", false, "Unlove tracks on the target service which were synced before but are no longer loved on the source service")
+	syncCommand.Flags().StringVar(&unmatchedFile, "unmatched", "", "Write tracks which could not be found on the target service to this file")
+	syncCommand.Flags().Float64Var(&minConfidence, "min-confidence", domain.DefaultThreshold, "Minimum confidence (0 to 1) for accepting a track found on the target service")
+	syncCommand.Flags().IntVar(&concurrency, "concurrency", 1, "Number of tracks looked up on the target service at the same time")
+	syncCommand.Flags().DurationVar(&cacheTTL, "cache-ttl", domain.DefaultMatchCacheTTL, "How long tracks found or not found on the target service are remembered, such as 24h. Specify 0 to look up all tracks again")
+	addDateFlags(syncCommand)
 	rootCommand.AddCommand(syncCommand)
 }
 
@@ -20,20 +45,86 @@ var syncCommand = &cobra.Command{
 	Short: "Sync recently loved tracks from one service to another",
 	Args:  cobra.MinimumNArgs(2),
 	RunE: func(command *cobra.Command, args []string) error {
-		return sync(services.AvailableServices, limit, page, command.OutOrStdout(), args)
+		if concurrency < 1 {
+			return fmt.Errorf("--concurrency must be at least 1, got %d", concurrency)
+		}
+
+		if cacheTTL < 0 {
+			return fmt.Errorf("--cache-ttl must not be negative, got %s", cacheTTL)
+		}
+
+		window, err := dateWindowFromFlags()
+		if err != nil {
+			return err
+		}
+
+		ledger, err := state.LoadLedger()
+		if err != nil {
+			return err
+		}
+
+		checkpoints, err := state.LoadCheckpoints()
+		if err != nil {
+			return err
+		}
+
+		runs, err := state.LoadRuns()
+		if err != nil {
+			return err
+		}
+
+		var matches domain.MatchCache
+		if cacheTTL > 0 {
+			if matches, err = state.LoadMatchCache(cacheTTL); err != nil {
+				return err
+			}
+		}
+
+		options := syncOptions{
+			limit:          limit,
+			page:           page,
+			full:           fullSync,
+			resume:         resumeSync,
+			dryRun:         dryRunSync,
+			mirrorRemovals: mirrorRemoval,
+			unmatchedFile:  unmatchedFile,
+			minConfidence:  minConfidence,
+			concurrency:    concurrency,
+			window:         window,
+		}
+
+		output := newPrinter(outputFormat, command.OutOrStdout())
+		return output.finish(sync(command.Context(), services.AvailableServices, ledger, checkpoints, runs, matches, options, output, args))
 	},
 }
 
-func sync(serviceLoader domain.ServiceLoader, limit int, page int, writer io.Writer, args []string) error {
+type syncOptions struct {
+	limit          int
+	page           int
+	full           bool
+	resume         bool
+	dryRun         bool
+	mirrorRemovals bool
+	unmatchedFile  string
+	minConfidence  float64
+	concurrency    int
+	window         dateWindow
+}
+
+type syncReport struct {
+	synced        int
+	alreadyLoved  int
+	notFound      []domain.Track
+	lowConfidence []domain.Track
+	failed        int
+	unloved       int
+}
+
+func sync(ctx context.Context, serviceLoader domain.ServiceLoader, ledger domain.Ledger, checkpoints domain.CheckpointStore, runs domain.RunStore, matches domain.MatchCache, options syncOptions, output *printer, args []string) error {
+	startedAt := time.Now()
 	sourceServiceName := args[0]
 	targetServiceName := args[1]
 
-	continuously := false
-	if limit == 0 {
-		limit = 50
-		continuously = true
-	}
-
 	sourceService, err := serviceLoader.ForName(sourceServiceName)
 	if err != nil {
 		return err
@@ -55,22 +146,505 @@ func sync(serviceLoader domain.ServiceLoader, limit int, page int, writer io.Wri
 		return fmt.Errorf("not logged in on %s", targetService.Name())
 	}
 
-	for ; ; page++ {
-		tracks, err := sourceService.GetLovedTracks(limit, page)
-		if err != nil {
+	if matchingService, ok := targetService.(domain.MatchingService); ok {
+		matchingService.SetMatcher(domain.NewMatcher(options.minConfidence))
+	}
+
+	source := sourceService.Name()
+	target := targetService.Name()
+	startPage := options.page
+
+	if options.resume {
+		if checkpoint, exists := checkpoints.Checkpoint(source, target); exists {
+			startPage = checkpoint.Page + 1
+			options.limit = checkpoint.Limit
+			fmt.Fprintf(output.messages(), "Resuming from page %d after %s\n", startPage, checkpoint.LastTrack.String())
+		} else {
+			fmt.Fprintf(output.messages(), "No checkpoint found, starting from page %d\n", startPage)
+		}
+	}
+
+	report := &syncReport{}
+
+	runKey := "sync:" + source + ":" + target
+	lovedTracksOptions := options.window.apply(pageOptions(options.limit, startPage), runs, runKey, output.messages())
+
+	var failures failedTracks
+	if !options.full {
+		failures = ledger.Failures(source, target)
+	}
+
+	lovedTracks := domain.NewLovedTracks(sourceService, lovedTracksOptions)
+	for lovedTracks.Next(ctx) {
+		tracks := lovedTracks.Tracks()
+		done := syncTracks(ctx, sourceService, targetService, ledger, matches, options, tracks, &failures, report, output)
+
+		if matches != nil {
+			if err := matches.Save(); err != nil {
+				return fmt.Errorf("failed to save match cache: %w", err)
+			}
+		}
+
+		if !options.dryRun {
+			if err := ledger.Save(); err != nil {
+				return fmt.Errorf("failed to save sync ledger: %w", err)
+			}
+
+			// A page which was interrupted halfway is synced again when resuming.
+			if ctx.Err() == nil {
+				checkpoint := domain.Checkpoint{
+					Source:    source,
+					Target:    target,
+					Page:      lovedTracks.Page(),
+					Limit:     options.limit,
+					LastTrack: tracks[len(tracks)-1],
+				}
+				if err := checkpoints.Save(checkpoint); err != nil {
+					return fmt.Errorf("failed to save sync checkpoint: %w", err)
+				}
+			}
+		}
+
+		if ctx.Err() != nil {
+			return syncInterrupted(ctx)
+		}
+
+		if done {
+			break
+		}
+	}
+
+	if ctx.Err() != nil {
+		return syncInterrupted(ctx)
+	}
+	if err := lovedTracks.Err(); err != nil {
+		return err
+	}
+
+	// Tracks which failed before but were not reached while going through all loved tracks are no longer loved, so they are not retried again.
+	if len(failures) > 0 && !options.dryRun && includesAllTracks(lovedTracksOptions) {
+		for _, track := range failures {
+			ledger.Remove(source, target, track)
+		}
+
+		if err := ledger.Save(); err != nil {
+			return fmt.Errorf("failed to save sync ledger: %w", err)
+		}
+	}
+
+	if options.mirrorRemovals {
+		if err := mirrorRemovals(ctx, sourceService, targetService, ledger, options.dryRun, report, output); err != nil {
+			if ctx.Err() != nil {
+				return syncInterrupted(ctx)
+			}
 			return err
 		}
 
-		for _, track := range tracks {
-			if err := targetService.LoveTrack(track); err != nil {
+		if !options.dryRun {
+			if err := ledger.Save(); err != nil {
+				return fmt.Errorf("failed to save sync ledger: %w", err)
+			}
+		}
+
+		if ctx.Err() != nil {
+			return syncInterrupted(ctx)
+		}
+	}
+
+	summary := output.messages()
+	if options.dryRun {
+		fmt.Fprintf(summary, "Summary: %d to love, %d already loved, %d not found, %d to review, %d failed", report.synced, report.alreadyLoved, len(report.notFound), len(report.lowConfidence), report.failed)
+		if options.mirrorRemovals {
+			fmt.Fprintf(summary, ", %d to unlove", report.unloved)
+		}
+	} else {
+		if err := checkpoints.Clear(source, target); err != nil {
+			return fmt.Errorf("failed to clear sync checkpoint: %w", err)
+		}
+
+		// Tracks which failed to sync are older than this run, so the next run only includes them when this run is not recorded.
+		if report.failed == 0 {
+			if err := options.window.record(runs, runKey, startedAt); err != nil {
 				return err
 			}
+		}
+
+		fmt.Fprintf(summary, "Summary: %d synced, %d already loved, %d not found, %d to review, %d failed", report.synced, report.alreadyLoved, len(report.notFound), len(report.lowConfidence), report.failed)
+		if options.mirrorRemovals {
+			fmt.Fprintf(summary, ", %d unloved", report.unloved)
+		}
+	}
+	fmt.Fprintln(summary)
+
+	if options.unmatchedFile != "" {
+		unmatchedTracks := append(report.notFound, report.lowConfidence...)
+		if err := writeUnmatchedTracks(options.unmatchedFile, unmatchedTracks); err != nil {
+			return err
+		}
+	}
+
+	if report.failed > 0 {
+		return fmt.Errorf("failed to sync %d tracks to %s", report.failed, targetService.Name())
+	}
+
+	return nil
+}
+
+// syncInterrupted returns the error for a sync which was stopped by cancelling its context.
+// Tracks synced until then are recorded in the ledger, but no checkpoint is saved for the interrupted page.
+func syncInterrupted(ctx context.Context) error {
+	return fmt.Errorf("sync interrupted: %w", ctx.Err())
+}
+
+// syncTracks syncs the tracks until reaching a track which was synced before.
+// As long as tracks which failed in earlier runs were not reached, tracks which were synced before are skipped instead.
+func syncTracks(ctx context.Context, sourceService domain.Service, targetService domain.Service, ledger domain.Ledger, matches domain.MatchCache, options syncOptions, tracks []domain.Track, failures *failedTracks, report *syncReport, output *printer) (done bool) {
+	source := sourceService.Name()
+	target := targetService.Name()
+
+	var pending []domain.Track
+	var retried []bool
+	var syncedTrack domain.Track
+	for _, track := range tracks {
+		if !options.full && ledger.Synced(source, target, track) {
+			if len(*failures) > 0 {
+				continue
+			}
+			syncedTrack = track
+			done = true
+			break
+		}
+		pending = append(pending, track)
+		retried = append(retried, failures.reach(track))
+	}
+
+	// Tracks which were already loved on the target service are not loved again, and recorded as preexisting.
+	results := findCachedTracks(ctx, targetService, matches, options, pending)
+	checkLoved(ctx, targetService, results)
+	if !options.dryRun {
+		loveTracks(ctx, targetService, results)
+	}
+
+	for index, result := range results {
+		track := result.track
+
+		// Tracks which could not be synced because of the interruption are not reported as failures.
+		if result.err != nil && ctx.Err() != nil {
+			continue
+		}
+
+		// Earlier failures of tracks which are now not found or to review are not retried again.
+		if retried[index] && !options.dryRun && result.err != nil && !isFailure(result.err) {
+			ledger.Remove(source, target, track)
+		}
+
+		var lowConfidence *domain.LowConfidenceError
+		if errors.As(result.err, &lowConfidence) {
+			report.lowConfidence = append(report.lowConfidence, track)
+			output.print(newSyncRecord(source, target, outcomeReview, track).withMatch(lowConfidence.Match))
+			continue
+		}
+		if errors.Is(result.err, domain.ErrTrackNotFound) {
+			report.notFound = append(report.notFound, track)
+			output.print(newSyncRecord(source, target, outcomeNotFound, track))
+			continue
+		}
+		if result.err != nil {
+			if !options.dryRun {
+				ledger.RecordFailure(source, target, track)
+			}
+			report.failed++
+			output.print(newSyncRecord(source, target, outcomeFailed, track).withMatch(result.match).withError(result.err))
+			continue
+		}
+
+		if result.loved {
+			if !options.dryRun {
+				ledger.RecordPreexisting(source, target, track, result.match.Track.ID)
+			}
+			report.alreadyLoved++
+			output.print(newSyncRecord(source, target, outcomeAlreadyLoved, track).withMatch(result.match))
+			continue
+		}
 
-			fmt.Fprintln(writer, "Synced:", track.String())
+		if options.dryRun {
+			report.synced++
+			output.print(newSyncRecord(source, target, outcomeWouldLove, track).withMatch(result.match))
+			continue
 		}
-		if !continuously || len(tracks) < limit {
+
+		ledger.Record(source, target, track, result.match.Track.ID)
+		report.synced++
+		output.print(newSyncRecord(source, target, outcomeSynced, track).withMatch(result.match))
+	}
+
+	if done && ctx.Err() == nil {
+		output.print(newSyncRecord(source, target, outcomePreviouslySynced, syncedTrack))
+	}
+
+	return done
+}
+
+// includesAllTracks returns whether iterating with the options goes through all loved tracks of a service.
+func includesAllTracks(options domain.LovedTracksOptions) bool {
+	return options.Offset == 0 && options.Limit == 0 && options.Since.IsZero() && options.Until.IsZero()
+}
+
+// failedTracks holds the tracks which failed to sync in earlier runs and were not reached yet.
+type failedTracks []domain.Track
+
+// reach removes the track from the failed tracks, returning whether it failed in an earlier run.
+func (f *failedTracks) reach(track domain.Track) bool {
+	for index, failed := range *f {
+		if strings.EqualFold(failed.Artist, track.Artist) && strings.EqualFold(failed.Name, track.Name) {
+			*f = append((*f)[:index], (*f)[index+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// isFailure returns whether the error is a failure to sync a track, rather than the track not being found or needing review.
+func isFailure(err error) bool {
+	var lowConfidence *domain.LowConfidenceError
+	return !errors.As(err, &lowConfidence) && !errors.Is(err, domain.ErrTrackNotFound)
+}
+
+type syncResult struct {
+	track domain.Track
+	match domain.Match
+	loved bool
+	err   error
+}
+
+// findTracks looks up the tracks on the service, until the context is cancelled.
+// Up to concurrency tracks are looked up at the same time, sharing the rate limit of the service, and the results keep the order of the tracks.
+func findTracks(ctx context.Context, service domain.Service, tracks []domain.Track, concurrency int) []*syncResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > len(tracks) {
+		concurrency = len(tracks)
+	}
+
+	results := make([]*syncResult, len(tracks))
+	indexes := make(chan int)
+	finished := make(chan struct{})
+
+	for worker := 0; worker < concurrency; worker++ {
+		go func() {
+			for index := range indexes {
+				match, err := service.FindTrack(ctx, tracks[index])
+				results[index] = &syncResult{
+					track: tracks[index],
+					match: match,
+					err:   err,
+				}
+			}
+			finished <- struct{}{}
+		}()
+	}
+
+	started := 0
+	for index := range tracks {
+		if ctx.Err() != nil {
 			break
 		}
+		indexes <- index
+		started++
+	}
+
+	close(indexes)
+	for worker := 0; worker < concurrency; worker++ {
+		<-finished
+	}
+
+	return results[:started]
+}
+
+// findCachedTracks looks up the tracks on the service like findTracks, but takes the tracks found or not found before from the match cache.
+// Cached matches below the minimum confidence are looked up again. Tracks which failed to be looked up are not cached.
+func findCachedTracks(ctx context.Context, service domain.Service, matches domain.MatchCache, options syncOptions, tracks []domain.Track) []*syncResult {
+	if matches == nil {
+		return findTracks(ctx, service, tracks, options.concurrency)
+	}
+
+	target := service.Name()
+	cached := make([]*syncResult, len(tracks))
+	var uncached []domain.Track
+
+	for index, track := range tracks {
+		match, exists := matches.Lookup(target, track)
+		switch {
+		case exists && match.NotFound:
+			cached[index] = &syncResult{track: track, err: domain.ErrTrackNotFound}
+		case exists && match.Match.Confidence >= options.minConfidence:
+			cached[index] = &syncResult{track: track, match: match.Match}
+		default:
+			uncached = append(uncached, track)
+		}
+	}
+
+	found := findTracks(ctx, service, uncached, options.concurrency)
+	for _, result := range found {
+		if result.err == nil {
+			matches.Store(target, result.track, domain.CachedMatch{Match: result.match})
+		} else if errors.Is(result.err, domain.ErrTrackNotFound) {
+			matches.Store(target, result.track, domain.CachedMatch{NotFound: true})
+		}
+	}
+
+	// When interrupted, the results end at the first track which was not looked up.
+	results := make([]*syncResult, 0, len(tracks))
+	for _, result := range cached {
+		if result == nil {
+			if len(found) == 0 {
+				break
+			}
+			result, found = found[0], found[1:]
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// foundTracks returns the results of tracks found on the service which are not loved there yet, along with the found tracks.
+func foundTracks(results []*syncResult) (found []*syncResult, tracks []domain.Track) {
+	for _, result := range results {
+		if result.err == nil && !result.loved {
+			found = append(found, result)
+			tracks = append(tracks, result.match.Track)
+		}
+	}
+	return
+}
+
+func loveTracks(ctx context.Context, service domain.Service, results []*syncResult) {
+	found, tracks := foundTracks(results)
+
+	if batchLover, ok := service.(domain.BatchLover); ok {
+		if len(tracks) == 0 {
+			return
+		}
+		errs := batchLover.LoveTracks(ctx, tracks)
+		if len(errs) != len(tracks) {
+			err := fmt.Errorf("%s returned %d results for %d loved tracks", service.Name(), len(errs), len(tracks))
+			for _, result := range found {
+				result.err = err
+			}
+			return
+		}
+
+		for index, err := range errs {
+			found[index].err = err
+		}
+		return
+	}
+
+	for index, track := range tracks {
+		if err := ctx.Err(); err != nil {
+			found[index].err = err
+			continue
+		}
+		found[index].err = service.LoveTrack(ctx, track)
+	}
+}
+
+func checkLoved(ctx context.Context, service domain.Service, results []*syncResult) {
+	found, tracks := foundTracks(results)
+
+	loveChecker, ok := service.(domain.LoveChecker)
+	if !ok || len(tracks) == 0 {
+		return
+	}
+
+	loved, err := loveChecker.Loved(ctx, tracks)
+	if err == nil && len(loved) != len(tracks) {
+		err = fmt.Errorf("%s returned %d loved states for %d tracks", service.Name(), len(loved), len(tracks))
+	}
+
+	for index, result := range found {
+		if err != nil {
+			result.err = err
+			continue
+		}
+		result.loved = loved[index]
+	}
+}
+
+// mirrorRemovals unloves tracks on the target service which were synced before, but are no longer loved on the source service.
+// Only tracks which admirer loved, as recorded in the ledger, are unloved, so tracks which were loved on the target service by other means are left alone.
+func mirrorRemovals(ctx context.Context, sourceService domain.Service, targetService domain.Service, ledger domain.Ledger, dryRun bool, report *syncReport, output *printer) error {
+	source := sourceService.Name()
+	target := targetService.Name()
+
+	entries := ledger.Entries(source, target)
+	if len(entries) == 0 {
+		return nil
+	}
+
+	tracks, err := domain.NewLovedTracks(sourceService, domain.LovedTracksOptions{}).All(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(tracks) == 0 {
+		return fmt.Errorf("refusing to unlove %d synced tracks on %s: no loved tracks found on %s", len(entries), target, source)
+	}
+
+	keys := trackKeys(tracks)
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		track := entry.Track
+		if entry.Preexisting || keys.contains(track) {
+			continue
+		}
+
+		if dryRun {
+			report.unloved++
+			output.print(newSyncRecord(source, target, outcomeWouldUnlove, track))
+			continue
+		}
+
+		targetTrack := domain.Track{
+			ID:     entry.TargetID,
+			Artist: track.Artist,
+			Name:   track.Name,
+		}
+		if err := targetService.UnloveTrack(ctx, targetTrack); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			report.failed++
+			output.print(newSyncRecord(source, target, outcomeFailed, track).withError(err))
+			continue
+		}
+
+		ledger.Remove(source, target, track)
+		report.unloved++
+		output.print(newSyncRecord(source, target, outcomeUnloved, track).withMatch(domain.Match{Track: targetTrack}))
+	}
+
+	return nil
+}
+
+func writeUnmatchedTracks(filename string, tracks []domain.Track) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed writing unmatched tracks: %w", err)
+	}
+	defer file.Close()
+
+	for _, track := range tracks {
+		fmt.Fprintln(file, track.String())
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed writing unmatched tracks: %w", err)
 	}
 
 	return nil
@@ -2,11 +2,18 @@ package commands
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"fmt"
 	"go.uber.org/mock/gomock"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/dietrichm/admirer/domain"
+	"github.com/dietrichm/admirer/infrastructure/services"
+	"github.com/dietrichm/admirer/infrastructure/state"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -25,31 +32,906 @@ func TestSync(t *testing.T) {
 		tracks := []domain.Track{trackOne, trackTwo}
 
 		sourceService := domain.NewMockService(ctrl)
+		sourceService.EXPECT().Name().AnyTimes().Return("Source")
+		sourceService.EXPECT().Authenticated().Return(true)
+		sourceService.EXPECT().GetLovedTracks(gomock.Any(), 5, 1).Return(tracks, nil)
+		sourceService.EXPECT().Close()
+
+		targetService := domain.NewMockService(ctrl)
+		targetService.EXPECT().Name().AnyTimes().Return("Target")
+		targetService.EXPECT().Authenticated().Return(true)
+		targetService.EXPECT().FindTrack(gomock.Any(), trackOne).Return(domain.Match{Track: domain.Track{ID: "targetOne"}, Method: domain.MatchBySearch}, nil)
+		targetService.EXPECT().LoveTrack(gomock.Any(), domain.Track{ID: "targetOne"})
+		targetService.EXPECT().FindTrack(gomock.Any(), trackTwo).Return(domain.Match{Track: domain.Track{ID: "targetTwo"}, Method: domain.MatchBySearch}, nil)
+		targetService.EXPECT().LoveTrack(gomock.Any(), domain.Track{ID: "targetTwo"})
+		targetService.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("source").Return(sourceService, nil)
+		serviceLoader.EXPECT().ForName("target").Return(targetService, nil)
+
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Failures("Source", "Target")
+		ledger.EXPECT().Synced("Source", "Target", trackOne).Return(false)
+		ledger.EXPECT().Synced("Source", "Target", trackTwo).Return(false)
+		ledger.EXPECT().Record("Source", "Target", trackOne, "targetOne")
+		ledger.EXPECT().Record("Source", "Target", trackTwo, "targetTwo")
+		ledger.EXPECT().Save()
+
+		got, err := executeSync(serviceLoader, ledger, anyCheckpoints(ctrl), syncOptions{limit: 5, page: 1}, "source", "target")
+
+		expected := `Synced: Awesome Artist - Blam (Instrumental) (search)
+Synced: Foo & Bar - Mr. Testy (search)
+Summary: 2 synced, 0 already loved, 0 not found, 0 to review, 0 failed
+`
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got)
+	})
+
+	t.Run("stops syncing when reaching previously synced track", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		trackOne := domain.Track{
+			Artist: "Awesome Artist",
+			Name:   "Blam (Instrumental)",
+		}
+		trackTwo := domain.Track{
+			Artist: "Foo & Bar",
+			Name:   "Mr. Testy",
+		}
+		tracks := []domain.Track{trackOne, trackTwo}
+
+		sourceService := domain.NewMockService(ctrl)
+		sourceService.EXPECT().Name().AnyTimes().Return("Source")
+		sourceService.EXPECT().Authenticated().Return(true)
+		sourceService.EXPECT().GetLovedTracks(gomock.Any(), 50, 1).Return(tracks, nil)
+		sourceService.EXPECT().Close()
+
+		targetService := domain.NewMockService(ctrl)
+		targetService.EXPECT().Name().AnyTimes().Return("Target")
+		targetService.EXPECT().Authenticated().Return(true)
+		targetService.EXPECT().FindTrack(gomock.Any(), trackOne).Return(domain.Match{Track: domain.Track{ID: "targetOne"}, Method: domain.MatchBySearch}, nil)
+		targetService.EXPECT().LoveTrack(gomock.Any(), domain.Track{ID: "targetOne"})
+		targetService.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("source").Return(sourceService, nil)
+		serviceLoader.EXPECT().ForName("target").Return(targetService, nil)
+
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Failures("Source", "Target")
+		ledger.EXPECT().Synced("Source", "Target", trackOne).Return(false)
+		ledger.EXPECT().Synced("Source", "Target", trackTwo).Return(true)
+		ledger.EXPECT().Record("Source", "Target", trackOne, "targetOne")
+		ledger.EXPECT().Save()
+
+		got, err := executeSync(serviceLoader, ledger, anyCheckpoints(ctrl), syncOptions{limit: 0, page: 1}, "source", "target")
+
+		expected := `Synced: Awesome Artist - Blam (Instrumental) (search)
+Reached previously synced track: Foo & Bar - Mr. Testy
+Summary: 1 synced, 0 already loved, 0 not found, 0 to review, 0 failed
+`
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got)
+	})
+
+	t.Run("syncs previously synced tracks on full sync", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		track := domain.Track{
+			Artist: "Foo & Bar",
+			Name:   "Mr. Testy",
+		}
+
+		sourceService := domain.NewMockService(ctrl)
+		sourceService.EXPECT().Name().AnyTimes().Return("Source")
+		sourceService.EXPECT().Authenticated().Return(true)
+		sourceService.EXPECT().GetLovedTracks(gomock.Any(), 5, 1).Return([]domain.Track{track}, nil)
+		sourceService.EXPECT().Close()
+
+		targetService := domain.NewMockService(ctrl)
+		targetService.EXPECT().Name().AnyTimes().Return("Target")
+		targetService.EXPECT().Authenticated().Return(true)
+		targetService.EXPECT().FindTrack(gomock.Any(), track).Return(domain.Match{Track: domain.Track{ID: "targetID"}, Method: domain.MatchBySearch}, nil)
+		targetService.EXPECT().LoveTrack(gomock.Any(), domain.Track{ID: "targetID"})
+		targetService.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("source").Return(sourceService, nil)
+		serviceLoader.EXPECT().ForName("target").Return(targetService, nil)
+
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Record("Source", "Target", track, "targetID")
+		ledger.EXPECT().Save()
+
+		got, err := executeSync(serviceLoader, ledger, anyCheckpoints(ctrl), syncOptions{limit: 5, page: 1, full: true}, "source", "target")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Synced: Foo & Bar - Mr. Testy (search)\nSummary: 1 synced, 0 already loved, 0 not found, 0 to review, 0 failed\n", got)
+	})
+
+	t.Run("returns error when failing to save ledger", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		track := domain.Track{
+			Artist: "Foo & Bar",
+			Name:   "Mr. Testy",
+		}
+
+		sourceService := domain.NewMockService(ctrl)
+		sourceService.EXPECT().Name().AnyTimes().Return("Source")
+		sourceService.EXPECT().Authenticated().Return(true)
+		sourceService.EXPECT().GetLovedTracks(gomock.Any(), gomock.Any(), gomock.Any()).Return([]domain.Track{track}, nil)
+		sourceService.EXPECT().Close()
+
+		targetService := domain.NewMockService(ctrl)
+		targetService.EXPECT().Name().AnyTimes().Return("Target")
+		targetService.EXPECT().Authenticated().Return(true)
+		targetService.EXPECT().FindTrack(gomock.Any(), track).Return(domain.Match{}, domain.ErrTrackNotFound)
+		targetService.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("source").Return(sourceService, nil)
+		serviceLoader.EXPECT().ForName("target").Return(targetService, nil)
+
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Failures("Source", "Target")
+		ledger.EXPECT().Synced("Source", "Target", track).Return(false)
+		ledger.EXPECT().Save().Return(errors.New("write error"))
+
+		_, err := executeSync(serviceLoader, ledger, anyCheckpoints(ctrl), syncOptions{limit: 10, page: 1}, "source", "target")
+
+		assert.EqualError(t, err, "failed to save sync ledger: write error")
+	})
+
+	t.Run("saves checkpoint after every page and clears it when done", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		track := domain.Track{
+			Artist: "Foo & Bar",
+			Name:   "Mr. Testy",
+		}
+		fullPage := make([]domain.Track, 50)
+		for index := range fullPage {
+			fullPage[index] = track
+		}
+
+		sourceService := domain.NewMockService(ctrl)
+		sourceService.EXPECT().Name().AnyTimes().Return("Source")
+		sourceService.EXPECT().Authenticated().Return(true)
+		sourceService.EXPECT().GetLovedTracks(gomock.Any(), 50, 1).Return(fullPage, nil)
+		sourceService.EXPECT().GetLovedTracks(gomock.Any(), 50, 2).Return([]domain.Track{track}, nil)
+		sourceService.EXPECT().Close()
+
+		targetService := domain.NewMockService(ctrl)
+		targetService.EXPECT().Name().AnyTimes().Return("Target")
+		targetService.EXPECT().Authenticated().Return(true)
+		targetService.EXPECT().FindTrack(gomock.Any(), track).Times(51)
+		targetService.EXPECT().LoveTrack(gomock.Any(), gomock.Any()).Times(51)
+		targetService.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("source").Return(sourceService, nil)
+		serviceLoader.EXPECT().ForName("target").Return(targetService, nil)
+
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Record(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		ledger.EXPECT().Save().Times(2)
+
+		checkpoints := domain.NewMockCheckpointStore(ctrl)
+		gomock.InOrder(
+			checkpoints.EXPECT().Save(domain.Checkpoint{Source: "Source", Target: "Target", Page: 1, Limit: 0, LastTrack: track}),
+			checkpoints.EXPECT().Save(domain.Checkpoint{Source: "Source", Target: "Target", Page: 2, Limit: 0, LastTrack: track}),
+			checkpoints.EXPECT().Clear("Source", "Target"),
+		)
+
+		_, err := executeSync(serviceLoader, ledger, checkpoints, syncOptions{limit: 0, page: 1, full: true}, "source", "target")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("keeps checkpoint when sync fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		sourceService := domain.NewMockService(ctrl)
+		sourceService.EXPECT().Name().AnyTimes().Return("Source")
+		sourceService.EXPECT().Authenticated().Return(true)
+		sourceService.EXPECT().GetLovedTracks(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("api error"))
+		sourceService.EXPECT().Close()
+
+		targetService := domain.NewMockService(ctrl)
+		targetService.EXPECT().Name().AnyTimes().Return("Target")
+		targetService.EXPECT().Authenticated().Return(true)
+		targetService.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("source").Return(sourceService, nil)
+		serviceLoader.EXPECT().ForName("target").Return(targetService, nil)
+
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Failures("Source", "Target")
+
+		checkpoints := domain.NewMockCheckpointStore(ctrl)
+
+		_, err := executeSync(serviceLoader, ledger, checkpoints, syncOptions{limit: 10, page: 1}, "source", "target")
+
+		assert.EqualError(t, err, "api error")
+	})
+
+	t.Run("stops cleanly when interrupted", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		trackOne := domain.Track{
+			Artist: "Awesome Artist",
+			Name:   "Blam (Instrumental)",
+		}
+		trackTwo := domain.Track{
+			Artist: "Foo & Bar",
+			Name:   "Mr. Testy",
+		}
+		tracks := []domain.Track{trackOne, trackTwo}
+
+		sourceService := domain.NewMockService(ctrl)
+		sourceService.EXPECT().Name().AnyTimes().Return("Source")
+		sourceService.EXPECT().Authenticated().Return(true)
+		sourceService.EXPECT().GetLovedTracks(gomock.Any(), 2, 1).Return(tracks, nil)
+		sourceService.EXPECT().Close()
+
+		targetService := domain.NewMockService(ctrl)
+		targetService.EXPECT().Name().AnyTimes().Return("Target")
+		targetService.EXPECT().Authenticated().Return(true)
+		targetService.EXPECT().FindTrack(gomock.Any(), trackOne).Return(domain.Match{Track: domain.Track{ID: "targetOne"}, Method: domain.MatchBySearch}, nil)
+		targetService.EXPECT().FindTrack(gomock.Any(), trackTwo).Return(domain.Match{Track: domain.Track{ID: "targetTwo"}, Method: domain.MatchBySearch}, nil)
+		targetService.EXPECT().LoveTrack(gomock.Any(), domain.Track{ID: "targetOne"}).DoAndReturn(func(context.Context, domain.Track) error {
+			cancel()
+			return nil
+		})
+		targetService.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("source").Return(sourceService, nil)
+		serviceLoader.EXPECT().ForName("target").Return(targetService, nil)
+
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Failures("Source", "Target")
+		ledger.EXPECT().Synced("Source", "Target", gomock.Any()).Return(false).Times(2)
+		ledger.EXPECT().Record("Source", "Target", trackOne, "targetOne")
+		ledger.EXPECT().Save()
+
+		buffer := new(bytes.Buffer)
+		output := newPrinter(outputText, buffer)
+		err := output.finish(sync(ctx, serviceLoader, ledger, domain.NewMockCheckpointStore(ctrl), nil, nil, syncOptions{limit: 2, page: 1}, output, []string{"source", "target"}))
+
+		assert.EqualError(t, err, "sync interrupted: context canceled")
+		assert.Equal(t, "Synced: Awesome Artist - Blam (Instrumental) (search)\n", buffer.String())
+	})
+
+	t.Run("looks up tracks concurrently and keeps output ordered", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		trackOne := domain.Track{
+			Artist: "Awesome Artist",
+			Name:   "Blam (Instrumental)",
+		}
+		trackTwo := domain.Track{
+			Artist: "Foo & Bar",
+			Name:   "Mr. Testy",
+		}
+
+		sourceService := domain.NewMockService(ctrl)
+		sourceService.EXPECT().Name().AnyTimes().Return("Source")
+		sourceService.EXPECT().Authenticated().Return(true)
+		sourceService.EXPECT().GetLovedTracks(gomock.Any(), 5, 1).Return([]domain.Track{trackOne, trackTwo}, nil)
+		sourceService.EXPECT().Close()
+
+		// The first lookup only finishes after the second one started, which requires concurrent lookups.
+		secondStarted := make(chan struct{})
+		targetService := domain.NewMockService(ctrl)
+		targetService.EXPECT().Name().AnyTimes().Return("Target")
+		targetService.EXPECT().Authenticated().Return(true)
+		targetService.EXPECT().FindTrack(gomock.Any(), trackOne).DoAndReturn(func(context.Context, domain.Track) (domain.Match, error) {
+			select {
+			case <-secondStarted:
+				return domain.Match{Track: domain.Track{ID: "targetOne"}, Method: domain.MatchBySearch}, nil
+			case <-time.After(5 * time.Second):
+				return domain.Match{}, errors.New("second lookup did not start")
+			}
+		})
+		targetService.EXPECT().FindTrack(gomock.Any(), trackTwo).DoAndReturn(func(context.Context, domain.Track) (domain.Match, error) {
+			close(secondStarted)
+			return domain.Match{Track: domain.Track{ID: "targetTwo"}, Method: domain.MatchBySearch}, nil
+		})
+		targetService.EXPECT().LoveTrack(gomock.Any(), domain.Track{ID: "targetOne"})
+		targetService.EXPECT().LoveTrack(gomock.Any(), domain.Track{ID: "targetTwo"})
+		targetService.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("source").Return(sourceService, nil)
+		serviceLoader.EXPECT().ForName("target").Return(targetService, nil)
+
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Failures("Source", "Target")
+		ledger.EXPECT().Synced("Source", "Target", gomock.Any()).Return(false).Times(2)
+		ledger.EXPECT().Record("Source", "Target", trackOne, "targetOne")
+		ledger.EXPECT().Record("Source", "Target", trackTwo, "targetTwo")
+		ledger.EXPECT().Save()
+
+		got, err := executeSync(serviceLoader, ledger, anyCheckpoints(ctrl), syncOptions{limit: 5, page: 1, concurrency: 4}, "source", "target")
+
+		expected := `Synced: Awesome Artist - Blam (Instrumental) (search)
+Synced: Foo & Bar - Mr. Testy (search)
+Summary: 2 synced, 0 already loved, 0 not found, 0 to review, 0 failed
+`
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got)
+	})
+
+	t.Run("takes tracks from match cache and caches tracks looked up", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		cachedTrack := domain.Track{Artist: "Awesome Artist", Name: "Blam (Instrumental)"}
+		missingTrack := domain.Track{Artist: "Unknown", Name: "Nowhere"}
+		newTrack := domain.Track{Artist: "Foo & Bar", Name: "Mr. Testy"}
+		cachedMatch := domain.Match{Track: domain.Track{ID: "cachedID"}, Confidence: 1, Method: domain.MatchByISRC}
+		newMatch := domain.Match{Track: domain.Track{ID: "newID"}, Confidence: 0.9, Method: domain.MatchBySearch}
+
+		sourceService := domain.NewMockService(ctrl)
+		sourceService.EXPECT().Name().AnyTimes().Return("Source")
+		sourceService.EXPECT().Authenticated().Return(true)
+		sourceService.EXPECT().GetLovedTracks(gomock.Any(), 5, 1).Return([]domain.Track{cachedTrack, missingTrack, newTrack}, nil)
+		sourceService.EXPECT().Close()
+
+		targetService := domain.NewMockService(ctrl)
+		targetService.EXPECT().Name().AnyTimes().Return("Target")
+		targetService.EXPECT().Authenticated().Return(true)
+		targetService.EXPECT().FindTrack(gomock.Any(), newTrack).Return(newMatch, nil)
+		targetService.EXPECT().LoveTrack(gomock.Any(), cachedMatch.Track)
+		targetService.EXPECT().LoveTrack(gomock.Any(), newMatch.Track)
+		targetService.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("source").Return(sourceService, nil)
+		serviceLoader.EXPECT().ForName("target").Return(targetService, nil)
+
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Failures("Source", "Target")
+		ledger.EXPECT().Synced("Source", "Target", gomock.Any()).Return(false).Times(3)
+		ledger.EXPECT().Record("Source", "Target", cachedTrack, "cachedID")
+		ledger.EXPECT().Record("Source", "Target", newTrack, "newID")
+		ledger.EXPECT().Save()
+
+		matches := domain.NewMockMatchCache(ctrl)
+		matches.EXPECT().Lookup("Target", cachedTrack).Return(domain.CachedMatch{Match: cachedMatch}, true)
+		matches.EXPECT().Lookup("Target", missingTrack).Return(domain.CachedMatch{NotFound: true}, true)
+		matches.EXPECT().Lookup("Target", newTrack).Return(domain.CachedMatch{}, false)
+		matches.EXPECT().Store("Target", newTrack, domain.CachedMatch{Match: newMatch})
+		matches.EXPECT().Save()
+
+		buffer := new(bytes.Buffer)
+		output := newPrinter(outputText, buffer)
+		options := syncOptions{limit: 5, page: 1, minConfidence: domain.DefaultThreshold, concurrency: 1}
+		err := output.finish(sync(context.Background(), serviceLoader, ledger, anyCheckpoints(ctrl), nil, matches, options, output, []string{"source", "target"}))
+
+		expected := `Synced: Awesome Artist - Blam (Instrumental) (isrc)
+Not found: Unknown - Nowhere
+Synced: Foo & Bar - Mr. Testy (search)
+Summary: 2 synced, 0 already loved, 1 not found, 0 to review, 0 failed
+`
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, buffer.String())
+	})
+
+	t.Run("returns error when failing to save match cache", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		track := domain.Track{Artist: "Foo & Bar", Name: "Mr. Testy"}
+
+		sourceService := domain.NewMockService(ctrl)
+		sourceService.EXPECT().Name().AnyTimes().Return("Source")
+		sourceService.EXPECT().Authenticated().Return(true)
+		sourceService.EXPECT().GetLovedTracks(gomock.Any(), 5, 1).Return([]domain.Track{track}, nil)
+		sourceService.EXPECT().Close()
+
+		targetService := domain.NewMockService(ctrl)
+		targetService.EXPECT().Name().AnyTimes().Return("Target")
+		targetService.EXPECT().Authenticated().Return(true)
+		targetService.EXPECT().FindTrack(gomock.Any(), track).Return(domain.Match{}, domain.ErrTrackNotFound)
+		targetService.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("source").Return(sourceService, nil)
+		serviceLoader.EXPECT().ForName("target").Return(targetService, nil)
+
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Failures("Source", "Target")
+		ledger.EXPECT().Synced("Source", "Target", track).Return(false)
+
+		matches := domain.NewMockMatchCache(ctrl)
+		matches.EXPECT().Lookup("Target", track).Return(domain.CachedMatch{}, false)
+		matches.EXPECT().Store("Target", track, domain.CachedMatch{NotFound: true})
+		matches.EXPECT().Save().Return(errors.New("write error"))
+
+		buffer := new(bytes.Buffer)
+		output := newPrinter(outputText, buffer)
+		options := syncOptions{limit: 5, page: 1, concurrency: 1}
+		err := output.finish(sync(context.Background(), serviceLoader, ledger, domain.NewMockCheckpointStore(ctrl), nil, matches, options, output, []string{"source", "target"}))
+
+		assert.EqualError(t, err, "failed to save match cache: write error")
+	})
+
+	t.Run("syncs tracks loved since last run and records run", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		lastRun := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+		newTrack := domain.Track{
+			Artist:  "Foo & Bar",
+			Name:    "Mr. Testy",
+			LovedAt: lastRun.Add(time.Hour),
+		}
+		oldTrack := domain.Track{
+			Artist:  "Awesome Artist",
+			Name:    "Blam (Instrumental)",
+			LovedAt: lastRun.Add(-time.Hour),
+		}
+
+		sourceService := domain.NewMockService(ctrl)
+		sourceService.EXPECT().Name().AnyTimes().Return("Source")
+		sourceService.EXPECT().Authenticated().Return(true)
+		sourceService.EXPECT().GetLovedTracks(gomock.Any(), 50, 1).Return([]domain.Track{newTrack, oldTrack}, nil)
+		sourceService.EXPECT().Close()
+
+		targetService := domain.NewMockService(ctrl)
+		targetService.EXPECT().Name().AnyTimes().Return("Target")
+		targetService.EXPECT().Authenticated().Return(true)
+		targetService.EXPECT().FindTrack(gomock.Any(), newTrack).Return(domain.Match{Track: domain.Track{ID: "targetID"}, Method: domain.MatchBySearch}, nil)
+		targetService.EXPECT().LoveTrack(gomock.Any(), domain.Track{ID: "targetID"})
+		targetService.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("source").Return(sourceService, nil)
+		serviceLoader.EXPECT().ForName("target").Return(targetService, nil)
+
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Failures("Source", "Target")
+		ledger.EXPECT().Synced("Source", "Target", newTrack).Return(false)
+		ledger.EXPECT().Record("Source", "Target", newTrack, "targetID")
+		ledger.EXPECT().Save()
+
+		runs := domain.NewMockRunStore(ctrl)
+		runs.EXPECT().LastRun("sync:Source:Target").Return(lastRun, true)
+		runs.EXPECT().Record("sync:Source:Target", gomock.Any())
+
+		buffer := new(bytes.Buffer)
+		output := newPrinter(outputText, buffer)
+		options := syncOptions{limit: 0, page: 1, window: dateWindow{sinceLastRun: true}}
+		err := output.finish(sync(context.Background(), serviceLoader, ledger, anyCheckpoints(ctrl), runs, nil, options, output, []string{"source", "target"}))
+
+		assert.NoError(t, err)
+		assert.Contains(t, buffer.String(), "Synced: Foo & Bar - Mr. Testy (search)\nSummary: 1 synced")
+	})
+
+	t.Run("resumes from page after checkpoint", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		track := domain.Track{
+			Artist: "Foo & Bar",
+			Name:   "Mr. Testy",
+		}
+		checkpoint := domain.Checkpoint{
+			Source:    "Source",
+			Target:    "Target",
+			Page:      37,
+			Limit:     20,
+			LastTrack: track,
+		}
+
+		sourceService := domain.NewMockService(ctrl)
+		sourceService.EXPECT().Name().AnyTimes().Return("Source")
+		sourceService.EXPECT().Authenticated().Return(true)
+		sourceService.EXPECT().GetLovedTracks(gomock.Any(), 20, 38).Return(nil, nil)
+		sourceService.EXPECT().Close()
+
+		targetService := domain.NewMockService(ctrl)
+		targetService.EXPECT().Name().AnyTimes().Return("Target")
+		targetService.EXPECT().Authenticated().Return(true)
+		targetService.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("source").Return(sourceService, nil)
+		serviceLoader.EXPECT().ForName("target").Return(targetService, nil)
+
+		checkpoints := domain.NewMockCheckpointStore(ctrl)
+		checkpoints.EXPECT().Checkpoint("Source", "Target").Return(checkpoint, true)
+		checkpoints.EXPECT().Clear("Source", "Target")
+
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Failures("Source", "Target")
+
+		got, err := executeSync(serviceLoader, ledger, checkpoints, syncOptions{limit: 10, page: 1, resume: true}, "source", "target")
+
+		expected := `Resuming from page 38 after Foo & Bar - Mr. Testy
+Summary: 0 synced, 0 already loved, 0 not found, 0 to review, 0 failed
+`
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got)
+	})
+
+	t.Run("reports tracks not found on target service", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		trackOne := domain.Track{
+			Artist: "Awesome Artist",
+			Name:   "Blam (Instrumental)",
+		}
+		trackTwo := domain.Track{
+			Artist: "Foo & Bar",
+			Name:   "Mr. Testy",
+		}
+		tracks := []domain.Track{trackOne, trackTwo}
+
+		sourceService := domain.NewMockService(ctrl)
+		sourceService.EXPECT().Name().AnyTimes().Return("Source")
+		sourceService.EXPECT().Authenticated().Return(true)
+		sourceService.EXPECT().GetLovedTracks(gomock.Any(), 5, 1).Return(tracks, nil)
+		sourceService.EXPECT().Close()
+
+		targetService := domain.NewMockService(ctrl)
+		targetService.EXPECT().Name().AnyTimes().Return("Target")
+		targetService.EXPECT().Authenticated().Return(true)
+		targetService.EXPECT().FindTrack(gomock.Any(), trackOne).Return(domain.Match{}, fmt.Errorf("%w on Target", domain.ErrTrackNotFound))
+		targetService.EXPECT().FindTrack(gomock.Any(), trackTwo).Return(domain.Match{Track: domain.Track{ID: "targetTwo"}, Method: domain.MatchBySearch}, nil)
+		targetService.EXPECT().LoveTrack(gomock.Any(), domain.Track{ID: "targetTwo"})
+		targetService.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("source").Return(sourceService, nil)
+		serviceLoader.EXPECT().ForName("target").Return(targetService, nil)
+
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Failures("Source", "Target")
+		ledger.EXPECT().Synced(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).Return(false)
+		ledger.EXPECT().Record("Source", "Target", trackTwo, "targetTwo")
+		ledger.EXPECT().Save()
+
+		unmatchedFile := filepath.Join(t.TempDir(), "unmatched.txt")
+		options := syncOptions{limit: 5, page: 1, unmatchedFile: unmatchedFile}
+
+		got, err := executeSync(serviceLoader, ledger, anyCheckpoints(ctrl), options, "source", "target")
+
+		expected := `Not found: Awesome Artist - Blam (Instrumental)
+Synced: Foo & Bar - Mr. Testy (search)
+Summary: 1 synced, 0 already loved, 1 not found, 0 to review, 0 failed
+`
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got)
+
+		contents, err := os.ReadFile(unmatchedFile)
+		assert.NoError(t, err)
+		assert.Equal(t, "Awesome Artist - Blam (Instrumental)\n", string(contents))
+	})
+
+	t.Run("loves tracks in batch when target service supports it", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		trackOne := domain.Track{
+			Artist: "Awesome Artist",
+			Name:   "Blam (Instrumental)",
+		}
+		trackTwo := domain.Track{
+			Artist: "Foo & Bar",
+			Name:   "Mr. Testy",
+		}
+		tracks := []domain.Track{trackOne, trackTwo}
+		targetTrack := domain.Track{ID: "targetOne"}
+
+		sourceService := domain.NewMockService(ctrl)
+		sourceService.EXPECT().Name().AnyTimes().Return("Source")
+		sourceService.EXPECT().Authenticated().Return(true)
+		sourceService.EXPECT().GetLovedTracks(gomock.Any(), 5, 1).Return(tracks, nil)
+		sourceService.EXPECT().Close()
+
+		targetService := batchService{domain.NewMockService(ctrl), domain.NewMockBatchLover(ctrl)}
+		targetService.MockService.EXPECT().Name().AnyTimes().Return("Target")
+		targetService.MockService.EXPECT().Authenticated().Return(true)
+		targetService.MockService.EXPECT().FindTrack(gomock.Any(), trackOne).Return(domain.Match{Track: targetTrack, Method: domain.MatchByISRC}, nil)
+		targetService.MockService.EXPECT().FindTrack(gomock.Any(), trackTwo).Return(domain.Match{}, domain.ErrTrackNotFound)
+		targetService.MockBatchLover.EXPECT().LoveTracks(gomock.Any(), []domain.Track{targetTrack}).Return([]error{nil})
+		targetService.MockService.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("source").Return(sourceService, nil)
+		serviceLoader.EXPECT().ForName("target").Return(targetService, nil)
+
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Failures("Source", "Target")
+		ledger.EXPECT().Synced(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).Return(false)
+		ledger.EXPECT().Record("Source", "Target", trackOne, "targetOne")
+		ledger.EXPECT().Save()
+
+		got, err := executeSync(serviceLoader, ledger, anyCheckpoints(ctrl), syncOptions{limit: 5, page: 1}, "source", "target")
+
+		expected := `Synced: Awesome Artist - Blam (Instrumental) (isrc)
+Not found: Foo & Bar - Mr. Testy
+Summary: 1 synced, 0 already loved, 1 not found, 0 to review, 0 failed
+`
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got)
+	})
+
+	t.Run("reports tracks as failed when batch returns wrong number of results", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		trackOne := domain.Track{Artist: "Awesome Artist", Name: "Blam (Instrumental)"}
+		trackTwo := domain.Track{Artist: "Foo & Bar", Name: "Mr. Testy"}
+		targetOne := domain.Track{ID: "targetOne"}
+		targetTwo := domain.Track{ID: "targetTwo"}
+
+		sourceService := domain.NewMockService(ctrl)
+		sourceService.EXPECT().Name().AnyTimes().Return("Source")
+		sourceService.EXPECT().Authenticated().Return(true)
+		sourceService.EXPECT().GetLovedTracks(gomock.Any(), 5, 1).Return([]domain.Track{trackOne, trackTwo}, nil)
+		sourceService.EXPECT().Close()
+
+		targetService := batchService{domain.NewMockService(ctrl), domain.NewMockBatchLover(ctrl)}
+		targetService.MockService.EXPECT().Name().AnyTimes().Return("Target")
+		targetService.MockService.EXPECT().Authenticated().Return(true)
+		targetService.MockService.EXPECT().FindTrack(gomock.Any(), trackOne).Return(domain.Match{Track: targetOne, Method: domain.MatchByISRC}, nil)
+		targetService.MockService.EXPECT().FindTrack(gomock.Any(), trackTwo).Return(domain.Match{Track: targetTwo, Method: domain.MatchByISRC}, nil)
+		targetService.MockBatchLover.EXPECT().LoveTracks(gomock.Any(), []domain.Track{targetOne, targetTwo}).Return([]error{nil})
+		targetService.MockService.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("source").Return(sourceService, nil)
+		serviceLoader.EXPECT().ForName("target").Return(targetService, nil)
+
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Failures("Source", "Target")
+		ledger.EXPECT().Synced(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).Return(false)
+		ledger.EXPECT().RecordFailure("Source", "Target", gomock.Any()).Times(2)
+		ledger.EXPECT().Save()
+
+		got, err := executeSync(serviceLoader, ledger, anyCheckpoints(ctrl), syncOptions{limit: 5, page: 1}, "source", "target")
+
+		assert.EqualError(t, err, "failed to sync 2 tracks to Target")
+		assert.Contains(t, got, "Target returned 1 results for 2 loved tracks")
+		assert.Contains(t, got, "Summary: 0 synced, 0 already loved, 0 not found, 0 to review, 2 failed")
+	})
+
+	t.Run("shows what would be synced without loving tracks in dry run", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		trackOne := domain.Track{
+			Artist: "Awesome Artist",
+			Name:   "Blam (Instrumental)",
+		}
+		trackTwo := domain.Track{
+			Artist: "Foo & Bar",
+			Name:   "Mr. Testy",
+		}
+		trackThree := domain.Track{
+			Artist: "Unknown",
+			Name:   "Missing",
+		}
+		targetOne := domain.Track{ID: "targetOne"}
+		targetTwo := domain.Track{ID: "targetTwo"}
+
+		sourceService := domain.NewMockService(ctrl)
+		sourceService.EXPECT().Name().AnyTimes().Return("Source")
+		sourceService.EXPECT().Authenticated().Return(true)
+		sourceService.EXPECT().GetLovedTracks(gomock.Any(), 5, 1).Return([]domain.Track{trackOne, trackTwo, trackThree}, nil)
+		sourceService.EXPECT().Close()
+
+		targetService := checkingService{domain.NewMockService(ctrl), domain.NewMockLoveChecker(ctrl)}
+		targetService.MockService.EXPECT().Name().AnyTimes().Return("Target")
+		targetService.MockService.EXPECT().Authenticated().Return(true)
+		targetService.MockService.EXPECT().FindTrack(gomock.Any(), trackOne).Return(domain.Match{Track: targetOne, Method: domain.MatchBySearch}, nil)
+		targetService.MockService.EXPECT().FindTrack(gomock.Any(), trackTwo).Return(domain.Match{Track: targetTwo, Method: domain.MatchByISRC}, nil)
+		targetService.MockService.EXPECT().FindTrack(gomock.Any(), trackThree).Return(domain.Match{}, domain.ErrTrackNotFound)
+		targetService.MockLoveChecker.EXPECT().Loved(gomock.Any(), []domain.Track{targetOne, targetTwo}).Return([]bool{true, false}, nil)
+		targetService.MockService.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("source").Return(sourceService, nil)
+		serviceLoader.EXPECT().ForName("target").Return(targetService, nil)
+
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Failures("Source", "Target")
+		ledger.EXPECT().Synced(gomock.Any(), gomock.Any(), gomock.Any()).Times(3).Return(false)
+
+		checkpoints := domain.NewMockCheckpointStore(ctrl)
+
+		got, err := executeSync(serviceLoader, ledger, checkpoints, syncOptions{limit: 5, page: 1, dryRun: true}, "source", "target")
+
+		expected := `Already loved: Awesome Artist - Blam (Instrumental)
+Would love: Foo & Bar - Mr. Testy (isrc)
+Not found: Unknown - Missing
+Summary: 1 to love, 1 already loved, 1 not found, 0 to review, 0 failed
+`
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got)
+	})
+
+	t.Run("reports tracks as failed when service returns wrong number of loved states in dry run", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		trackOne := domain.Track{Artist: "Awesome Artist", Name: "Blam (Instrumental)"}
+		trackTwo := domain.Track{Artist: "Foo & Bar", Name: "Mr. Testy"}
+		targetOne := domain.Track{ID: "targetOne"}
+		targetTwo := domain.Track{ID: "targetTwo"}
+
+		sourceService := domain.NewMockService(ctrl)
+		sourceService.EXPECT().Name().AnyTimes().Return("Source")
+		sourceService.EXPECT().Authenticated().Return(true)
+		sourceService.EXPECT().GetLovedTracks(gomock.Any(), 5, 1).Return([]domain.Track{trackOne, trackTwo}, nil)
+		sourceService.EXPECT().Close()
+
+		targetService := checkingService{domain.NewMockService(ctrl), domain.NewMockLoveChecker(ctrl)}
+		targetService.MockService.EXPECT().Name().AnyTimes().Return("Target")
+		targetService.MockService.EXPECT().Authenticated().Return(true)
+		targetService.MockService.EXPECT().FindTrack(gomock.Any(), trackOne).Return(domain.Match{Track: targetOne, Method: domain.MatchBySearch}, nil)
+		targetService.MockService.EXPECT().FindTrack(gomock.Any(), trackTwo).Return(domain.Match{Track: targetTwo, Method: domain.MatchBySearch}, nil)
+		targetService.MockLoveChecker.EXPECT().Loved(gomock.Any(), []domain.Track{targetOne, targetTwo}).Return([]bool{true}, nil)
+		targetService.MockService.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("source").Return(sourceService, nil)
+		serviceLoader.EXPECT().ForName("target").Return(targetService, nil)
+
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Failures("Source", "Target")
+		ledger.EXPECT().Synced(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).Return(false)
+
+		got, err := executeSync(serviceLoader, ledger, domain.NewMockCheckpointStore(ctrl), syncOptions{limit: 5, page: 1, dryRun: true}, "source", "target")
+
+		assert.EqualError(t, err, "failed to sync 2 tracks to Target")
+		assert.Contains(t, got, "Target returned 1 loved states for 2 tracks")
+		assert.Contains(t, got, "0 to love, 0 already loved, 0 not found, 0 to review, 2 failed")
+	})
+
+	t.Run("unloves previously synced tracks which are no longer loved on source service", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		kept := domain.Track{
+			Artist: "Foo & Bar",
+			Name:   "Mr. Testy",
+		}
+		removed := domain.Track{
+			Artist: "Awesome Artist",
+			Name:   "Blam (Instrumental)",
+		}
+		entries := []domain.LedgerEntry{
+			{Track: kept, TargetID: "keptID"},
+			{Track: removed, TargetID: "removedID"},
+		}
+
+		sourceService := domain.NewMockService(ctrl)
+		sourceService.EXPECT().Name().AnyTimes().Return("Source")
+		sourceService.EXPECT().Authenticated().Return(true)
+		sourceService.EXPECT().GetLovedTracks(gomock.Any(), 5, 1).Return([]domain.Track{kept}, nil)
+		sourceService.EXPECT().GetLovedTracks(gomock.Any(), 50, 1).Return([]domain.Track{kept}, nil)
+		sourceService.EXPECT().Close()
+
+		targetService := domain.NewMockService(ctrl)
+		targetService.EXPECT().Name().AnyTimes().Return("Target")
+		targetService.EXPECT().Authenticated().Return(true)
+		targetService.EXPECT().UnloveTrack(gomock.Any(), domain.Track{ID: "removedID", Artist: "Awesome Artist", Name: "Blam (Instrumental)"})
+		targetService.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("source").Return(sourceService, nil)
+		serviceLoader.EXPECT().ForName("target").Return(targetService, nil)
+
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Failures("Source", "Target")
+		ledger.EXPECT().Synced("Source", "Target", kept).Return(true)
+		ledger.EXPECT().Entries("Source", "Target").Return(entries)
+		ledger.EXPECT().Remove("Source", "Target", removed)
+		ledger.EXPECT().Save().Times(2)
+
+		got, err := executeSync(serviceLoader, ledger, anyCheckpoints(ctrl), syncOptions{limit: 5, page: 1, mirrorRemovals: true}, "source", "target")
+
+		expected := `Reached previously synced track: Foo & Bar - Mr. Testy
+Unloved: Awesome Artist - Blam (Instrumental)
+Summary: 0 synced, 0 already loved, 0 not found, 0 to review, 0 failed, 1 unloved
+`
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got)
+	})
+
+	t.Run("refuses to unlove tracks when source service has no loved tracks", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		sourceService := domain.NewMockService(ctrl)
+		sourceService.EXPECT().Name().AnyTimes().Return("Source")
 		sourceService.EXPECT().Authenticated().Return(true)
-		sourceService.EXPECT().GetLovedTracks(5, 1).Return(tracks, nil)
+		sourceService.EXPECT().GetLovedTracks(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).Return(nil, nil)
 		sourceService.EXPECT().Close()
 
 		targetService := domain.NewMockService(ctrl)
+		targetService.EXPECT().Name().AnyTimes().Return("Target")
 		targetService.EXPECT().Authenticated().Return(true)
-		targetService.EXPECT().LoveTrack(trackOne).Return(nil)
-		targetService.EXPECT().LoveTrack(trackTwo).Return(nil)
 		targetService.EXPECT().Close()
 
 		serviceLoader := domain.NewMockServiceLoader(ctrl)
 		serviceLoader.EXPECT().ForName("source").Return(sourceService, nil)
 		serviceLoader.EXPECT().ForName("target").Return(targetService, nil)
 
-		got, err := executeSync(serviceLoader, 5, 1, "source", "target")
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Failures("Source", "Target")
+		ledger.EXPECT().Entries("Source", "Target").Return([]domain.LedgerEntry{{Track: domain.Track{Artist: "Foo", Name: "Bar"}}})
+
+		_, err := executeSync(serviceLoader, ledger, anyCheckpoints(ctrl), syncOptions{limit: 5, page: 1, mirrorRemovals: true}, "source", "target")
+
+		assert.EqualError(t, err, "refusing to unlove 1 synced tracks on Target: no loved tracks found on Source")
+	})
+
+	t.Run("reports low confidence matches for review", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		track := domain.Track{
+			Artist: "Foo & Bar",
+			Name:   "Mr. Testy",
+		}
+		lowConfidence := &domain.LowConfidenceError{
+			Track: track,
+			Match: domain.Match{
+				Track:      domain.Track{Artist: "Foo", Name: "Mr. Tasty"},
+				Confidence: 0.62,
+			},
+			Threshold: 0.9,
+		}
+
+		sourceService := domain.NewMockService(ctrl)
+		sourceService.EXPECT().Name().AnyTimes().Return("Source")
+		sourceService.EXPECT().Authenticated().Return(true)
+		sourceService.EXPECT().GetLovedTracks(gomock.Any(), 5, 1).Return([]domain.Track{track}, nil)
+		sourceService.EXPECT().Close()
+
+		targetService := matchingService{domain.NewMockService(ctrl), domain.NewMockMatchingService(ctrl)}
+		targetService.MockService.EXPECT().Name().AnyTimes().Return("Target")
+		targetService.MockService.EXPECT().Authenticated().Return(true)
+		targetService.MockMatchingService.EXPECT().SetMatcher(domain.NewMatcher(0.9))
+		targetService.MockService.EXPECT().FindTrack(gomock.Any(), track).Return(domain.Match{}, lowConfidence)
+		targetService.MockService.EXPECT().Close()
+
+		serviceLoader := domain.NewMockServiceLoader(ctrl)
+		serviceLoader.EXPECT().ForName("source").Return(sourceService, nil)
+		serviceLoader.EXPECT().ForName("target").Return(targetService, nil)
+
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Failures("Source", "Target")
+		ledger.EXPECT().Synced(gomock.Any(), gomock.Any(), gomock.Any()).Return(false)
+		ledger.EXPECT().Save()
+
+		unmatchedFile := filepath.Join(t.TempDir(), "unmatched.txt")
+		options := syncOptions{limit: 5, page: 1, unmatchedFile: unmatchedFile, minConfidence: 0.9}
 
-		expected := `Synced: Awesome Artist - Blam (Instrumental)
-Synced: Foo & Bar - Mr. Testy
+		got, err := executeSync(serviceLoader, ledger, anyCheckpoints(ctrl), options, "source", "target")
+
+		expected := `Review: Foo & Bar - Mr. Testy (best match Foo - Mr. Tasty with confidence 0.62)
+Summary: 0 synced, 0 already loved, 0 not found, 1 to review, 0 failed
 `
 
 		assert.NoError(t, err)
 		assert.Equal(t, expected, got)
+
+		contents, err := os.ReadFile(unmatchedFile)
+		assert.NoError(t, err)
+		assert.Equal(t, "Foo & Bar - Mr. Testy\n", string(contents))
 	})
 
-	t.Run("returns error when failing to mark track as loved", func(t *testing.T) {
+	t.Run("continues syncing and returns error when failing to mark track as loved", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 
 		tracks := []domain.Track{
@@ -60,34 +942,49 @@ Synced: Foo & Bar - Mr. Testy
 		}
 
 		sourceService := domain.NewMockService(ctrl)
+		sourceService.EXPECT().Name().AnyTimes().Return("Source")
 		sourceService.EXPECT().Authenticated().Return(true)
-		sourceService.EXPECT().GetLovedTracks(gomock.Any(), gomock.Any()).Return(tracks, nil)
+		sourceService.EXPECT().GetLovedTracks(gomock.Any(), gomock.Any(), gomock.Any()).Return(tracks, nil)
 		sourceService.EXPECT().Close()
 
 		targetService := domain.NewMockService(ctrl)
+		targetService.EXPECT().Name().AnyTimes().Return("Target")
 		targetService.EXPECT().Authenticated().Return(true)
-		targetService.EXPECT().LoveTrack(gomock.Any()).Return(errors.New("api error"))
+		targetService.EXPECT().FindTrack(gomock.Any(), gomock.Any()).Return(domain.Match{Track: domain.Track{ID: "targetID"}}, nil)
+		targetService.EXPECT().LoveTrack(gomock.Any(), domain.Track{ID: "targetID"}).Return(errors.New("api error"))
 		targetService.EXPECT().Close()
 
 		serviceLoader := domain.NewMockServiceLoader(ctrl)
 		serviceLoader.EXPECT().ForName("source").Return(sourceService, nil)
 		serviceLoader.EXPECT().ForName("target").Return(targetService, nil)
 
-		output, err := executeSync(serviceLoader, 10, 1, "source", "target")
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Failures("Source", "Target")
+		ledger.EXPECT().Synced(gomock.Any(), gomock.Any(), gomock.Any()).Return(false)
+		ledger.EXPECT().RecordFailure("Source", "Target", tracks[0])
+		ledger.EXPECT().Save()
+
+		output, err := executeSync(serviceLoader, ledger, anyCheckpoints(ctrl), syncOptions{limit: 10, page: 1}, "source", "target")
+
+		expected := `Failed: Awesome Artist - Blam (Instrumental) (api error)
+Summary: 0 synced, 0 already loved, 0 not found, 0 to review, 1 failed
+`
 
 		assert.Error(t, err)
-		assert.Empty(t, output)
+		assert.Equal(t, expected, output)
 	})
 
 	t.Run("returns error when failing to read loved tracks", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 
 		sourceService := domain.NewMockService(ctrl)
+		sourceService.EXPECT().Name().AnyTimes().Return("Source")
 		sourceService.EXPECT().Authenticated().Return(true)
-		sourceService.EXPECT().GetLovedTracks(gomock.Any(), gomock.Any()).Return(nil, errors.New("read error"))
+		sourceService.EXPECT().GetLovedTracks(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("read error"))
 		sourceService.EXPECT().Close()
 
 		targetService := domain.NewMockService(ctrl)
+		targetService.EXPECT().Name().AnyTimes().Return("Target")
 		targetService.EXPECT().Authenticated().Return(true)
 		targetService.EXPECT().Close()
 
@@ -95,7 +992,10 @@ Synced: Foo & Bar - Mr. Testy
 		serviceLoader.EXPECT().ForName("source").Return(sourceService, nil)
 		serviceLoader.EXPECT().ForName("target").Return(targetService, nil)
 
-		output, err := executeSync(serviceLoader, 10, 1, "source", "target")
+		ledger := domain.NewMockLedger(ctrl)
+		ledger.EXPECT().Failures("Source", "Target")
+
+		output, err := executeSync(serviceLoader, ledger, anyCheckpoints(ctrl), syncOptions{limit: 10, page: 1}, "source", "target")
 
 		assert.Error(t, err)
 		assert.Empty(t, output)
@@ -107,7 +1007,9 @@ Synced: Foo & Bar - Mr. Testy
 		serviceLoader := domain.NewMockServiceLoader(ctrl)
 		serviceLoader.EXPECT().ForName("source").Return(nil, errors.New("service error"))
 
-		output, err := executeSync(serviceLoader, 10, 1, "source", "target")
+		ledger := domain.NewMockLedger(ctrl)
+
+		output, err := executeSync(serviceLoader, ledger, anyCheckpoints(ctrl), syncOptions{limit: 10, page: 1}, "source", "target")
 
 		assert.Error(t, err)
 		assert.Empty(t, output)
@@ -121,7 +1023,9 @@ Synced: Foo & Bar - Mr. Testy
 		serviceLoader.EXPECT().ForName("source").Return(sourceService, nil)
 		serviceLoader.EXPECT().ForName("target").Return(nil, errors.New("service error"))
 
-		output, err := executeSync(serviceLoader, 10, 1, "source", "target")
+		ledger := domain.NewMockLedger(ctrl)
+
+		output, err := executeSync(serviceLoader, ledger, anyCheckpoints(ctrl), syncOptions{limit: 10, page: 1}, "source", "target")
 
 		assert.Error(t, err)
 		assert.Empty(t, output)
@@ -142,7 +1046,9 @@ Synced: Foo & Bar - Mr. Testy
 		serviceLoader.EXPECT().ForName("source").Return(sourceService, nil)
 		serviceLoader.EXPECT().ForName("target").Return(targetService, nil)
 
-		output, err := executeSync(serviceLoader, 10, 1, "source", "target")
+		ledger := domain.NewMockLedger(ctrl)
+
+		output, err := executeSync(serviceLoader, ledger, anyCheckpoints(ctrl), syncOptions{limit: 10, page: 1}, "source", "target")
 
 		assert.Error(t, err)
 		assert.Empty(t, output)
@@ -164,15 +1070,251 @@ Synced: Foo & Bar - Mr. Testy
 		serviceLoader.EXPECT().ForName("source").Return(sourceService, nil)
 		serviceLoader.EXPECT().ForName("target").Return(targetService, nil)
 
-		output, err := executeSync(serviceLoader, 10, 1, "source", "target")
+		ledger := domain.NewMockLedger(ctrl)
+
+		output, err := executeSync(serviceLoader, ledger, anyCheckpoints(ctrl), syncOptions{limit: 10, page: 1}, "source", "target")
 
 		assert.Error(t, err)
 		assert.Empty(t, output)
 	})
 }
 
-func executeSync(serviceLoader domain.ServiceLoader, limit int, page int, args ...string) (string, error) {
+func TestSyncRetriesFailedTracks(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	newer := domain.Track{Artist: "Newer", Name: "Track"}
+	synced := domain.Track{Artist: "Awesome Artist", Name: "Blam (Instrumental)"}
+	failing := domain.Track{Artist: "Foo & Bar", Name: "Mr. Testy"}
+
+	ctrl := gomock.NewController(t)
+
+	sourceService := domain.NewMockService(ctrl)
+	sourceService.EXPECT().Name().AnyTimes().Return("Source")
+	sourceService.EXPECT().Authenticated().AnyTimes().Return(true)
+	sourceService.EXPECT().Close().AnyTimes()
+
+	targetService := domain.NewMockService(ctrl)
+	targetService.EXPECT().Name().AnyTimes().Return("Target")
+	targetService.EXPECT().Authenticated().AnyTimes().Return(true)
+	targetService.EXPECT().FindTrack(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(ctx context.Context, track domain.Track) (domain.Match, error) {
+		return domain.Match{Track: domain.Track{ID: track.Name}, Method: domain.MatchByName}, nil
+	})
+	targetService.EXPECT().Close().AnyTimes()
+
+	serviceLoader := domain.NewMockServiceLoader(ctrl)
+	serviceLoader.EXPECT().ForName("source").AnyTimes().Return(sourceService, nil)
+	serviceLoader.EXPECT().ForName("target").AnyTimes().Return(targetService, nil)
+
+	ledger, err := state.LoadLedger()
+	assert.NoError(t, err)
+
+	sourceService.EXPECT().GetLovedTracks(gomock.Any(), 10, 1).Return([]domain.Track{synced, failing}, nil)
+	targetService.EXPECT().LoveTrack(gomock.Any(), domain.Track{ID: synced.Name})
+	targetService.EXPECT().LoveTrack(gomock.Any(), domain.Track{ID: failing.Name}).Return(errors.New("api error"))
+
+	_, err = executeSync(serviceLoader, ledger, anyCheckpoints(ctrl), syncOptions{limit: 10, page: 1}, "source", "target")
+
+	assert.EqualError(t, err, "failed to sync 1 tracks to Target")
+
+	sourceService.EXPECT().GetLovedTracks(gomock.Any(), 10, 1).Return([]domain.Track{newer, synced, failing}, nil)
+	targetService.EXPECT().LoveTrack(gomock.Any(), domain.Track{ID: newer.Name})
+	targetService.EXPECT().LoveTrack(gomock.Any(), domain.Track{ID: failing.Name})
+
+	got, err := executeSync(serviceLoader, ledger, anyCheckpoints(ctrl), syncOptions{limit: 10, page: 1}, "source", "target")
+
+	expected := `Synced: Newer - Track (name)
+Synced: Foo & Bar - Mr. Testy (name)
+Summary: 2 synced, 0 already loved, 0 not found, 0 to review, 0 failed
+`
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, got)
+
+	sourceService.EXPECT().GetLovedTracks(gomock.Any(), 10, 1).Return([]domain.Track{newer, synced, failing}, nil)
+
+	got, err = executeSync(serviceLoader, ledger, anyCheckpoints(ctrl), syncOptions{limit: 10, page: 1}, "source", "target")
+
+	expected = `Reached previously synced track: Newer - Track
+Summary: 0 synced, 0 already loved, 0 not found, 0 to review, 0 failed
+`
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, got)
+}
+
+func TestSyncStopsRetryingFailedTracksNoLongerLoved(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	newer := domain.Track{Artist: "Newer", Name: "Track"}
+	synced := domain.Track{Artist: "Awesome Artist", Name: "Blam (Instrumental)"}
+	failing := domain.Track{Artist: "Foo & Bar", Name: "Mr. Testy"}
+
+	ctrl := gomock.NewController(t)
+
+	sourceService := domain.NewMockService(ctrl)
+	sourceService.EXPECT().Name().AnyTimes().Return("Source")
+	sourceService.EXPECT().Authenticated().AnyTimes().Return(true)
+	sourceService.EXPECT().Close().AnyTimes()
+
+	targetService := domain.NewMockService(ctrl)
+	targetService.EXPECT().Name().AnyTimes().Return("Target")
+	targetService.EXPECT().Authenticated().AnyTimes().Return(true)
+	targetService.EXPECT().FindTrack(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(ctx context.Context, track domain.Track) (domain.Match, error) {
+		return domain.Match{Track: domain.Track{ID: track.Name}, Method: domain.MatchByName}, nil
+	})
+	targetService.EXPECT().Close().AnyTimes()
+
+	serviceLoader := domain.NewMockServiceLoader(ctrl)
+	serviceLoader.EXPECT().ForName("source").AnyTimes().Return(sourceService, nil)
+	serviceLoader.EXPECT().ForName("target").AnyTimes().Return(targetService, nil)
+
+	ledger, err := state.LoadLedger()
+	assert.NoError(t, err)
+
+	sourceService.EXPECT().GetLovedTracks(gomock.Any(), 50, 1).Return([]domain.Track{synced, failing}, nil)
+	targetService.EXPECT().LoveTrack(gomock.Any(), domain.Track{ID: synced.Name})
+	targetService.EXPECT().LoveTrack(gomock.Any(), domain.Track{ID: failing.Name}).Return(errors.New("api error"))
+
+	_, err = executeSync(serviceLoader, ledger, anyCheckpoints(ctrl), syncOptions{limit: 0, page: 1}, "source", "target")
+
+	assert.EqualError(t, err, "failed to sync 1 tracks to Target")
+	assert.Len(t, ledger.Failures("Source", "Target"), 1)
+
+	sourceService.EXPECT().GetLovedTracks(gomock.Any(), 50, 1).Return([]domain.Track{synced}, nil)
+
+	_, err = executeSync(serviceLoader, ledger, anyCheckpoints(ctrl), syncOptions{limit: 0, page: 1}, "source", "target")
+
+	assert.NoError(t, err)
+	assert.Empty(t, ledger.Failures("Source", "Target"))
+
+	sourceService.EXPECT().GetLovedTracks(gomock.Any(), 50, 1).Return([]domain.Track{newer, synced}, nil)
+	targetService.EXPECT().LoveTrack(gomock.Any(), domain.Track{ID: newer.Name})
+
+	got, err := executeSync(serviceLoader, ledger, anyCheckpoints(ctrl), syncOptions{limit: 0, page: 1}, "source", "target")
+
+	expected := `Synced: Newer - Track (name)
+Reached previously synced track: Awesome Artist - Blam (Instrumental)
+Summary: 1 synced, 0 already loved, 0 not found, 0 to review, 0 failed
+`
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, got)
+}
+
+func TestSyncKeepsTracksAlreadyLovedOnTarget(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	existing := domain.Track{Artist: "Foo & Bar", Name: "Mr. Testy"}
+	added := domain.Track{Artist: "Awesome Artist", Name: "Blam (Instrumental)"}
+
+	ctrl := gomock.NewController(t)
+
+	sourceService := domain.NewMockService(ctrl)
+	sourceService.EXPECT().Name().AnyTimes().Return("Source")
+	sourceService.EXPECT().Authenticated().AnyTimes().Return(true)
+	sourceService.EXPECT().Close().AnyTimes()
+
+	targetService := checkingService{domain.NewMockService(ctrl), domain.NewMockLoveChecker(ctrl)}
+	targetService.MockService.EXPECT().Name().AnyTimes().Return("Target")
+	targetService.MockService.EXPECT().Authenticated().AnyTimes().Return(true)
+	targetService.MockService.EXPECT().FindTrack(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(ctx context.Context, track domain.Track) (domain.Match, error) {
+		return domain.Match{Track: domain.Track{ID: track.Name}, Method: domain.MatchByName}, nil
+	})
+	targetService.MockService.EXPECT().Close().AnyTimes()
+
+	serviceLoader := domain.NewMockServiceLoader(ctrl)
+	serviceLoader.EXPECT().ForName("source").AnyTimes().Return(sourceService, nil)
+	serviceLoader.EXPECT().ForName("target").AnyTimes().Return(targetService, nil)
+
+	ledger, err := state.LoadLedger()
+	assert.NoError(t, err)
+
+	sourceService.EXPECT().GetLovedTracks(gomock.Any(), 10, 1).Return([]domain.Track{existing, added}, nil)
+	targetService.MockLoveChecker.EXPECT().Loved(gomock.Any(), []domain.Track{{ID: existing.Name}, {ID: added.Name}}).Return([]bool{true, false}, nil)
+	targetService.MockService.EXPECT().LoveTrack(gomock.Any(), domain.Track{ID: added.Name})
+
+	got, err := executeSync(serviceLoader, ledger, anyCheckpoints(ctrl), syncOptions{limit: 10, page: 1}, "source", "target")
+
+	expected := `Already loved: Foo & Bar - Mr. Testy
+Synced: Awesome Artist - Blam (Instrumental) (name)
+Summary: 1 synced, 1 already loved, 0 not found, 0 to review, 0 failed
+`
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, got)
+
+	sourceService.EXPECT().GetLovedTracks(gomock.Any(), 10, 1).Return([]domain.Track{added}, nil)
+	sourceService.EXPECT().GetLovedTracks(gomock.Any(), 50, 1).Return([]domain.Track{added}, nil)
+
+	got, err = executeSync(serviceLoader, ledger, anyCheckpoints(ctrl), syncOptions{limit: 10, page: 1, mirrorRemovals: true}, "source", "target")
+
+	expected = `Reached previously synced track: Awesome Artist - Blam (Instrumental)
+Summary: 0 synced, 0 already loved, 0 not found, 0 to review, 0 failed, 0 unloved
+`
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, got)
+}
+
+func TestSyncFiles(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	directory := t.TempDir()
+	source := filepath.Join(directory, "source.csv")
+	target := filepath.Join(directory, "target.json")
+
+	err := os.WriteFile(source, []byte("artist,name,loved_at\nFoo & Bar,Mr. Testy,2024-01-02T03:04:05Z\nAwesome Artist,Blam,2024-01-01T00:00:00Z\n"), 0644)
+	assert.NoError(t, err)
+
+	ledger, err := state.LoadLedger()
+	assert.NoError(t, err)
+	checkpoints, err := state.LoadCheckpoints()
+	assert.NoError(t, err)
+
+	got, err := executeSync(services.AvailableServices, ledger, checkpoints, syncOptions{limit: 0, page: 1}, "file:"+source, "file:"+target)
+
+	expected := `Synced: Foo & Bar - Mr. Testy (name)
+Synced: Awesome Artist - Blam (name)
+Summary: 2 synced, 0 already loved, 0 not found, 0 to review, 0 failed
+`
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, got)
+
+	got, err = executeSync(services.AvailableServices, ledger, checkpoints, syncOptions{limit: 0, page: 1}, "file:"+target, "file:"+filepath.Join(directory, "restored.csv"))
+
+	assert.NoError(t, err)
+	assert.Contains(t, got, "Summary: 2 synced")
+
+	restored, err := os.ReadFile(filepath.Join(directory, "restored.csv"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(restored), "Foo & Bar,Mr. Testy,,,,,,,,,2024-01-02T03:04:05Z\nAwesome Artist,Blam,")
+}
+
+func executeSync(serviceLoader domain.ServiceLoader, ledger domain.Ledger, checkpoints domain.CheckpointStore, options syncOptions, args ...string) (string, error) {
 	buffer := new(bytes.Buffer)
-	err := sync(serviceLoader, limit, page, buffer, args)
+	output := newPrinter(outputText, buffer)
+	err := output.finish(sync(context.Background(), serviceLoader, ledger, checkpoints, nil, nil, options, output, args))
 	return buffer.String(), err
 }
+
+func anyCheckpoints(ctrl *gomock.Controller) domain.CheckpointStore {
+	checkpoints := domain.NewMockCheckpointStore(ctrl)
+	checkpoints.EXPECT().Save(gomock.Any()).AnyTimes()
+	checkpoints.EXPECT().Clear(gomock.Any(), gomock.Any()).AnyTimes()
+	return checkpoints
+}
+
+type matchingService struct {
+	*domain.MockService
+	*domain.MockMatchingService
+}
+
+type batchService struct {
+	*domain.MockService
+	*domain.MockBatchLover
+}
+
+type checkingService struct {
+	*domain.MockService
+	*domain.MockLoveChecker
+}
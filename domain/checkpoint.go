@@ -0,0 +1,19 @@
+//go:generate mockgen -source checkpoint.go -destination checkpoint_mock.go -package domain
+
+package domain
+
+// Checkpoint records the last page synced between two services.
+type Checkpoint struct {
+	Source    string
+	Target    string
+	Page      int
+	Limit     int
+	LastTrack Track
+}
+
+// CheckpointStore keeps the checkpoints of unfinished syncs.
+type CheckpointStore interface {
+	Checkpoint(source string, target string) (checkpoint Checkpoint, exists bool)
+	Save(checkpoint Checkpoint) error
+	Clear(source string, target string) error
+}
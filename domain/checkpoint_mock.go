@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: checkpoint.go
+//
+// Generated by this command:
+//
+//	mockgen -source checkpoint.go -destination checkpoint_mock.go -package domain
+//
+
+// Package domain is a generated GoMock package.
+package domain
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCheckpointStore is a mock of CheckpointStore interface.
+type MockCheckpointStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockCheckpointStoreMockRecorder
+}
+
+// MockCheckpointStoreMockRecorder is the mock recorder for MockCheckpointStore.
+type MockCheckpointStoreMockRecorder struct {
+	mock *MockCheckpointStore
+}
+
+// NewMockCheckpointStore creates a new mock instance.
+func NewMockCheckpointStore(ctrl *gomock.Controller) *MockCheckpointStore {
+	mock := &MockCheckpointStore{ctrl: ctrl}
+	mock.recorder = &MockCheckpointStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCheckpointStore) EXPECT() *MockCheckpointStoreMockRecorder {
+	return m.recorder
+}
+
+// Checkpoint mocks base method.
+func (m *MockCheckpointStore) Checkpoint(source, target string) (Checkpoint, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Checkpoint", source, target)
+	ret0, _ := ret[0].(Checkpoint)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Checkpoint indicates an expected call of Checkpoint.
+func (mr *MockCheckpointStoreMockRecorder) Checkpoint(source, target any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Checkpoint", reflect.TypeOf((*MockCheckpointStore)(nil).Checkpoint), source, target)
+}
+
+// Clear mocks base method.
+func (m *MockCheckpointStore) Clear(source, target string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clear", source, target)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Clear indicates an expected call of Clear.
+func (mr *MockCheckpointStoreMockRecorder) Clear(source, target any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clear", reflect.TypeOf((*MockCheckpointStore)(nil).Clear), source, target)
+}
+
+// Save mocks base method.
+func (m *MockCheckpointStore) Save(checkpoint Checkpoint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", checkpoint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockCheckpointStoreMockRecorder) Save(checkpoint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockCheckpointStore)(nil).Save), checkpoint)
+}
@@ -0,0 +1,29 @@
+//go:generate mockgen -source ledger.go -destination ledger_mock.go -package domain
+
+package domain
+
+import "time"
+
+// LedgerEntry is a track which was synced from a source to a target service.
+type LedgerEntry struct {
+	// Track is the track as read from the source service.
+	Track    Track
+	TargetID string
+	SyncedAt time.Time
+	// Preexisting is whether the track was already loved on the target service before it was synced.
+	// Such tracks were not loved by admirer, so they are never unloved when removing synced tracks.
+	Preexisting bool
+}
+
+// Ledger keeps track of the tracks which were synced between services.
+// Tracks which failed to sync are kept as well, so they can be retried until they are synced.
+type Ledger interface {
+	Synced(source string, target string, track Track) bool
+	Record(source string, target string, track Track, targetID string)
+	RecordPreexisting(source string, target string, track Track, targetID string)
+	RecordFailure(source string, target string, track Track)
+	Entries(source string, target string) []LedgerEntry
+	Failures(source string, target string) []Track
+	Remove(source string, target string, track Track)
+	Save() error
+}
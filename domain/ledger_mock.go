@@ -0,0 +1,143 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ledger.go
+//
+// Generated by this command:
+//
+//	mockgen -source ledger.go -destination ledger_mock.go -package domain
+//
+
+// Package domain is a generated GoMock package.
+package domain
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLedger is a mock of Ledger interface.
+type MockLedger struct {
+	ctrl     *gomock.Controller
+	recorder *MockLedgerMockRecorder
+}
+
+// MockLedgerMockRecorder is the mock recorder for MockLedger.
+type MockLedgerMockRecorder struct {
+	mock *MockLedger
+}
+
+// NewMockLedger creates a new mock instance.
+func NewMockLedger(ctrl *gomock.Controller) *MockLedger {
+	mock := &MockLedger{ctrl: ctrl}
+	mock.recorder = &MockLedgerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLedger) EXPECT() *MockLedgerMockRecorder {
+	return m.recorder
+}
+
+// Entries mocks base method.
+func (m *MockLedger) Entries(source, target string) []LedgerEntry {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Entries", source, target)
+	ret0, _ := ret[0].([]LedgerEntry)
+	return ret0
+}
+
+// Entries indicates an expected call of Entries.
+func (mr *MockLedgerMockRecorder) Entries(source, target any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Entries", reflect.TypeOf((*MockLedger)(nil).Entries), source, target)
+}
+
+// Failures mocks base method.
+func (m *MockLedger) Failures(source, target string) []Track {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Failures", source, target)
+	ret0, _ := ret[0].([]Track)
+	return ret0
+}
+
+// Failures indicates an expected call of Failures.
+func (mr *MockLedgerMockRecorder) Failures(source, target any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Failures", reflect.TypeOf((*MockLedger)(nil).Failures), source, target)
+}
+
+// Record mocks base method.
+func (m *MockLedger) Record(source, target string, track Track, targetID string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Record", source, target, track, targetID)
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockLedgerMockRecorder) Record(source, target, track, targetID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockLedger)(nil).Record), source, target, track, targetID)
+}
+
+// RecordFailure mocks base method.
+func (m *MockLedger) RecordFailure(source, target string, track Track) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordFailure", source, target, track)
+}
+
+// RecordFailure indicates an expected call of RecordFailure.
+func (mr *MockLedgerMockRecorder) RecordFailure(source, target, track any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailure", reflect.TypeOf((*MockLedger)(nil).RecordFailure), source, target, track)
+}
+
+// RecordPreexisting mocks base method.
+func (m *MockLedger) RecordPreexisting(source, target string, track Track, targetID string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordPreexisting", source, target, track, targetID)
+}
+
+// RecordPreexisting indicates an expected call of RecordPreexisting.
+func (mr *MockLedgerMockRecorder) RecordPreexisting(source, target, track, targetID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordPreexisting", reflect.TypeOf((*MockLedger)(nil).RecordPreexisting), source, target, track, targetID)
+}
+
+// Remove mocks base method.
+func (m *MockLedger) Remove(source, target string, track Track) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Remove", source, target, track)
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockLedgerMockRecorder) Remove(source, target, track any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockLedger)(nil).Remove), source, target, track)
+}
+
+// Save mocks base method.
+func (m *MockLedger) Save() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockLedgerMockRecorder) Save() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockLedger)(nil).Save))
+}
+
+// Synced mocks base method.
+func (m *MockLedger) Synced(source, target string, track Track) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Synced", source, target, track)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Synced indicates an expected call of Synced.
+func (mr *MockLedgerMockRecorder) Synced(source, target, track any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Synced", reflect.TypeOf((*MockLedger)(nil).Synced), source, target, track)
+}
@@ -0,0 +1,210 @@
+//go:generate mockgen -source loved_tracks.go -destination loved_tracks_mock.go -package domain
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultPageSize is the number of loved tracks requested at once when no page size is given.
+const DefaultPageSize = 50
+
+// TrackPage is a page of loved tracks, along with the total number of loved tracks on the service.
+type TrackPage struct {
+	Tracks []Track
+	Total  int
+}
+
+// PagingService is implemented by services which know the total number of loved tracks when returning a page.
+type PagingService interface {
+	GetLovedTracksPage(ctx context.Context, limit int, page int) (TrackPage, error)
+}
+
+// LovedTracksOptions select which loved tracks are iterated over.
+type LovedTracksOptions struct {
+	// PageSize is the number of tracks requested at once, or DefaultPageSize when zero.
+	PageSize int
+	// Offset is the number of most recently loved tracks to skip.
+	Offset int
+	// Limit is the maximum number of tracks, or zero for all loved tracks.
+	Limit int
+	// Since skips tracks loved before this time, unless it is zero.
+	Since time.Time
+	// Until skips tracks loved at or after this time, unless it is zero.
+	Until time.Time
+}
+
+// LovedTracks iterates over the loved tracks of a service, requesting them page by page.
+// The iteration ends at the total number of tracks when the service reports it, and otherwise at the first page which is not full.
+// As services return the most recently loved tracks first, it also ends at the first track loved before Since.
+// Tracks without a loved time are never skipped by Since and Until.
+//
+//	lovedTracks := domain.NewLovedTracks(service, domain.LovedTracksOptions{})
+//	for lovedTracks.Next(ctx) {
+//		for _, track := range lovedTracks.Tracks() {
+//			...
+//		}
+//	}
+//	if err := lovedTracks.Err(); err != nil {
+//		...
+//	}
+type LovedTracks struct {
+	service    Service
+	options    LovedTracksOptions
+	nextPage   int
+	page       int
+	tracks     []Track
+	returned   int
+	total      int
+	totalKnown bool
+	done       bool
+	err        error
+}
+
+// NewLovedTracks returns an iterator over the loved tracks of the service.
+func NewLovedTracks(service Service, options LovedTracksOptions) *LovedTracks {
+	if options.PageSize <= 0 {
+		options.PageSize = DefaultPageSize
+	}
+	if options.Offset < 0 {
+		options.Offset = 0
+	}
+
+	return &LovedTracks{
+		service:  service,
+		options:  options,
+		nextPage: options.Offset/options.PageSize + 1,
+	}
+}
+
+// Next requests the next page of tracks, and returns false when there are no more tracks or requesting them failed.
+func (l *LovedTracks) Next(ctx context.Context) bool {
+	for !l.done && l.err == nil {
+		if l.options.Limit > 0 && l.returned >= l.options.Limit {
+			break
+		}
+
+		start := (l.nextPage - 1) * l.options.PageSize
+		if l.totalKnown && start >= l.total {
+			break
+		}
+
+		tracks, err := l.request(ctx, l.nextPage)
+		if err != nil {
+			l.err = err
+			break
+		}
+
+		l.page = l.nextPage
+		l.nextPage++
+
+		if len(tracks) == 0 || (!l.totalKnown && len(tracks) < l.options.PageSize) {
+			l.done = true
+		}
+
+		if skip := l.options.Offset - start; skip > 0 {
+			if skip > len(tracks) {
+				skip = len(tracks)
+			}
+			tracks = tracks[skip:]
+		}
+
+		tracks = l.filter(tracks)
+
+		if remaining := l.options.Limit - l.returned; l.options.Limit > 0 && len(tracks) > remaining {
+			tracks = tracks[:remaining]
+		}
+
+		if len(tracks) > 0 {
+			l.tracks = tracks
+			l.returned += len(tracks)
+			return true
+		}
+	}
+
+	l.tracks = nil
+	l.done = true
+	return false
+}
+
+// filter returns the tracks loved between Since and Until, and ends the iteration when reaching a track loved before Since.
+func (l *LovedTracks) filter(tracks []Track) []Track {
+	if l.options.Since.IsZero() && l.options.Until.IsZero() {
+		return tracks
+	}
+
+	filtered := make([]Track, 0, len(tracks))
+	for _, track := range tracks {
+		if track.LovedAt.IsZero() {
+			filtered = append(filtered, track)
+			continue
+		}
+		if !l.options.Since.IsZero() && track.LovedAt.Before(l.options.Since) {
+			l.done = true
+			break
+		}
+		if !l.options.Until.IsZero() && !track.LovedAt.Before(l.options.Until) {
+			continue
+		}
+		filtered = append(filtered, track)
+	}
+	return filtered
+}
+
+func (l *LovedTracks) request(ctx context.Context, page int) ([]Track, error) {
+	pagingService, ok := l.service.(PagingService)
+	if !ok {
+		return l.service.GetLovedTracks(ctx, l.options.PageSize, page)
+	}
+
+	result, err := pagingService.GetLovedTracksPage(ctx, l.options.PageSize, page)
+	if err != nil {
+		return nil, err
+	}
+
+	l.total = result.Total
+	l.totalKnown = true
+	return result.Tracks, nil
+}
+
+// Tracks returns the tracks of the current page.
+func (l *LovedTracks) Tracks() []Track {
+	return l.tracks
+}
+
+// Page returns the number of the current page, counting from 1 in pages of the page size.
+func (l *LovedTracks) Page() int {
+	return l.page
+}
+
+// Total returns the total number of loved tracks on the service, and whether the service reported it.
+// It is only known after requesting the first page.
+func (l *LovedTracks) Total() (total int, known bool) {
+	return l.total, l.totalKnown
+}
+
+// Err returns the error which ended the iteration, if any.
+func (l *LovedTracks) Err() error {
+	return l.err
+}
+
+// All returns all remaining tracks.
+func (l *LovedTracks) All(ctx context.Context) ([]Track, error) {
+	var tracks []Track
+	for l.Next(ctx) {
+		if total, known := l.Total(); known && tracks == nil {
+			capacity := total - l.options.Offset
+			if l.options.Limit > 0 && l.options.Limit < capacity {
+				capacity = l.options.Limit
+			}
+			if capacity < len(l.Tracks()) {
+				capacity = len(l.Tracks())
+			}
+			tracks = make([]Track, 0, capacity)
+		}
+		tracks = append(tracks, l.Tracks()...)
+	}
+
+	return tracks, l.Err()
+}
@@ -0,0 +1,55 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: loved_tracks.go
+//
+// Generated by this command:
+//
+//	mockgen -source loved_tracks.go -destination loved_tracks_mock.go -package domain
+//
+
+// Package domain is a generated GoMock package.
+package domain
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPagingService is a mock of PagingService interface.
+type MockPagingService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPagingServiceMockRecorder
+}
+
+// MockPagingServiceMockRecorder is the mock recorder for MockPagingService.
+type MockPagingServiceMockRecorder struct {
+	mock *MockPagingService
+}
+
+// NewMockPagingService creates a new mock instance.
+func NewMockPagingService(ctrl *gomock.Controller) *MockPagingService {
+	mock := &MockPagingService{ctrl: ctrl}
+	mock.recorder = &MockPagingServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPagingService) EXPECT() *MockPagingServiceMockRecorder {
+	return m.recorder
+}
+
+// GetLovedTracksPage mocks base method.
+func (m *MockPagingService) GetLovedTracksPage(ctx context.Context, limit, page int) (TrackPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLovedTracksPage", ctx, limit, page)
+	ret0, _ := ret[0].(TrackPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLovedTracksPage indicates an expected call of GetLovedTracksPage.
+func (mr *MockPagingServiceMockRecorder) GetLovedTracksPage(ctx, limit, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLovedTracksPage", reflect.TypeOf((*MockPagingService)(nil).GetLovedTracksPage), ctx, limit, page)
+}
@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLovedTracks(t *testing.T) {
+	ctx := context.Background()
+
+	newTracks := func(from int, count int) []Track {
+		tracks := make([]Track, count)
+		for index := range tracks {
+			tracks[index] = Track{ID: strconv.Itoa(from + index)}
+		}
+		return tracks
+	}
+
+	collect := func(lovedTracks *LovedTracks) (pages [][]Track) {
+		for lovedTracks.Next(ctx) {
+			pages = append(pages, lovedTracks.Tracks())
+		}
+		return
+	}
+
+	t.Run("requests pages until page is not full", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		service := NewMockService(ctrl)
+		gomock.InOrder(
+			service.EXPECT().GetLovedTracks(ctx, 2, 1).Return(newTracks(1, 2), nil),
+			service.EXPECT().GetLovedTracks(ctx, 2, 2).Return(newTracks(3, 1), nil),
+		)
+
+		lovedTracks := NewLovedTracks(service, LovedTracksOptions{PageSize: 2})
+
+		assert.Equal(t, [][]Track{newTracks(1, 2), newTracks(3, 1)}, collect(lovedTracks))
+		assert.Equal(t, 2, lovedTracks.Page())
+		assert.NoError(t, lovedTracks.Err())
+
+		_, known := lovedTracks.Total()
+		assert.False(t, known)
+	})
+
+	t.Run("stops at total reported by service", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		service := struct {
+			*MockService
+			*MockPagingService
+		}{NewMockService(ctrl), NewMockPagingService(ctrl)}
+		gomock.InOrder(
+			service.MockPagingService.EXPECT().GetLovedTracksPage(ctx, 2, 1).Return(TrackPage{Tracks: newTracks(1, 2), Total: 4}, nil),
+			service.MockPagingService.EXPECT().GetLovedTracksPage(ctx, 2, 2).Return(TrackPage{Tracks: newTracks(3, 2), Total: 4}, nil),
+		)
+
+		lovedTracks := NewLovedTracks(service, LovedTracksOptions{PageSize: 2})
+		got, err := lovedTracks.All(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, newTracks(1, 4), got)
+
+		total, known := lovedTracks.Total()
+		assert.True(t, known)
+		assert.Equal(t, 4, total)
+	})
+
+	t.Run("starts at offset and stops at limit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		service := NewMockService(ctrl)
+		gomock.InOrder(
+			service.EXPECT().GetLovedTracks(ctx, 3, 2).Return(newTracks(4, 3), nil),
+			service.EXPECT().GetLovedTracks(ctx, 3, 3).Return(newTracks(7, 3), nil),
+		)
+
+		lovedTracks := NewLovedTracks(service, LovedTracksOptions{PageSize: 3, Offset: 4, Limit: 4})
+
+		assert.Equal(t, [][]Track{newTracks(5, 2), newTracks(7, 2)}, collect(lovedTracks))
+		assert.Equal(t, 3, lovedTracks.Page())
+	})
+
+	t.Run("skips tracks outside of dates and stops at first track loved before since", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		day := func(day int) time.Time {
+			return time.Date(2024, 1, day, 12, 0, 0, 0, time.UTC)
+		}
+		firstPage := []Track{{ID: "1", LovedAt: day(9)}, {ID: "2", LovedAt: day(8)}}
+		secondPage := []Track{{ID: "3", LovedAt: day(7)}, {ID: "4"}}
+		thirdPage := []Track{{ID: "5", LovedAt: day(5)}, {ID: "6", LovedAt: day(6)}}
+
+		service := NewMockService(ctrl)
+		gomock.InOrder(
+			service.EXPECT().GetLovedTracks(ctx, 2, 1).Return(firstPage, nil),
+			service.EXPECT().GetLovedTracks(ctx, 2, 2).Return(secondPage, nil),
+			service.EXPECT().GetLovedTracks(ctx, 2, 3).Return(thirdPage, nil),
+		)
+
+		lovedTracks := NewLovedTracks(service, LovedTracksOptions{PageSize: 2, Since: day(6), Until: day(9)})
+
+		assert.Equal(t, [][]Track{firstPage[1:], secondPage}, collect(lovedTracks))
+		assert.NoError(t, lovedTracks.Err())
+	})
+
+	t.Run("uses default page size", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		service := NewMockService(ctrl)
+		service.EXPECT().GetLovedTracks(ctx, DefaultPageSize, 1).Return(nil, nil)
+
+		got, err := NewLovedTracks(service, LovedTracksOptions{}).All(ctx)
+
+		assert.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("returns error of failing page", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		service := NewMockService(ctrl)
+		service.EXPECT().GetLovedTracks(ctx, 2, 1).Return(newTracks(1, 2), nil)
+		service.EXPECT().GetLovedTracks(ctx, 2, 2).Return(nil, errors.New("api error"))
+
+		lovedTracks := NewLovedTracks(service, LovedTracksOptions{PageSize: 2})
+
+		assert.Equal(t, [][]Track{newTracks(1, 2)}, collect(lovedTracks))
+		assert.EqualError(t, lovedTracks.Err(), "api error")
+		assert.False(t, lovedTracks.Next(ctx))
+	})
+}
@@ -0,0 +1,24 @@
+//go:generate mockgen -source match_cache.go -destination match_cache_mock.go -package domain
+
+package domain
+
+import "time"
+
+// DefaultMatchCacheTTL is how long tracks found or not found on a service are remembered by default.
+const DefaultMatchCacheTTL = 30 * 24 * time.Hour
+
+// CachedMatch is the outcome of looking up a track on a target service before.
+type CachedMatch struct {
+	Match Match
+	// NotFound is set for tracks which could not be found on the target service.
+	NotFound bool
+}
+
+// MatchCache remembers the tracks found on target services, so repeated syncs do not look them up again.
+// Tracks are identified by their ISRC, MusicBrainz identifier or normalised artist and title.
+type MatchCache interface {
+	// Lookup returns the cached outcome for the track on the target service, unless it is unknown or expired.
+	Lookup(target string, track Track) (match CachedMatch, exists bool)
+	Store(target string, track Track, match CachedMatch)
+	Save() error
+}
@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: match_cache.go
+//
+// Generated by this command:
+//
+//	mockgen -source match_cache.go -destination match_cache_mock.go -package domain
+//
+
+// Package domain is a generated GoMock package.
+package domain
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMatchCache is a mock of MatchCache interface.
+type MockMatchCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockMatchCacheMockRecorder
+}
+
+// MockMatchCacheMockRecorder is the mock recorder for MockMatchCache.
+type MockMatchCacheMockRecorder struct {
+	mock *MockMatchCache
+}
+
+// NewMockMatchCache creates a new mock instance.
+func NewMockMatchCache(ctrl *gomock.Controller) *MockMatchCache {
+	mock := &MockMatchCache{ctrl: ctrl}
+	mock.recorder = &MockMatchCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMatchCache) EXPECT() *MockMatchCacheMockRecorder {
+	return m.recorder
+}
+
+// Lookup mocks base method.
+func (m *MockMatchCache) Lookup(target string, track Track) (CachedMatch, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lookup", target, track)
+	ret0, _ := ret[0].(CachedMatch)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Lookup indicates an expected call of Lookup.
+func (mr *MockMatchCacheMockRecorder) Lookup(target, track any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lookup", reflect.TypeOf((*MockMatchCache)(nil).Lookup), target, track)
+}
+
+// Save mocks base method.
+func (m *MockMatchCache) Save() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockMatchCacheMockRecorder) Save() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockMatchCache)(nil).Save))
+}
+
+// Store mocks base method.
+func (m *MockMatchCache) Store(target string, track Track, match CachedMatch) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Store", target, track, match)
+}
+
+// Store indicates an expected call of Store.
+func (mr *MockMatchCacheMockRecorder) Store(target, track, match any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Store", reflect.TypeOf((*MockMatchCache)(nil).Store), target, track, match)
+}
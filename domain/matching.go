@@ -0,0 +1,261 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultThreshold is the default minimum confidence for accepting a match.
+const DefaultThreshold = 0.8
+
+var (
+	featuringPattern  = regexp.MustCompile(`(?i)\s*[\(\[]\s*(feat\.?|ft\.?|featuring|with)\s[^\)\]]*[\)\]]|\s+(feat\.?|ft\.?|featuring)\s.*$`)
+	bracketPattern    = regexp.MustCompile(`\s*[\(\[]([^\)\]]*)[\)\]]`)
+	dashSuffixPattern = regexp.MustCompile(`\s+-\s+(.*)$`)
+	versionPattern    = regexp.MustCompile(`(?i)\b(remaster(ed)?|single version|album version|mono|stereo|deluxe|bonus track|explicit)\b`)
+	artistSeparator   = regexp.MustCompile(`(?i)\s*(,|&|\band\b|\bx\b|\bvs\.?|\bfeat\.?|\bft\.?|\bfeaturing\b|\bwith\b)\s*`)
+	nonAlphanumeric   = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+	coverKeywords     = []string{"karaoke", "made famous by", "originally performed", "in the style of", "tribute", "cover version"}
+)
+
+// MatchMethod describes how a track was matched on a service.
+type MatchMethod string
+
+const (
+	// MatchByISRC is used for tracks matched by their ISRC.
+	MatchByISRC MatchMethod = "isrc"
+	// MatchByMBID is used for tracks matched by their MusicBrainz recording identifier.
+	MatchByMBID MatchMethod = "mbid"
+	// MatchBySearch is used for tracks matched by searching their artist and title.
+	MatchBySearch MatchMethod = "search"
+	// MatchByName is used for tracks which services identify by their artist and title.
+	MatchByName MatchMethod = "name"
+)
+
+// Match is a candidate track matched against a requested track.
+type Match struct {
+	Track      Track
+	Confidence float64
+	Method     MatchMethod
+}
+
+// LowConfidenceError is returned when the best candidate for a track scores below the threshold.
+type LowConfidenceError struct {
+	Track     Track
+	Match     Match
+	Threshold float64
+}
+
+func (e *LowConfidenceError) Error() string {
+	return fmt.Sprintf("best match for %s is %s with confidence %.2f, below threshold %.2f", e.Track, e.Match.Track, e.Match.Confidence, e.Threshold)
+}
+
+// Matcher scores candidate tracks against a requested track.
+type Matcher struct {
+	Threshold float64
+}
+
+// NewMatcher creates a Matcher accepting matches with at least the given confidence.
+func NewMatcher(threshold float64) Matcher {
+	return Matcher{Threshold: threshold}
+}
+
+// Best returns the best scoring candidate for the track.
+// It returns ErrTrackNotFound when there are no candidates and a LowConfidenceError when the best candidate scores below the threshold.
+func (m Matcher) Best(track Track, candidates []Track) (Match, error) {
+	if len(candidates) == 0 {
+		return Match{}, ErrTrackNotFound
+	}
+
+	best := Match{Confidence: -1}
+	for _, candidate := range candidates {
+		if confidence := m.Score(track, candidate); confidence > best.Confidence {
+			best = Match{
+				Track:      candidate,
+				Confidence: confidence,
+				Method:     MatchBySearch,
+			}
+		}
+	}
+
+	if best.Confidence < m.Threshold {
+		return best, &LowConfidenceError{
+			Track:     track,
+			Match:     best,
+			Threshold: m.Threshold,
+		}
+	}
+
+	return best, nil
+}
+
+// Score returns the confidence between 0 and 1 that the candidate is the same track.
+func (m Matcher) Score(track Track, candidate Track) float64 {
+	score := (titleScore(track.Name, candidate.Name) + artistScore(credits(track), credits(candidate))) / 2
+
+	if isCover(candidate) && !isCover(track) {
+		score *= 0.5
+	}
+
+	if track.Duration > 0 && candidate.Duration > 0 {
+		score *= 0.75 + 0.25*durationScore(track.Duration, candidate.Duration)
+	}
+
+	return score
+}
+
+// NormalizeTitle reduces a track title to a form suitable for comparison,
+// removing featured artists, remaster and version suffixes, punctuation and diacritics.
+func NormalizeTitle(title string) string {
+	title = featuringPattern.ReplaceAllString(title, "")
+	title = bracketPattern.ReplaceAllStringFunc(title, func(part string) string {
+		if versionPattern.MatchString(part) {
+			return ""
+		}
+		return part
+	})
+	if suffix := dashSuffixPattern.FindStringSubmatch(title); suffix != nil && versionPattern.MatchString(suffix[1]) {
+		title = strings.TrimSuffix(title, suffix[0])
+	}
+
+	return normalize(title)
+}
+
+// NormalizeArtist reduces an artist name to a form suitable for comparison.
+func NormalizeArtist(artist string) string {
+	return normalize(artist)
+}
+
+// TrackKeys returns keys identifying the track across services, combining each of its normalised artists with its normalised title.
+// Tracks which share a key can be considered the same track.
+func TrackKeys(track Track) (keys []string) {
+	if track.ISRC != "" {
+		keys = append(keys, "isrc:"+strings.ToUpper(track.ISRC))
+	}
+
+	title := NormalizeTitle(track.Name)
+	for _, artist := range credits(track) {
+		for _, part := range splitArtists(artist) {
+			keys = append(keys, part+"\x00"+title)
+		}
+	}
+	return
+}
+
+func normalize(value string) string {
+	value = strings.ReplaceAll(value, "&", " and ")
+	value, _, _ = transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), value)
+	value = nonAlphanumeric.ReplaceAllString(strings.ToLower(value), " ")
+
+	return strings.TrimSpace(value)
+}
+
+func titleScore(title string, candidate string) float64 {
+	return similarity(NormalizeTitle(title), NormalizeTitle(candidate))
+}
+
+func artistScore(artists []string, candidates []string) float64 {
+	score := similarity(NormalizeArtist(strings.Join(artists, " ")), NormalizeArtist(strings.Join(candidates, " ")))
+
+	for _, artist := range artists {
+		for _, part := range splitArtists(artist) {
+			for _, candidate := range candidates {
+				for _, candidatePart := range splitArtists(candidate) {
+					if part == candidatePart {
+						return 1
+					}
+				}
+			}
+		}
+	}
+
+	return score
+}
+
+func credits(track Track) []string {
+	if len(track.Artists) > 0 {
+		return track.Artists
+	}
+	return []string{track.Artist}
+}
+
+func splitArtists(artist string) (artists []string) {
+	for _, part := range artistSeparator.Split(artist, -1) {
+		if normalized := NormalizeArtist(part); normalized != "" {
+			artists = append(artists, normalized)
+		}
+	}
+	return
+}
+
+func isCover(track Track) bool {
+	text := strings.ToLower(strings.Join(credits(track), " ") + " " + track.Name + " " + track.Album)
+	for _, keyword := range coverKeywords {
+		if strings.Contains(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func durationScore(duration time.Duration, candidate time.Duration) float64 {
+	difference := duration - candidate
+	if difference < 0 {
+		difference = -difference
+	}
+
+	const tolerance = 3 * time.Second
+	const maximum = 30 * time.Second
+
+	if difference <= tolerance {
+		return 1
+	}
+	if difference >= maximum {
+		return 0
+	}
+
+	return 1 - float64(difference-tolerance)/float64(maximum-tolerance)
+}
+
+// similarity returns the Sørensen–Dice coefficient of the character bigrams of both strings.
+func similarity(a string, b string) float64 {
+	if a == b {
+		return 1
+	}
+
+	aBigrams := bigrams(a)
+	bBigrams := bigrams(b)
+	if len(aBigrams) == 0 || len(bBigrams) == 0 {
+		return 0
+	}
+
+	counts := map[string]int{}
+	for _, bigram := range aBigrams {
+		counts[bigram]++
+	}
+
+	common := 0
+	for _, bigram := range bBigrams {
+		if counts[bigram] > 0 {
+			counts[bigram]--
+			common++
+		}
+	}
+
+	return 2 * float64(common) / float64(len(aBigrams)+len(bBigrams))
+}
+
+func bigrams(value string) (result []string) {
+	characters := []rune(strings.ReplaceAll(value, " ", ""))
+	for index := 0; index < len(characters)-1; index++ {
+		result = append(result, string(characters[index:index+2]))
+	}
+	return
+}
@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTitle(t *testing.T) {
+	tests := map[string]string{
+		"Mr. Testy":                          "mr testy",
+		"Mr. Testy - Remastered 2011":        "mr testy",
+		"Mr. Testy - 2011 Remaster":          "mr testy",
+		"Mr. Testy (Remastered)":             "mr testy",
+		"Mr. Testy [Mono]":                   "mr testy",
+		"Mr. Testy (feat. Someone Else)":     "mr testy",
+		"Mr. Testy feat. Someone Else":       "mr testy",
+		"Mr. Testy (Instrumental)":           "mr testy instrumental",
+		"Mr. Testy - Live at Wembley":        "mr testy live at wembley",
+		"Café Crème":                         "cafe creme",
+		"Rock & Roll":                        "rock and roll",
+		"Don't Stop Me Now - Single Version": "don t stop me now",
+		"Don’t Stop Me Now":                  "don t stop me now",
+	}
+
+	for title, expected := range tests {
+		assert.Equal(t, expected, NormalizeTitle(title), title)
+	}
+}
+
+func TestTrackKeys(t *testing.T) {
+	t.Run("returns keys for every credited artist", func(t *testing.T) {
+		track := Track{
+			Artists: []string{"Foo & Bar", "Baz"},
+			Name:    "Mr. Testy - Remastered 2011",
+			ISRC:    "usabc2000001",
+		}
+
+		expected := []string{"isrc:USABC2000001", "foo\x00mr testy", "bar\x00mr testy", "baz\x00mr testy"}
+
+		assert.Equal(t, expected, TrackKeys(track))
+	})
+
+	t.Run("shares a key between the same track on different services", func(t *testing.T) {
+		lastfm := Track{
+			Artist: "Foo & Bar",
+			Name:   "Mr. Testy",
+		}
+		spotify := Track{
+			Artist:  "Foo",
+			Artists: []string{"Foo", "Bar"},
+			Name:    "Mr Testy (Remastered)",
+		}
+
+		assert.Contains(t, TrackKeys(spotify), TrackKeys(lastfm)[0])
+	})
+}
+
+func TestMatcher(t *testing.T) {
+	track := Track{
+		Artist: "Foo & Bar",
+		Name:   "Mr. Testy (feat. Baz)",
+	}
+
+	t.Run("scores identical tracks with full confidence", func(t *testing.T) {
+		matcher := NewMatcher(DefaultThreshold)
+
+		assert.Equal(t, 1.0, matcher.Score(track, track))
+	})
+
+	t.Run("scores tracks with differing punctuation, diacritics and suffixes with full confidence", func(t *testing.T) {
+		matcher := NewMatcher(DefaultThreshold)
+		candidate := Track{
+			Artist: "Foo and Bär",
+			Name:   "Mr Testy - Remastered 2011",
+		}
+
+		assert.Equal(t, 1.0, matcher.Score(track, candidate))
+	})
+
+	t.Run("scores candidate with one of the credited artists with full confidence", func(t *testing.T) {
+		matcher := NewMatcher(DefaultThreshold)
+		candidate := Track{
+			Artist: "Bar",
+			Name:   "Mr. Testy",
+		}
+
+		assert.Equal(t, 1.0, matcher.Score(track, candidate))
+	})
+
+	t.Run("scores candidate crediting all artists separately with full confidence", func(t *testing.T) {
+		matcher := NewMatcher(DefaultThreshold)
+		candidate := Track{
+			Artist:  "Baz",
+			Artists: []string{"Baz", "Foo"},
+			Name:    "Mr. Testy",
+		}
+
+		assert.Equal(t, 1.0, matcher.Score(track, candidate))
+	})
+
+	t.Run("scores different tracks with low confidence", func(t *testing.T) {
+		matcher := NewMatcher(DefaultThreshold)
+		candidate := Track{
+			Artist: "Awesome Artist",
+			Name:   "Blam",
+		}
+
+		assert.Less(t, matcher.Score(track, candidate), 0.5)
+	})
+
+	t.Run("penalises karaoke versions", func(t *testing.T) {
+		matcher := NewMatcher(DefaultThreshold)
+		candidate := Track{
+			Artist: "Karaoke All Stars",
+			Name:   "Mr. Testy (Made Famous by Foo & Bar)",
+		}
+
+		assert.Less(t, matcher.Score(track, candidate), DefaultThreshold)
+	})
+
+	t.Run("penalises differing durations when known", func(t *testing.T) {
+		matcher := NewMatcher(DefaultThreshold)
+		withDuration := Track{Artist: "Foo", Name: "Mr. Testy", Duration: 3 * time.Minute}
+
+		assert.Equal(t, 1.0, matcher.Score(withDuration, Track{Artist: "Foo", Name: "Mr. Testy", Duration: 3*time.Minute + 2*time.Second}))
+		assert.Equal(t, 1.0, matcher.Score(withDuration, Track{Artist: "Foo", Name: "Mr. Testy"}))
+		assert.Less(t, matcher.Score(withDuration, Track{Artist: "Foo", Name: "Mr. Testy", Duration: 8 * time.Minute}), DefaultThreshold)
+	})
+
+	t.Run("returns best scoring candidate", func(t *testing.T) {
+		matcher := NewMatcher(DefaultThreshold)
+		candidates := []Track{
+			{ID: "cover", Artist: "Karaoke All Stars", Name: "Mr. Testy"},
+			{ID: "original", Artist: "Foo", Name: "Mr. Testy"},
+			{ID: "other", Artist: "Foo", Name: "Blam"},
+		}
+
+		got, err := matcher.Best(track, candidates)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "original", got.Track.ID)
+		assert.Equal(t, 1.0, got.Confidence)
+	})
+
+	t.Run("returns track not found error without candidates", func(t *testing.T) {
+		matcher := NewMatcher(DefaultThreshold)
+
+		_, err := matcher.Best(track, nil)
+
+		assert.ErrorIs(t, err, ErrTrackNotFound)
+	})
+
+	t.Run("returns low confidence error when best candidate scores below threshold", func(t *testing.T) {
+		matcher := NewMatcher(0.99)
+		candidate := Track{ID: "close", Artist: "Foo", Name: "Mr. Tasty"}
+
+		got, err := matcher.Best(track, []Track{candidate})
+
+		var lowConfidence *LowConfidenceError
+		assert.True(t, errors.As(err, &lowConfidence))
+		assert.Equal(t, "close", lowConfidence.Match.Track.ID)
+		assert.Equal(t, "close", got.Track.ID)
+		assert.Less(t, got.Confidence, 0.99)
+	})
+}
@@ -0,0 +1,12 @@
+//go:generate mockgen -source runs.go -destination runs_mock.go -package domain
+
+package domain
+
+import "time"
+
+// RunStore keeps the start time of the last run of commands, such as a sync between two services.
+// Runs are identified by a key naming the command and its services.
+type RunStore interface {
+	LastRun(key string) (startedAt time.Time, exists bool)
+	Record(key string, startedAt time.Time) error
+}
@@ -0,0 +1,69 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: runs.go
+//
+// Generated by this command:
+//
+//	mockgen -source runs.go -destination runs_mock.go -package domain
+//
+
+// Package domain is a generated GoMock package.
+package domain
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRunStore is a mock of RunStore interface.
+type MockRunStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockRunStoreMockRecorder
+}
+
+// MockRunStoreMockRecorder is the mock recorder for MockRunStore.
+type MockRunStoreMockRecorder struct {
+	mock *MockRunStore
+}
+
+// NewMockRunStore creates a new mock instance.
+func NewMockRunStore(ctrl *gomock.Controller) *MockRunStore {
+	mock := &MockRunStore{ctrl: ctrl}
+	mock.recorder = &MockRunStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRunStore) EXPECT() *MockRunStoreMockRecorder {
+	return m.recorder
+}
+
+// LastRun mocks base method.
+func (m *MockRunStore) LastRun(key string) (time.Time, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastRun", key)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// LastRun indicates an expected call of LastRun.
+func (mr *MockRunStoreMockRecorder) LastRun(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastRun", reflect.TypeOf((*MockRunStore)(nil).LastRun), key)
+}
+
+// Record mocks base method.
+func (m *MockRunStore) Record(key string, startedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", key, startedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockRunStoreMockRecorder) Record(key, startedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockRunStore)(nil).Record), key, startedAt)
+}
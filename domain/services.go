@@ -2,19 +2,58 @@
 
 package domain
 
+import "context"
+
 // Service is the external service interface.
+// Methods taking a context stop their requests when it is cancelled, while Close always persists state.
 type Service interface {
 	Name() string
 	Authenticated() bool
 	CreateAuthURL(redirectURL string) string
 	CodeParam() string
-	Authenticate(code string, redirectURL string) error
-	GetUsername() (string, error)
-	GetLovedTracks(limit int, page int) ([]Track, error)
-	LoveTrack(track Track) error
+	Authenticate(ctx context.Context, code string, redirectURL string) error
+	GetUsername(ctx context.Context) (string, error)
+	GetLovedTracks(ctx context.Context, limit int, page int) ([]Track, error)
+	FindTrack(ctx context.Context, track Track) (Match, error)
+	LoveTrack(ctx context.Context, track Track) error
+	UnloveTrack(ctx context.Context, track Track) error
 	Close() error
 }
 
+// BatchLover is implemented by services which can mark multiple tracks as loved at once.
+// The returned errors correspond to the given tracks.
+type BatchLover interface {
+	LoveTracks(ctx context.Context, tracks []Track) []error
+}
+
+// LoveChecker is implemented by services which can tell whether tracks are loved.
+type LoveChecker interface {
+	Loved(ctx context.Context, tracks []Track) ([]bool, error)
+}
+
+// MatchingService is implemented by services which search for candidates to match tracks.
+type MatchingService interface {
+	SetMatcher(matcher Matcher)
+}
+
+// TokenService is implemented by services which authenticate with a user token instead of an authorization callback.
+// TokenURL returns the page on which users find their token.
+type TokenService interface {
+	TokenURL() string
+}
+
+// CredentialsService is implemented by services which authenticate with credentials passed to the login command instead of an authorization callback.
+// CredentialsUsage describes the expected credentials argument.
+type CredentialsService interface {
+	CredentialsUsage() string
+}
+
+// PasswordService is implemented by credentials services which read the password separately, so it is not passed to the login command.
+// SetPassword is called with the password before authenticating.
+type PasswordService interface {
+	SetPassword(password string)
+}
+
 // ServiceLoader loads service instances by name.
 type ServiceLoader interface {
 	ForName(serviceName string) (Service, error)
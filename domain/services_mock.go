@@ -10,6 +10,7 @@
 package domain
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
@@ -39,17 +40,17 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 }
 
 // Authenticate mocks base method.
-func (m *MockService) Authenticate(code, redirectURL string) error {
+func (m *MockService) Authenticate(ctx context.Context, code, redirectURL string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Authenticate", code, redirectURL)
+	ret := m.ctrl.Call(m, "Authenticate", ctx, code, redirectURL)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Authenticate indicates an expected call of Authenticate.
-func (mr *MockServiceMockRecorder) Authenticate(code, redirectURL any) *gomock.Call {
+func (mr *MockServiceMockRecorder) Authenticate(ctx, code, redirectURL any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockService)(nil).Authenticate), code, redirectURL)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockService)(nil).Authenticate), ctx, code, redirectURL)
 }
 
 // Authenticated mocks base method.
@@ -108,48 +109,63 @@ func (mr *MockServiceMockRecorder) CreateAuthURL(redirectURL any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuthURL", reflect.TypeOf((*MockService)(nil).CreateAuthURL), redirectURL)
 }
 
+// FindTrack mocks base method.
+func (m *MockService) FindTrack(ctx context.Context, track Track) (Match, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTrack", ctx, track)
+	ret0, _ := ret[0].(Match)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTrack indicates an expected call of FindTrack.
+func (mr *MockServiceMockRecorder) FindTrack(ctx, track any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTrack", reflect.TypeOf((*MockService)(nil).FindTrack), ctx, track)
+}
+
 // GetLovedTracks mocks base method.
-func (m *MockService) GetLovedTracks(limit, page int) ([]Track, error) {
+func (m *MockService) GetLovedTracks(ctx context.Context, limit, page int) ([]Track, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLovedTracks", limit, page)
+	ret := m.ctrl.Call(m, "GetLovedTracks", ctx, limit, page)
 	ret0, _ := ret[0].([]Track)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetLovedTracks indicates an expected call of GetLovedTracks.
-func (mr *MockServiceMockRecorder) GetLovedTracks(limit, page any) *gomock.Call {
+func (mr *MockServiceMockRecorder) GetLovedTracks(ctx, limit, page any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLovedTracks", reflect.TypeOf((*MockService)(nil).GetLovedTracks), limit, page)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLovedTracks", reflect.TypeOf((*MockService)(nil).GetLovedTracks), ctx, limit, page)
 }
 
 // GetUsername mocks base method.
-func (m *MockService) GetUsername() (string, error) {
+func (m *MockService) GetUsername(ctx context.Context) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUsername")
+	ret := m.ctrl.Call(m, "GetUsername", ctx)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUsername indicates an expected call of GetUsername.
-func (mr *MockServiceMockRecorder) GetUsername() *gomock.Call {
+func (mr *MockServiceMockRecorder) GetUsername(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsername", reflect.TypeOf((*MockService)(nil).GetUsername))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsername", reflect.TypeOf((*MockService)(nil).GetUsername), ctx)
 }
 
 // LoveTrack mocks base method.
-func (m *MockService) LoveTrack(track Track) error {
+func (m *MockService) LoveTrack(ctx context.Context, track Track) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LoveTrack", track)
+	ret := m.ctrl.Call(m, "LoveTrack", ctx, track)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // LoveTrack indicates an expected call of LoveTrack.
-func (mr *MockServiceMockRecorder) LoveTrack(track any) *gomock.Call {
+func (mr *MockServiceMockRecorder) LoveTrack(ctx, track any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoveTrack", reflect.TypeOf((*MockService)(nil).LoveTrack), track)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoveTrack", reflect.TypeOf((*MockService)(nil).LoveTrack), ctx, track)
 }
 
 // Name mocks base method.
@@ -166,6 +182,239 @@ func (mr *MockServiceMockRecorder) Name() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockService)(nil).Name))
 }
 
+// UnloveTrack mocks base method.
+func (m *MockService) UnloveTrack(ctx context.Context, track Track) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnloveTrack", ctx, track)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnloveTrack indicates an expected call of UnloveTrack.
+func (mr *MockServiceMockRecorder) UnloveTrack(ctx, track any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnloveTrack", reflect.TypeOf((*MockService)(nil).UnloveTrack), ctx, track)
+}
+
+// MockBatchLover is a mock of BatchLover interface.
+type MockBatchLover struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatchLoverMockRecorder
+}
+
+// MockBatchLoverMockRecorder is the mock recorder for MockBatchLover.
+type MockBatchLoverMockRecorder struct {
+	mock *MockBatchLover
+}
+
+// NewMockBatchLover creates a new mock instance.
+func NewMockBatchLover(ctrl *gomock.Controller) *MockBatchLover {
+	mock := &MockBatchLover{ctrl: ctrl}
+	mock.recorder = &MockBatchLoverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBatchLover) EXPECT() *MockBatchLoverMockRecorder {
+	return m.recorder
+}
+
+// LoveTracks mocks base method.
+func (m *MockBatchLover) LoveTracks(ctx context.Context, tracks []Track) []error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoveTracks", ctx, tracks)
+	ret0, _ := ret[0].([]error)
+	return ret0
+}
+
+// LoveTracks indicates an expected call of LoveTracks.
+func (mr *MockBatchLoverMockRecorder) LoveTracks(ctx, tracks any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoveTracks", reflect.TypeOf((*MockBatchLover)(nil).LoveTracks), ctx, tracks)
+}
+
+// MockLoveChecker is a mock of LoveChecker interface.
+type MockLoveChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoveCheckerMockRecorder
+}
+
+// MockLoveCheckerMockRecorder is the mock recorder for MockLoveChecker.
+type MockLoveCheckerMockRecorder struct {
+	mock *MockLoveChecker
+}
+
+// NewMockLoveChecker creates a new mock instance.
+func NewMockLoveChecker(ctrl *gomock.Controller) *MockLoveChecker {
+	mock := &MockLoveChecker{ctrl: ctrl}
+	mock.recorder = &MockLoveCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoveChecker) EXPECT() *MockLoveCheckerMockRecorder {
+	return m.recorder
+}
+
+// Loved mocks base method.
+func (m *MockLoveChecker) Loved(ctx context.Context, tracks []Track) ([]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Loved", ctx, tracks)
+	ret0, _ := ret[0].([]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Loved indicates an expected call of Loved.
+func (mr *MockLoveCheckerMockRecorder) Loved(ctx, tracks any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Loved", reflect.TypeOf((*MockLoveChecker)(nil).Loved), ctx, tracks)
+}
+
+// MockMatchingService is a mock of MatchingService interface.
+type MockMatchingService struct {
+	ctrl     *gomock.Controller
+	recorder *MockMatchingServiceMockRecorder
+}
+
+// MockMatchingServiceMockRecorder is the mock recorder for MockMatchingService.
+type MockMatchingServiceMockRecorder struct {
+	mock *MockMatchingService
+}
+
+// NewMockMatchingService creates a new mock instance.
+func NewMockMatchingService(ctrl *gomock.Controller) *MockMatchingService {
+	mock := &MockMatchingService{ctrl: ctrl}
+	mock.recorder = &MockMatchingServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMatchingService) EXPECT() *MockMatchingServiceMockRecorder {
+	return m.recorder
+}
+
+// SetMatcher mocks base method.
+func (m *MockMatchingService) SetMatcher(matcher Matcher) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetMatcher", matcher)
+}
+
+// SetMatcher indicates an expected call of SetMatcher.
+func (mr *MockMatchingServiceMockRecorder) SetMatcher(matcher any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMatcher", reflect.TypeOf((*MockMatchingService)(nil).SetMatcher), matcher)
+}
+
+// MockTokenService is a mock of TokenService interface.
+type MockTokenService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenServiceMockRecorder
+}
+
+// MockTokenServiceMockRecorder is the mock recorder for MockTokenService.
+type MockTokenServiceMockRecorder struct {
+	mock *MockTokenService
+}
+
+// NewMockTokenService creates a new mock instance.
+func NewMockTokenService(ctrl *gomock.Controller) *MockTokenService {
+	mock := &MockTokenService{ctrl: ctrl}
+	mock.recorder = &MockTokenServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenService) EXPECT() *MockTokenServiceMockRecorder {
+	return m.recorder
+}
+
+// TokenURL mocks base method.
+func (m *MockTokenService) TokenURL() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TokenURL")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// TokenURL indicates an expected call of TokenURL.
+func (mr *MockTokenServiceMockRecorder) TokenURL() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TokenURL", reflect.TypeOf((*MockTokenService)(nil).TokenURL))
+}
+
+// MockCredentialsService is a mock of CredentialsService interface.
+type MockCredentialsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCredentialsServiceMockRecorder
+}
+
+// MockCredentialsServiceMockRecorder is the mock recorder for MockCredentialsService.
+type MockCredentialsServiceMockRecorder struct {
+	mock *MockCredentialsService
+}
+
+// NewMockCredentialsService creates a new mock instance.
+func NewMockCredentialsService(ctrl *gomock.Controller) *MockCredentialsService {
+	mock := &MockCredentialsService{ctrl: ctrl}
+	mock.recorder = &MockCredentialsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCredentialsService) EXPECT() *MockCredentialsServiceMockRecorder {
+	return m.recorder
+}
+
+// CredentialsUsage mocks base method.
+func (m *MockCredentialsService) CredentialsUsage() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CredentialsUsage")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CredentialsUsage indicates an expected call of CredentialsUsage.
+func (mr *MockCredentialsServiceMockRecorder) CredentialsUsage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CredentialsUsage", reflect.TypeOf((*MockCredentialsService)(nil).CredentialsUsage))
+}
+
+// MockPasswordService is a mock of PasswordService interface.
+type MockPasswordService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordServiceMockRecorder
+}
+
+// MockPasswordServiceMockRecorder is the mock recorder for MockPasswordService.
+type MockPasswordServiceMockRecorder struct {
+	mock *MockPasswordService
+}
+
+// NewMockPasswordService creates a new mock instance.
+func NewMockPasswordService(ctrl *gomock.Controller) *MockPasswordService {
+	mock := &MockPasswordService{ctrl: ctrl}
+	mock.recorder = &MockPasswordServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordService) EXPECT() *MockPasswordServiceMockRecorder {
+	return m.recorder
+}
+
+// SetPassword mocks base method.
+func (m *MockPasswordService) SetPassword(password string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetPassword", password)
+}
+
+// SetPassword indicates an expected call of SetPassword.
+func (mr *MockPasswordServiceMockRecorder) SetPassword(password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPassword", reflect.TypeOf((*MockPasswordService)(nil).SetPassword), password)
+}
+
 // MockServiceLoader is a mock of ServiceLoader interface.
 type MockServiceLoader struct {
 	ctrl     *gomock.Controller
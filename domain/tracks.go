@@ -2,12 +2,35 @@
 
 package domain
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTrackNotFound is returned by services when a track cannot be matched on the service.
+var ErrTrackNotFound = errors.New("track not found")
 
 // Track represents a track on an external service.
 type Track struct {
+	// ID is the identifier of the track on the service it was read from.
+	ID string
+	// URI is the URI or URL of the track on the service it was read from.
+	URI string
+	// Artist is the primary artist of the track.
 	Artist string
-	Name   string
+	// Artists are all credited artists of the track, starting with the primary artist.
+	Artists  []string
+	Name     string
+	Album    string
+	Duration time.Duration
+	ISRC     string
+	// MBID is the MusicBrainz recording identifier.
+	MBID string
+	// ArtistMBID is the MusicBrainz identifier of the primary artist.
+	ArtistMBID string
+	// LovedAt is the time at which the track was loved or saved on the service.
+	LovedAt time.Time
 }
 
 func (t Track) String() string {
@@ -11,6 +11,8 @@ require (
 	github.com/zmb3/spotify/v2 v2.4.0
 	go.uber.org/mock v0.4.0
 	golang.org/x/oauth2 v0.16.0
+	golang.org/x/term v0.15.0
+	golang.org/x/text v0.14.0
 	honnef.co/go/tools v0.4.6
 )
 
@@ -43,8 +45,6 @@ require (
 	golang.org/x/exp/typeparams v0.0.0-20221208152030-732eee02a75a // indirect
 	golang.org/x/mod v0.14.0 // indirect
 	golang.org/x/sys v0.15.0 // indirect
-	golang.org/x/term v0.15.0 // indirect
-	golang.org/x/text v0.14.0 // indirect
 	golang.org/x/tools v0.16.0 // indirect
 	google.golang.org/appengine v1.6.8 // indirect
 	google.golang.org/protobuf v1.32.0 // indirect
@@ -3,14 +3,32 @@
 package authentication
 
 import (
+	"context"
 	"io"
 	"os"
+	"time"
 )
 
-// DefaultCallbackProvider is the default callback provider for services.
-var DefaultCallbackProvider = &cliCallbackProvider{os.Stdin}
+const (
+	// DefaultCallbackPort is the default local port to listen on for authentication callbacks.
+	DefaultCallbackPort = 8888
+	// DefaultCallbackTimeout is the default duration to wait for an authentication callback.
+	DefaultCallbackTimeout = 5 * time.Minute
+)
+
+// ManualCallbackProvider is the callback provider reading authentication codes from standard input.
+var ManualCallbackProvider = &cliCallbackProvider{os.Stdin}
+
+// TerminalPasswordReader is the password reader reading passwords from the terminal, or from standard input when it is not a terminal.
+var TerminalPasswordReader = &terminalPasswordReader{os.Stdin}
 
 // CallbackProvider provides a callback mechanism for authenticating services.
 type CallbackProvider interface {
-	ReadCode(key string, writer io.Writer) (code string, err error)
+	RedirectURL() string
+	ReadCode(ctx context.Context, key string, writer io.Writer) (code string, err error)
+}
+
+// PasswordReader reads passwords without showing them.
+type PasswordReader interface {
+	ReadPassword(prompt string, writer io.Writer) (password string, err error)
 }
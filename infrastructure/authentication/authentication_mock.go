@@ -10,6 +10,7 @@
 package authentication
 
 import (
+	context "context"
 	io "io"
 	reflect "reflect"
 
@@ -40,16 +41,68 @@ func (m *MockCallbackProvider) EXPECT() *MockCallbackProviderMockRecorder {
 }
 
 // ReadCode mocks base method.
-func (m *MockCallbackProvider) ReadCode(key string, writer io.Writer) (string, error) {
+func (m *MockCallbackProvider) ReadCode(ctx context.Context, key string, writer io.Writer) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ReadCode", key, writer)
+	ret := m.ctrl.Call(m, "ReadCode", ctx, key, writer)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ReadCode indicates an expected call of ReadCode.
-func (mr *MockCallbackProviderMockRecorder) ReadCode(key, writer any) *gomock.Call {
+func (mr *MockCallbackProviderMockRecorder) ReadCode(ctx, key, writer any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadCode", reflect.TypeOf((*MockCallbackProvider)(nil).ReadCode), key, writer)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadCode", reflect.TypeOf((*MockCallbackProvider)(nil).ReadCode), ctx, key, writer)
+}
+
+// RedirectURL mocks base method.
+func (m *MockCallbackProvider) RedirectURL() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedirectURL")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// RedirectURL indicates an expected call of RedirectURL.
+func (mr *MockCallbackProviderMockRecorder) RedirectURL() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedirectURL", reflect.TypeOf((*MockCallbackProvider)(nil).RedirectURL))
+}
+
+// MockPasswordReader is a mock of PasswordReader interface.
+type MockPasswordReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordReaderMockRecorder
+}
+
+// MockPasswordReaderMockRecorder is the mock recorder for MockPasswordReader.
+type MockPasswordReaderMockRecorder struct {
+	mock *MockPasswordReader
+}
+
+// NewMockPasswordReader creates a new mock instance.
+func NewMockPasswordReader(ctrl *gomock.Controller) *MockPasswordReader {
+	mock := &MockPasswordReader{ctrl: ctrl}
+	mock.recorder = &MockPasswordReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordReader) EXPECT() *MockPasswordReaderMockRecorder {
+	return m.recorder
+}
+
+// ReadPassword mocks base method.
+func (m *MockPasswordReader) ReadPassword(prompt string, writer io.Writer) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadPassword", prompt, writer)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadPassword indicates an expected call of ReadPassword.
+func (mr *MockPasswordReaderMockRecorder) ReadPassword(prompt, writer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadPassword", reflect.TypeOf((*MockPasswordReader)(nil).ReadPassword), prompt, writer)
 }
@@ -2,6 +2,7 @@ package authentication
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"strings"
@@ -11,7 +12,11 @@ type cliCallbackProvider struct {
 	reader io.Reader
 }
 
-func (c cliCallbackProvider) ReadCode(key string, writer io.Writer) (code string, err error) {
+func (c cliCallbackProvider) RedirectURL() string {
+	return "https://admirer.test"
+}
+
+func (c cliCallbackProvider) ReadCode(ctx context.Context, key string, writer io.Writer) (code string, err error) {
 	fmt.Fprintf(writer, "Please provide %q parameter from the authentication callback URL's query parameters: ", key)
 
 	bufferedReader := bufio.NewReader(c.reader)
@@ -2,6 +2,7 @@ package authentication
 
 import (
 	"bytes"
+	"context"
 	"strings"
 	"testing"
 )
@@ -14,7 +15,7 @@ func TestCliCallbackProvider(t *testing.T) {
 
 		provider := &cliCallbackProvider{buffer}
 
-		got, err := provider.ReadCode("foo", writer)
+		got, err := provider.ReadCode(context.Background(), "foo", writer)
 
 		if err != nil {
 			t.Errorf("Unexpected error: %v", err)
@@ -33,13 +34,24 @@ func TestCliCallbackProvider(t *testing.T) {
 		}
 	})
 
+	t.Run("returns placeholder redirect URL", func(t *testing.T) {
+		provider := &cliCallbackProvider{}
+
+		expected := "https://admirer.test"
+		got := provider.RedirectURL()
+
+		if got != expected {
+			t.Errorf("expected %q, got %q", expected, got)
+		}
+	})
+
 	t.Run("returns error when failing to read", func(t *testing.T) {
 		buffer := new(bytes.Buffer)
 		writer := new(bytes.Buffer)
 
 		provider := &cliCallbackProvider{buffer}
 
-		got, err := provider.ReadCode("foo", writer)
+		got, err := provider.ReadCode(context.Background(), "foo", writer)
 
 		if err == nil {
 			t.Error("Expected an error")
@@ -2,28 +2,84 @@ package authentication
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"time"
 )
 
-//lint:ignore U1000 WIP
+const callbackPath = "/callback"
+
 type httpCallbackProvider struct {
-	server *http.Server
+	port    int
+	timeout time.Duration
+}
+
+// NewHTTPCallbackProvider creates a CallbackProvider that listens on the loopback interface for the authentication callback.
+func NewHTTPCallbackProvider(port int, timeout time.Duration) CallbackProvider {
+	return &httpCallbackProvider{
+		port:    port,
+		timeout: timeout,
+	}
+}
+
+func (h *httpCallbackProvider) RedirectURL() string {
+	return fmt.Sprintf("http://%s%s", h.address(), callbackPath)
 }
 
-func (h *httpCallbackProvider) ReadCode(key string, writer io.Writer) (code string, err error) {
-	ctx := context.Background()
+func (h *httpCallbackProvider) ReadCode(ctx context.Context, key string, writer io.Writer) (code string, err error) {
+	listener, err := net.Listen("tcp", h.address())
+	if err != nil {
+		return "", fmt.Errorf("failed listening for authentication callback: %w", err)
+	}
+
+	handler := &httpCallbackHandler{
+		Key:   key,
+		Value: make(chan string, 1),
+	}
+	mux := http.NewServeMux()
+	mux.Handle(callbackPath, handler)
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serverErrors := make(chan error, 1)
+	go func() {
+		serverErrors <- server.Serve(listener)
+	}()
+	defer server.Close()
 
-	handler := &httpCallbackHandler{Key: key}
-	h.server = &http.Server{
-		Handler: handler,
+	fmt.Fprintln(writer, "Waiting for authentication callback on", h.RedirectURL())
+
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+
+	select {
+	case code = <-handler.Value:
+	case err = <-serverErrors:
+		return "", fmt.Errorf("authentication callback server failed: %w", err)
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", fmt.Errorf("timed out after %s waiting for authentication callback", h.timeout)
+		}
+		return "", ctx.Err()
 	}
 
-	go h.server.ListenAndServe()
+	if code == "" {
+		return "", fmt.Errorf("authentication callback did not contain %q parameter", key)
+	}
 
-	err = h.server.Shutdown(ctx)
+	return code, nil
+}
 
-	return
+func (h *httpCallbackProvider) address() string {
+	return fmt.Sprintf("127.0.0.1:%d", h.port)
 }
 
 type httpCallbackHandler struct {
@@ -32,5 +88,20 @@ type httpCallbackHandler struct {
 }
 
 func (h *httpCallbackHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
-	h.Value <- request.FormValue(h.Key)
+	value := request.FormValue(h.Key)
+
+	select {
+	case h.Value <- value:
+	default:
+	}
+
+	writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	if value == "" {
+		writer.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintln(writer, "Authentication failed: no authentication code was received. Please return to the terminal.")
+		return
+	}
+
+	fmt.Fprintln(writer, "Authentication code received. You can close this tab and return to the terminal.")
 }
@@ -1,13 +1,88 @@
 package authentication
 
 import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
 func TestHttpCallbackProvider(t *testing.T) {
+	t.Run("returns loopback redirect URL for configured port", func(t *testing.T) {
+		provider := NewHTTPCallbackProvider(8123, time.Minute)
+
+		assert.Equal(t, "http://127.0.0.1:8123/callback", provider.RedirectURL())
+	})
+
+	t.Run("returns code received on callback URL", func(t *testing.T) {
+		provider := NewHTTPCallbackProvider(freePort(t), time.Minute)
+		writer := new(bytes.Buffer)
+
+		go func() {
+			requestCallback(t, provider.RedirectURL()+"?myToken=tokenValue")
+		}()
+
+		got, err := provider.ReadCode(context.Background(), "myToken", writer)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "tokenValue", got)
+		assert.Contains(t, writer.String(), "Waiting for authentication callback on "+provider.RedirectURL())
+	})
+
+	t.Run("returns error when callback does not contain code", func(t *testing.T) {
+		provider := NewHTTPCallbackProvider(freePort(t), time.Minute)
+
+		go func() {
+			requestCallback(t, provider.RedirectURL()+"?error=access_denied")
+		}()
+
+		got, err := provider.ReadCode(context.Background(), "myToken", new(bytes.Buffer))
+
+		assert.EqualError(t, err, `authentication callback did not contain "myToken" parameter`)
+		assert.Empty(t, got)
+	})
+
+	t.Run("returns error when timing out", func(t *testing.T) {
+		provider := NewHTTPCallbackProvider(freePort(t), 10*time.Millisecond)
+
+		got, err := provider.ReadCode(context.Background(), "myToken", new(bytes.Buffer))
+
+		assert.ErrorContains(t, err, "timed out")
+		assert.Empty(t, got)
+	})
+
+	t.Run("returns error when cancelled", func(t *testing.T) {
+		provider := NewHTTPCallbackProvider(freePort(t), time.Minute)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		got, err := provider.ReadCode(ctx, "myToken", new(bytes.Buffer))
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, got)
+	})
+
+	t.Run("returns error when port is unavailable", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer listener.Close()
+
+		port := listener.Addr().(*net.TCPAddr).Port
+		provider := NewHTTPCallbackProvider(port, time.Minute)
+
+		got, err := provider.ReadCode(context.Background(), "myToken", new(bytes.Buffer))
+
+		assert.Error(t, err)
+		assert.Empty(t, got)
+	})
 }
 
 func TestHttpCallbackHandler(t *testing.T) {
@@ -35,4 +110,51 @@ func TestHttpCallbackHandler(t *testing.T) {
 
 		assert.Empty(t, <-handler.Value)
 	})
+
+	t.Run("tells user to close the browser tab", func(t *testing.T) {
+		handler := &httpCallbackHandler{
+			Key:   "myToken",
+			Value: make(chan string, 1),
+		}
+		response := httptest.NewRecorder()
+
+		handler.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Contains(t, response.Body.String(), "You can close this tab")
+	})
+
+	t.Run("does not block on repeated callbacks", func(t *testing.T) {
+		handler := &httpCallbackHandler{
+			Key:   "myToken",
+			Value: make(chan string, 1),
+		}
+
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+
+		assert.Equal(t, "tokenValue", <-handler.Value)
+	})
+}
+
+func freePort(t *testing.T) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer listener.Close()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func requestCallback(t *testing.T, url string) {
+	for attempt := 0; attempt < 50; attempt++ {
+		response, err := http.Get(url)
+		if err == nil {
+			response.Body.Close()
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("failed requesting %q", url)
 }
@@ -0,0 +1,35 @@
+package authentication
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+type terminalPasswordReader struct {
+	file *os.File
+}
+
+func (t terminalPasswordReader) ReadPassword(prompt string, writer io.Writer) (string, error) {
+	fmt.Fprint(writer, prompt)
+
+	if !term.IsTerminal(int(t.file.Fd())) {
+		password, err := bufio.NewReader(t.file).ReadString('\n')
+		if err != nil && (err != io.EOF || password == "") {
+			return "", err
+		}
+		return strings.TrimRight(password, "\r\n"), nil
+	}
+
+	password, err := term.ReadPassword(int(t.file.Fd()))
+	fmt.Fprintln(writer)
+	if err != nil {
+		return "", err
+	}
+
+	return string(password), nil
+}
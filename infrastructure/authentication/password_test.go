@@ -0,0 +1,54 @@
+package authentication
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestTerminalPasswordReader(t *testing.T) {
+	t.Run("reads password from standard input when it is not a terminal", func(t *testing.T) {
+		reader, writer, err := os.Pipe()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer reader.Close()
+
+		writer.WriteString("sesame\n")
+		writer.Close()
+
+		output := new(bytes.Buffer)
+		passwordReader := &terminalPasswordReader{reader}
+
+		got, err := passwordReader.ReadPassword("Password: ", output)
+
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		expected := "sesame"
+		if got != expected {
+			t.Errorf("expected %q, got %q", expected, got)
+		}
+
+		expected = "Password: "
+		if output.String() != expected {
+			t.Errorf("expected %q, got %q", expected, output.String())
+		}
+	})
+
+	t.Run("returns error when standard input is empty", func(t *testing.T) {
+		reader, writer, err := os.Pipe()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer reader.Close()
+		writer.Close()
+
+		passwordReader := &terminalPasswordReader{reader}
+
+		if _, err := passwordReader.ReadPassword("Password: ", new(bytes.Buffer)); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
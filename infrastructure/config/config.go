@@ -13,6 +13,10 @@ type Config interface {
 	IsSet(key string) bool
 	GetString(key string) string
 	Set(key string, value interface{})
+	// Remove removes the key when saving.
+	Remove(key string)
+	// Keys returns the keys which are set.
+	Keys() ([]string, error)
 	Save() error
 }
 
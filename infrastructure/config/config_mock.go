@@ -66,6 +66,33 @@ func (mr *MockConfigMockRecorder) IsSet(key any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSet", reflect.TypeOf((*MockConfig)(nil).IsSet), key)
 }
 
+// Keys mocks base method.
+func (m *MockConfig) Keys() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Keys")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Keys indicates an expected call of Keys.
+func (mr *MockConfigMockRecorder) Keys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keys", reflect.TypeOf((*MockConfig)(nil).Keys))
+}
+
+// Remove mocks base method.
+func (m *MockConfig) Remove(key string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Remove", key)
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockConfigMockRecorder) Remove(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockConfig)(nil).Remove), key)
+}
+
 // Save mocks base method.
 func (m *MockConfig) Save() error {
 	m.ctrl.T.Helper()
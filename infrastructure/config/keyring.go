@@ -3,7 +3,10 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/99designs/keyring"
 )
@@ -12,12 +15,15 @@ import (
 type Keyring interface {
 	Get(key string) (keyring.Item, error)
 	Set(item keyring.Item) error
+	Remove(key string) error
+	Keys() ([]string, error)
 }
 
 type keyringConfig struct {
 	Keyring
 	prefix  string
 	unsaved map[string]keyring.Item
+	removed map[string]bool
 }
 
 func (k *keyringConfig) Get(key string) (keyring.Item, error) {
@@ -59,6 +65,42 @@ func (k *keyringConfig) Set(key string, value interface{}) {
 		k.unsaved = map[string]keyring.Item{}
 	}
 	k.unsaved[key] = item
+	delete(k.removed, key)
+}
+
+func (k *keyringConfig) Remove(key string) {
+	delete(k.unsaved, key)
+
+	if k.removed == nil {
+		k.removed = map[string]bool{}
+	}
+	k.removed[key] = true
+}
+
+// Keys returns the keys in the keyring with the prefix of this config, without the prefix.
+func (k *keyringConfig) Keys() ([]string, error) {
+	keys, err := k.Keyring.Keys()
+	if err != nil {
+		return nil, fmt.Errorf("failed listing keyring keys: %w", err)
+	}
+
+	found := map[string]bool{}
+	for _, key := range keys {
+		if key, exists := strings.CutPrefix(key, k.prefixed("")); exists && !k.removed[key] {
+			found[key] = true
+		}
+	}
+	for key := range k.unsaved {
+		found[key] = true
+	}
+
+	result := make([]string, 0, len(found))
+	for key := range found {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+
+	return result, nil
 }
 
 func (k *keyringConfig) Save() error {
@@ -69,6 +111,13 @@ func (k *keyringConfig) Save() error {
 	}
 	k.unsaved = map[string]keyring.Item{}
 
+	for key := range k.removed {
+		if err := k.Keyring.Remove(k.prefixed(key)); err != nil && !errors.Is(err, keyring.ErrKeyNotFound) {
+			return err
+		}
+	}
+	k.removed = map[string]bool{}
+
 	return nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"go.uber.org/mock/gomock"
 	"os"
+	"strings"
 	"testing"
 
 	keyring_lib "github.com/99designs/keyring"
@@ -130,6 +131,90 @@ func TestKeyringConfig(t *testing.T) {
 		}
 	})
 
+	t.Run("returns keys with prefix", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		keyring := NewMockKeyring(ctrl)
+		keyring.EXPECT().Keys().Return([]string{"prefix-foo", "other-bar", "prefix-baz", "prefix-removed"}, nil)
+
+		config := &keyringConfig{
+			Keyring: keyring,
+			prefix:  "prefix",
+		}
+
+		config.Set("new", "value")
+		config.Remove("removed")
+		got, err := config.Keys()
+
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		expected := []string{"baz", "foo", "new"}
+		if strings.Join(got, ",") != strings.Join(expected, ",") {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("returns error when failing to list keys", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		keyring := NewMockKeyring(ctrl)
+		keyring.EXPECT().Keys().Return(nil, errors.New("read error"))
+
+		config := &keyringConfig{
+			Keyring: keyring,
+			prefix:  "prefix",
+		}
+
+		_, err := config.Keys()
+
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+	})
+
+	t.Run("removes keys from keyring when saving", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		keyring := NewMockKeyring(ctrl)
+		keyring.EXPECT().Remove("prefix-foo").Return(nil)
+		keyring.EXPECT().Remove("prefix-bar").Return(keyring_lib.ErrKeyNotFound)
+
+		config := &keyringConfig{
+			Keyring: keyring,
+			prefix:  "prefix",
+		}
+
+		config.Set("foo", "unsaved")
+		config.Remove("foo")
+		config.Remove("bar")
+		err := config.Save()
+
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("returns error when failing to remove key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		keyring := NewMockKeyring(ctrl)
+		keyring.EXPECT().Remove("prefix-foo").Return(errors.New("write error"))
+
+		config := &keyringConfig{
+			Keyring: keyring,
+			prefix:  "prefix",
+		}
+
+		config.Remove("foo")
+		err := config.Save()
+
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+	})
+
 	t.Run("saves empty keyring without error", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		keyring := NewMockKeyring(ctrl)
@@ -5,6 +5,8 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/spf13/viper"
 )
@@ -19,10 +21,34 @@ type viperConfig struct {
 	*viper.Viper
 }
 
+// Remove replaces the configuration with one lacking the key, as viper cannot unset keys.
+func (v *viperConfig) Remove(key string) {
+	settings := v.AllSettings()
+	delete(settings, strings.ToLower(key))
+
+	config := newViper(v.ConfigFileUsed())
+	config.MergeConfigMap(settings)
+	v.Viper = config
+}
+
+func (v *viperConfig) Keys() ([]string, error) {
+	keys := v.AllKeys()
+	sort.Strings(keys)
+	return keys, nil
+}
+
 func (v *viperConfig) Save() error {
 	return v.WriteConfig()
 }
 
+func newViper(filename string) *viper.Viper {
+	config := viper.New()
+	config.SetConfigFile(filename)
+	config.SetConfigType("yaml")
+	config.SetConfigPermissions(permissions)
+	return config
+}
+
 type viperLoader struct{}
 
 func (v viperLoader) Load(name string) (Config, error) {
@@ -31,10 +57,7 @@ func (v viperLoader) Load(name string) (Config, error) {
 }
 
 func (v viperLoader) loadFromFile(filename string) (Config, error) {
-	config := viper.New()
-	config.SetConfigFile(filename)
-	config.SetConfigType("yaml")
-	config.SetConfigPermissions(permissions)
+	config := newViper(filename)
 
 	if err := config.ReadInConfig(); err != nil {
 		switch readError := err.(type) {
@@ -94,6 +94,41 @@ func TestViperLoader(t *testing.T) {
 		}
 	})
 
+	t.Run("removes key from configuration file", func(t *testing.T) {
+		file, err := createFile("foo: bar\nbaz: qux\n")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer os.Remove(file.Name())
+
+		loader := &viperLoader{}
+		config, err := loader.loadFromFile(file.Name())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		config.Remove("foo")
+		if err := config.Save(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		config, err = loader.loadFromFile(file.Name())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		keys, err := config.Keys()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := "baz"
+		got := strings.Join(keys, ",")
+		if got != expected {
+			t.Errorf("expected %q, got %q", expected, got)
+		}
+	})
+
 	t.Run("returns error for invalid filename", func(t *testing.T) {
 		loader := &viperLoader{}
 		config, err := loader.loadFromFile("./")
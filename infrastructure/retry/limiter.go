@@ -0,0 +1,45 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter spaces out requests to stay within a requests per second budget.
+// A nil Limiter does not limit requests.
+type Limiter struct {
+	mutex    sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// NewLimiter creates a Limiter allowing the given number of requests per second.
+// It returns nil, allowing unlimited requests, when the budget is not positive.
+func NewLimiter(requestsPerSecond float64) *Limiter {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+
+	return &Limiter{
+		interval: time.Duration(float64(time.Second) / requestsPerSecond),
+	}
+}
+
+// Wait blocks until the next request is allowed or the context is done.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return ctx.Err()
+	}
+
+	l.mutex.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mutex.Unlock()
+
+	return sleep(ctx, delay)
+}
@@ -0,0 +1,85 @@
+package retry
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Policy describes how often failed requests are retried and how long to wait in between.
+type Policy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultPolicy is the retry policy used for external services.
+var DefaultPolicy = Policy{
+	MaxAttempts: 5,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    time.Minute,
+}
+
+// sleep waits for the duration or until the context is done.
+var sleep = func(ctx context.Context, duration time.Duration) error {
+	if duration <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Backoff returns the delay before retrying the given attempt, using exponential backoff with jitter.
+func (p Policy) Backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for retry := 1; retry < attempt && delay < p.MaxDelay; retry++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// Do calls fn until it succeeds, fails with an error which is not retryable or the policy runs out of attempts.
+// Every attempt waits for the limiter first.
+func Do(ctx context.Context, policy Policy, limiter *Limiter, retryable func(error) bool, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+
+		err := fn()
+		if err == nil || attempt >= policy.MaxAttempts || !retryable(err) {
+			return err
+		}
+
+		if err := sleep(ctx, policy.Backoff(attempt)); err != nil {
+			return err
+		}
+	}
+}
+
+// RequestsPerSecond reads a requests per second budget from the environment variable, or returns the fallback.
+func RequestsPerSecond(name string, fallback float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(name), 64)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,111 @@
+package retry
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Transport is an http.RoundTripper retrying rate limited and failed requests.
+// It honours the Retry-After header and otherwise backs off exponentially.
+type Transport struct {
+	Base    http.RoundTripper
+	Policy  Policy
+	Limiter *Limiter
+}
+
+// NewTransport creates a Transport using the default policy and a requests per second budget.
+func NewTransport(requestsPerSecond float64) *Transport {
+	return &Transport{
+		Policy:  DefaultPolicy,
+		Limiter: NewLimiter(requestsPerSecond),
+	}
+}
+
+// RoundTrip executes the request, retrying it according to the policy.
+func (t *Transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	ctx := request.Context()
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err := t.Limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		attemptRequest := request
+		if attempt > 1 && request.Body != nil {
+			body, err := request.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptRequest = request.Clone(ctx)
+			attemptRequest.Body = body
+		}
+
+		response, err := base.RoundTrip(attemptRequest)
+		if attempt >= t.Policy.MaxAttempts || !t.retryable(request, response, err) {
+			return response, err
+		}
+
+		delay := t.Policy.Backoff(attempt)
+		if response != nil {
+			if retryAfter, ok := parseRetryAfter(response.Header.Get("Retry-After")); ok {
+				if retryAfter > t.Policy.MaxDelay {
+					return response, nil
+				}
+				delay = retryAfter
+			}
+
+			_, _ = io.Copy(io.Discard, response.Body)
+			response.Body.Close()
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (t *Transport) retryable(request *http.Request, response *http.Response, err error) bool {
+	if request.Body != nil && request.GetBody == nil {
+		return false
+	}
+
+	if err != nil {
+		return request.Context().Err() == nil
+	}
+
+	switch response.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,225 @@
+package retry
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransport(t *testing.T) {
+	policy := Policy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    time.Minute,
+	}
+
+	t.Run("retries rate limited and failed requests", func(t *testing.T) {
+		delays := recordSleeps(t)
+		statuses := []int{http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusOK}
+		server, requests := statusServer(t, statuses, nil)
+
+		response, err := newClient(policy).Get(server.URL)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, 3, *requests)
+		assert.Len(t, *delays, 2)
+	})
+
+	t.Run("honours Retry-After header", func(t *testing.T) {
+		delays := recordSleeps(t)
+		header := http.Header{"Retry-After": []string{"7"}}
+		server, _ := statusServer(t, []int{http.StatusTooManyRequests, http.StatusOK}, header)
+
+		response, err := newClient(policy).Get(server.URL)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, []time.Duration{7 * time.Second}, *delays)
+	})
+
+	t.Run("does not wait longer than maximum delay", func(t *testing.T) {
+		recordSleeps(t)
+		header := http.Header{"Retry-After": []string{"3600"}}
+		server, requests := statusServer(t, []int{http.StatusTooManyRequests, http.StatusOK}, header)
+
+		response, err := newClient(policy).Get(server.URL)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusTooManyRequests, response.StatusCode)
+		assert.Equal(t, 1, *requests)
+	})
+
+	t.Run("returns last response when running out of attempts", func(t *testing.T) {
+		recordSleeps(t)
+		statuses := []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusOK}
+		server, requests := statusServer(t, statuses, nil)
+
+		response, err := newClient(policy).Get(server.URL)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadGateway, response.StatusCode)
+		assert.Equal(t, 3, *requests)
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		recordSleeps(t)
+		server, requests := statusServer(t, []int{http.StatusNotFound, http.StatusOK}, nil)
+
+		response, err := newClient(policy).Get(server.URL)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, response.StatusCode)
+		assert.Equal(t, 1, *requests)
+	})
+
+	t.Run("sends request body on every attempt", func(t *testing.T) {
+		recordSleeps(t)
+		var bodies []string
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			body, _ := io.ReadAll(request.Body)
+			bodies = append(bodies, string(body))
+			if len(bodies) == 1 {
+				writer.WriteHeader(http.StatusInternalServerError)
+			}
+		}))
+		defer server.Close()
+
+		response, err := newClient(policy).Post(server.URL, "text/plain", strings.NewReader("payload"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, []string{"payload", "payload"}, bodies)
+	})
+
+	t.Run("waits for limiter before every attempt", func(t *testing.T) {
+		delays := recordSleeps(t)
+		server, _ := statusServer(t, []int{http.StatusOK, http.StatusOK}, nil)
+
+		transport := &Transport{Policy: policy, Limiter: NewLimiter(2)}
+		client := &http.Client{Transport: transport}
+
+		_, err := client.Get(server.URL)
+		assert.NoError(t, err)
+		_, err = client.Get(server.URL)
+		assert.NoError(t, err)
+
+		assert.Len(t, *delays, 2)
+		assert.Zero(t, (*delays)[0])
+		assert.InDelta(t, 500*time.Millisecond, (*delays)[1], float64(50*time.Millisecond))
+	})
+}
+
+func TestDo(t *testing.T) {
+	policy := Policy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    time.Second,
+	}
+	retryable := func(err error) bool {
+		return err.Error() == "temporary"
+	}
+
+	t.Run("retries retryable errors until success", func(t *testing.T) {
+		recordSleeps(t)
+		errs := []error{errorString("temporary"), nil}
+		calls := 0
+
+		err := Do(context.Background(), policy, nil, retryable, func() error {
+			calls++
+			return errs[calls-1]
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("returns errors which are not retryable", func(t *testing.T) {
+		recordSleeps(t)
+		calls := 0
+
+		err := Do(context.Background(), policy, nil, retryable, func() error {
+			calls++
+			return errorString("permanent")
+		})
+
+		assert.EqualError(t, err, "permanent")
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("stops after maximum attempts", func(t *testing.T) {
+		recordSleeps(t)
+		calls := 0
+
+		err := Do(context.Background(), policy, nil, retryable, func() error {
+			calls++
+			return errorString("temporary")
+		})
+
+		assert.EqualError(t, err, "temporary")
+		assert.Equal(t, 3, calls)
+	})
+}
+
+func TestPolicy(t *testing.T) {
+	policy := Policy{
+		BaseDelay: time.Second,
+		MaxDelay:  10 * time.Second,
+	}
+
+	for attempt, maximum := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second} {
+		delay := policy.Backoff(attempt + 1)
+
+		assert.GreaterOrEqual(t, delay, maximum/2)
+		assert.LessOrEqual(t, delay, maximum)
+	}
+}
+
+type errorString string
+
+func (e errorString) Error() string {
+	return string(e)
+}
+
+func newClient(policy Policy) *http.Client {
+	return &http.Client{
+		Transport: &Transport{Policy: policy},
+	}
+}
+
+func statusServer(t *testing.T, statuses []int, header http.Header) (*httptest.Server, *int) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		status := statuses[requests]
+		requests++
+		if status != http.StatusOK {
+			for key, values := range header {
+				writer.Header()[key] = values
+			}
+		}
+		writer.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func recordSleeps(t *testing.T) *[]time.Duration {
+	var delays []time.Duration
+	original := sleep
+	sleep = func(ctx context.Context, duration time.Duration) error {
+		delays = append(delays, duration)
+		return nil
+	}
+	t.Cleanup(func() {
+		sleep = original
+	})
+
+	return &delays
+}
@@ -0,0 +1,253 @@
+package deezer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/dietrichm/admirer/infrastructure/retry"
+)
+
+const (
+	apiURL     = "https://api.deezer.com"
+	connectURL = "https://connect.deezer.com/oauth"
+	perms      = "basic_access,manage_library,offline_access"
+)
+
+// Deezer error codes.
+// See https://developers.deezer.com/api/errors.
+const (
+	errorQuota    = 4
+	errorBusy     = 700
+	errorNotFound = 800
+)
+
+// User is a Deezer user.
+type User struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// Artist is a Deezer artist.
+type Artist struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// Album is a Deezer album.
+type Album struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+}
+
+// Track is a Deezer track.
+type Track struct {
+	ID           int64    `json:"id"`
+	Title        string   `json:"title"`
+	Link         string   `json:"link"`
+	Duration     int      `json:"duration"`
+	ISRC         string   `json:"isrc"`
+	TimeAdd      int64    `json:"time_add"`
+	Artist       Artist   `json:"artist"`
+	Contributors []Artist `json:"contributors"`
+	Album        Album    `json:"album"`
+}
+
+// Error is an error returned by the Deezer API.
+type Error struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	Code    int    `json:"code"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s (code %d)", e.Type, e.Message, e.Code)
+}
+
+type apiClient struct {
+	httpClient  *http.Client
+	baseURL     string
+	accessToken string
+}
+
+func newAPIClient(httpClient *http.Client, accessToken string) *apiClient {
+	return &apiClient{
+		httpClient:  httpClient,
+		baseURL:     apiURL,
+		accessToken: accessToken,
+	}
+}
+
+func (a *apiClient) CurrentUser(ctx context.Context) (user *User, err error) {
+	err = a.request(ctx, http.MethodGet, "/user/me", nil, &user)
+	return
+}
+
+func (a *apiClient) FavouriteTracks(ctx context.Context, index int, limit int) ([]Track, error) {
+	query := url.Values{
+		"index": {strconv.Itoa(index)},
+		"limit": {strconv.Itoa(limit)},
+	}
+
+	var result struct {
+		Data []Track `json:"data"`
+	}
+	err := a.request(ctx, http.MethodGet, "/user/me/tracks", query, &result)
+	return result.Data, err
+}
+
+func (a *apiClient) TrackByISRC(ctx context.Context, isrc string) (*Track, error) {
+	var track Track
+	err := a.request(ctx, http.MethodGet, "/track/isrc:"+url.PathEscape(isrc), nil, &track)
+
+	var deezerError *Error
+	if errors.As(err, &deezerError) && deezerError.Code == errorNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &track, nil
+}
+
+func (a *apiClient) SearchTracks(ctx context.Context, query string, limit int) ([]Track, error) {
+	values := url.Values{
+		"q":     {query},
+		"limit": {strconv.Itoa(limit)},
+	}
+
+	var result struct {
+		Data []Track `json:"data"`
+	}
+	err := a.request(ctx, http.MethodGet, "/search/track", values, &result)
+	return result.Data, err
+}
+
+func (a *apiClient) AddFavouriteTrack(ctx context.Context, id string) error {
+	return a.request(ctx, http.MethodPost, "/user/me/tracks", url.Values{"track_id": {id}}, nil)
+}
+
+func (a *apiClient) RemoveFavouriteTrack(ctx context.Context, id string) error {
+	return a.request(ctx, http.MethodDelete, "/user/me/tracks", url.Values{"track_id": {id}}, nil)
+}
+
+// request performs an API request, retrying it when the quota is exceeded.
+// Deezer reports errors in the response body, also for successful HTTP responses.
+func (a *apiClient) request(ctx context.Context, method string, path string, query url.Values, result interface{}) error {
+	values := url.Values{"access_token": {a.accessToken}}
+	for key, value := range query {
+		values[key] = value
+	}
+
+	endpoint := a.baseURL + path + "?" + values.Encode()
+
+	return retry.Do(ctx, retry.DefaultPolicy, nil, retryable, func() error {
+		request, err := http.NewRequestWithContext(ctx, method, endpoint, nil)
+		if err != nil {
+			return err
+		}
+
+		response, err := a.httpClient.Do(request)
+		if err != nil {
+			return err
+		}
+		defer response.Body.Close()
+
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			return err
+		}
+
+		var errorResponse struct {
+			Error *Error `json:"error"`
+		}
+		if err := json.Unmarshal(body, &errorResponse); err == nil && errorResponse.Error != nil {
+			return errorResponse.Error
+		}
+
+		if response.StatusCode != http.StatusOK {
+			return fmt.Errorf("unexpected HTTP status %s", response.Status)
+		}
+
+		if result == nil {
+			return nil
+		}
+
+		return json.Unmarshal(body, result)
+	})
+}
+
+func retryable(err error) bool {
+	var deezerError *Error
+	if !errors.As(err, &deezerError) {
+		return false
+	}
+
+	return deezerError.Code == errorQuota || deezerError.Code == errorBusy
+}
+
+type authenticator struct {
+	httpClient   *http.Client
+	baseURL      string
+	clientID     string
+	clientSecret string
+}
+
+func newAuthenticator(httpClient *http.Client, clientID string, clientSecret string) *authenticator {
+	return &authenticator{
+		httpClient:   httpClient,
+		baseURL:      connectURL,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+	}
+}
+
+func (a *authenticator) AuthURL(redirectURL string) string {
+	values := url.Values{
+		"app_id":       {a.clientID},
+		"redirect_uri": {redirectURL},
+		"perms":        {perms},
+	}
+	return a.baseURL + "/auth.php?" + values.Encode()
+}
+
+func (a *authenticator) Exchange(ctx context.Context, code string) (string, error) {
+	values := url.Values{
+		"app_id": {a.clientID},
+		"secret": {a.clientSecret},
+		"code":   {code},
+		"output": {"json"},
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, a.baseURL+"/access_token.php?"+values.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := a.httpClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil || result.AccessToken == "" {
+		return "", fmt.Errorf("no access token received: %s", strings.TrimSpace(string(body)))
+	}
+
+	return result.AccessToken, nil
+}
@@ -0,0 +1,104 @@
+package deezer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIClient(t *testing.T) {
+	t.Run("reads favourite tracks", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			assert.Equal(t, "/user/me/tracks", request.URL.Path)
+			assert.Equal(t, "access_token=token&index=10&limit=5", request.URL.RawQuery)
+			writer.Write([]byte(`{"data": [{"id": 3135556, "title": "Mr. Testy", "time_add": 1704164645, "artist": {"name": "Foo"}}], "total": 11}`))
+		}))
+		defer server.Close()
+
+		client := &apiClient{httpClient: server.Client(), baseURL: server.URL, accessToken: "token"}
+		got, err := client.FavouriteTracks(context.Background(), 10, 5)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Track{{ID: 3135556, Title: "Mr. Testy", TimeAdd: 1704164645, Artist: Artist{Name: "Foo"}}}, got)
+	})
+
+	t.Run("returns API error reported in response body", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.Write([]byte(`{"error": {"type": "OAuthException", "message": "Invalid OAuth access token.", "code": 300}}`))
+		}))
+		defer server.Close()
+
+		client := &apiClient{httpClient: server.Client(), baseURL: server.URL, accessToken: "token"}
+		_, err := client.CurrentUser(context.Background())
+
+		assert.EqualError(t, err, "OAuthException: Invalid OAuth access token. (code 300)")
+	})
+
+	t.Run("returns no track for unknown ISRC", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			assert.Equal(t, "/track/isrc:USABC2000001", request.URL.Path)
+			writer.Write([]byte(`{"error": {"type": "DataException", "message": "no data", "code": 800}}`))
+		}))
+		defer server.Close()
+
+		client := &apiClient{httpClient: server.Client(), baseURL: server.URL, accessToken: "token"}
+		got, err := client.TrackByISRC(context.Background(), "USABC2000001")
+
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("adds favourite track", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			assert.Equal(t, http.MethodPost, request.Method)
+			assert.Equal(t, "/user/me/tracks", request.URL.Path)
+			assert.Equal(t, "3135556", request.URL.Query().Get("track_id"))
+			writer.Write([]byte(`true`))
+		}))
+		defer server.Close()
+
+		client := &apiClient{httpClient: server.Client(), baseURL: server.URL, accessToken: "token"}
+
+		assert.NoError(t, client.AddFavouriteTrack(context.Background(), "3135556"))
+	})
+}
+
+func TestAuthenticator(t *testing.T) {
+	t.Run("creates authentication URL", func(t *testing.T) {
+		authenticator := newAuthenticator(nil, "appID", "secret")
+
+		expected := "https://connect.deezer.com/oauth/auth.php?app_id=appID&perms=basic_access%2Cmanage_library%2Coffline_access&redirect_uri=https%3A%2F%2Fadmirer.test"
+
+		assert.Equal(t, expected, authenticator.AuthURL("https://admirer.test"))
+	})
+
+	t.Run("exchanges code for access token", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			assert.Equal(t, "/access_token.php", request.URL.Path)
+			assert.Equal(t, "app_id=appID&code=authcode&output=json&secret=secret", request.URL.RawQuery)
+			writer.Write([]byte(`{"access_token": "accessToken", "expires": 0}`))
+		}))
+		defer server.Close()
+
+		authenticator := &authenticator{httpClient: server.Client(), baseURL: server.URL, clientID: "appID", clientSecret: "secret"}
+		got, err := authenticator.Exchange(context.Background(), "authcode")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "accessToken", got)
+	})
+
+	t.Run("returns error for wrong code", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.Write([]byte("wrong code"))
+		}))
+		defer server.Close()
+
+		authenticator := &authenticator{httpClient: server.Client(), baseURL: server.URL, clientID: "appID", clientSecret: "secret"}
+		_, err := authenticator.Exchange(context.Background(), "authcode")
+
+		assert.EqualError(t, err, "no access token received: wrong code")
+	})
+}
@@ -0,0 +1,242 @@
+//go:generate mockgen -source deezer.go -destination deezer_mock.go -package deezer
+
+package deezer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dietrichm/admirer/domain"
+	"github.com/dietrichm/admirer/infrastructure/config"
+	"github.com/dietrichm/admirer/infrastructure/retry"
+)
+
+// Authenticator is our interface for a Deezer OAuth authenticator.
+type Authenticator interface {
+	AuthURL(redirectURL string) string
+	Exchange(ctx context.Context, code string) (accessToken string, err error)
+}
+
+// Client is our interface for a Deezer API client.
+type Client interface {
+	CurrentUser(ctx context.Context) (*User, error)
+	FavouriteTracks(ctx context.Context, index int, limit int) ([]Track, error)
+	TrackByISRC(ctx context.Context, isrc string) (*Track, error)
+	SearchTracks(ctx context.Context, query string, limit int) ([]Track, error)
+	AddFavouriteTrack(ctx context.Context, id string) error
+	RemoveFavouriteTrack(ctx context.Context, id string) error
+}
+
+const (
+	searchLimit       = 10
+	requestsPerSecond = 10
+)
+
+// Deezer is the external Deezer service implementation.
+type Deezer struct {
+	authenticator Authenticator
+	client        Client
+	httpClient    *http.Client
+	secrets       config.Config
+	matcher       domain.Matcher
+}
+
+// NewDeezer creates a Deezer instance.
+func NewDeezer(secrets config.Config) (*Deezer, error) {
+	clientID := os.Getenv("DEEZER_CLIENT_ID")
+	clientSecret := os.Getenv("DEEZER_CLIENT_SECRET")
+
+	if len(clientID) == 0 || len(clientSecret) == 0 {
+		return nil, errors.New("please set DEEZER_CLIENT_ID and DEEZER_CLIENT_SECRET environment variables")
+	}
+
+	transport := retry.NewTransport(retry.RequestsPerSecond("DEEZER_REQUESTS_PER_SECOND", requestsPerSecond))
+	httpClient := &http.Client{Transport: transport}
+
+	service := &Deezer{
+		authenticator: newAuthenticator(httpClient, clientID, clientSecret),
+		httpClient:    httpClient,
+		secrets:       secrets,
+		matcher:       domain.NewMatcher(domain.DefaultThreshold),
+	}
+
+	if accessToken := secrets.GetString("access_token"); accessToken != "" {
+		service.client = newAPIClient(httpClient, accessToken)
+	}
+
+	return service, nil
+}
+
+// Name returns the human-readable service name.
+func (d *Deezer) Name() string {
+	return "Deezer"
+}
+
+// Authenticated returns whether the service is logged in.
+func (d *Deezer) Authenticated() bool {
+	return d.client != nil
+}
+
+// CreateAuthURL returns an authorization URL to authorize the integration.
+func (d *Deezer) CreateAuthURL(redirectURL string) string {
+	return d.authenticator.AuthURL(redirectURL)
+}
+
+// CodeParam is the query parameter name used in the authentication callback.
+func (d *Deezer) CodeParam() string {
+	return "code"
+}
+
+// Authenticate takes an authorization code and authenticates the user.
+func (d *Deezer) Authenticate(ctx context.Context, code string, redirectURL string) error {
+	accessToken, err := d.authenticator.Exchange(ctx, code)
+	if err != nil {
+		return fmt.Errorf("failed to authenticate on Deezer: %w", err)
+	}
+
+	d.client = newAPIClient(d.httpClient, accessToken)
+	d.secrets.Set("access_token", accessToken)
+
+	if err := d.secrets.Save(); err != nil {
+		return fmt.Errorf("failed to save Deezer secrets: %w", err)
+	}
+
+	return nil
+}
+
+// GetUsername requests and returns the username of the logged-in user.
+func (d *Deezer) GetUsername(ctx context.Context) (string, error) {
+	user, err := d.client.CurrentUser(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to read Deezer profile data: %w", err)
+	}
+
+	return user.Name, nil
+}
+
+// GetLovedTracks returns favourite tracks from the external service.
+func (d *Deezer) GetLovedTracks(ctx context.Context, limit int, page int) (tracks []domain.Track, err error) {
+	index := (page - 1) * limit
+
+	result, err := d.client.FavouriteTracks(ctx, index, limit)
+	if err != nil {
+		return tracks, fmt.Errorf("failed to read Deezer favourite tracks: %w", err)
+	}
+
+	for _, resultTrack := range result {
+		tracks = append(tracks, trackFromDeezer(resultTrack))
+	}
+	return
+}
+
+// SetMatcher sets the matcher used to select search results when loving tracks.
+func (d *Deezer) SetMatcher(matcher domain.Matcher) {
+	d.matcher = matcher
+}
+
+// FindTrack looks up the track on the external service, by ISRC when available and by searching otherwise.
+func (d *Deezer) FindTrack(ctx context.Context, track domain.Track) (domain.Match, error) {
+	if track.ISRC != "" {
+		result, err := d.client.TrackByISRC(ctx, track.ISRC)
+		if err != nil {
+			return domain.Match{}, fmt.Errorf("failed to look up track on Deezer: %w", err)
+		}
+
+		if result != nil {
+			match := domain.Match{
+				Track:      trackFromDeezer(*result),
+				Confidence: 1,
+				Method:     domain.MatchByISRC,
+			}
+			return match, nil
+		}
+	}
+
+	query := fmt.Sprintf("artist:%q track:%q", track.Artist, track.Name)
+	query = strings.ReplaceAll(query, `\"`, "")
+
+	candidates, err := d.searchTracks(ctx, query)
+	if err != nil {
+		return domain.Match{}, err
+	}
+
+	if len(candidates) == 0 {
+		query = domain.NormalizeArtist(track.Artist) + " " + domain.NormalizeTitle(track.Name)
+		if candidates, err = d.searchTracks(ctx, query); err != nil {
+			return domain.Match{}, err
+		}
+	}
+
+	match, err := d.matcher.Best(track, candidates)
+	if errors.Is(err, domain.ErrTrackNotFound) {
+		return match, fmt.Errorf("%w on Deezer: %s", domain.ErrTrackNotFound, track)
+	}
+
+	return match, err
+}
+
+// LoveTrack adds a track found on the external service to the favourite tracks.
+func (d *Deezer) LoveTrack(ctx context.Context, track domain.Track) error {
+	if err := d.client.AddFavouriteTrack(ctx, track.ID); err != nil {
+		return fmt.Errorf("failed to mark track as loved on Deezer: %w", err)
+	}
+
+	return nil
+}
+
+// UnloveTrack removes a track found on the external service from the favourite tracks.
+func (d *Deezer) UnloveTrack(ctx context.Context, track domain.Track) error {
+	if err := d.client.RemoveFavouriteTrack(ctx, track.ID); err != nil {
+		return fmt.Errorf("failed to unlove track on Deezer: %w", err)
+	}
+
+	return nil
+}
+
+// Close persists any state before quitting the application.
+func (d *Deezer) Close() error {
+	return nil
+}
+
+func (d *Deezer) searchTracks(ctx context.Context, query string) (tracks []domain.Track, err error) {
+	result, err := d.client.SearchTracks(ctx, query, searchLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search track on Deezer: %w", err)
+	}
+
+	for _, resultTrack := range result {
+		tracks = append(tracks, trackFromDeezer(resultTrack))
+	}
+	return
+}
+
+func trackFromDeezer(deezerTrack Track) domain.Track {
+	track := domain.Track{
+		ID:       strconv.FormatInt(deezerTrack.ID, 10),
+		URI:      deezerTrack.Link,
+		Artist:   deezerTrack.Artist.Name,
+		Name:     deezerTrack.Title,
+		Album:    deezerTrack.Album.Title,
+		Duration: time.Duration(deezerTrack.Duration) * time.Second,
+		ISRC:     deezerTrack.ISRC,
+	}
+
+	for _, contributor := range deezerTrack.Contributors {
+		track.Artists = append(track.Artists, contributor.Name)
+	}
+	if len(track.Artists) == 0 && track.Artist != "" {
+		track.Artists = []string{track.Artist}
+	}
+
+	if deezerTrack.TimeAdd > 0 {
+		track.LovedAt = time.Unix(deezerTrack.TimeAdd, 0).UTC()
+	}
+
+	return track
+}
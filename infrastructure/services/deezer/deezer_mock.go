@@ -0,0 +1,180 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: deezer.go
+//
+// Generated by this command:
+//
+//	mockgen -source deezer.go -destination deezer_mock.go -package deezer
+//
+
+// Package deezer is a generated GoMock package.
+package deezer
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAuthenticator is a mock of Authenticator interface.
+type MockAuthenticator struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthenticatorMockRecorder
+}
+
+// MockAuthenticatorMockRecorder is the mock recorder for MockAuthenticator.
+type MockAuthenticatorMockRecorder struct {
+	mock *MockAuthenticator
+}
+
+// NewMockAuthenticator creates a new mock instance.
+func NewMockAuthenticator(ctrl *gomock.Controller) *MockAuthenticator {
+	mock := &MockAuthenticator{ctrl: ctrl}
+	mock.recorder = &MockAuthenticatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthenticator) EXPECT() *MockAuthenticatorMockRecorder {
+	return m.recorder
+}
+
+// AuthURL mocks base method.
+func (m *MockAuthenticator) AuthURL(redirectURL string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthURL", redirectURL)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// AuthURL indicates an expected call of AuthURL.
+func (mr *MockAuthenticatorMockRecorder) AuthURL(redirectURL any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthURL", reflect.TypeOf((*MockAuthenticator)(nil).AuthURL), redirectURL)
+}
+
+// Exchange mocks base method.
+func (m *MockAuthenticator) Exchange(ctx context.Context, code string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exchange", ctx, code)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exchange indicates an expected call of Exchange.
+func (mr *MockAuthenticatorMockRecorder) Exchange(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exchange", reflect.TypeOf((*MockAuthenticator)(nil).Exchange), ctx, code)
+}
+
+// MockClient is a mock of Client interface.
+type MockClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientMockRecorder
+}
+
+// MockClientMockRecorder is the mock recorder for MockClient.
+type MockClientMockRecorder struct {
+	mock *MockClient
+}
+
+// NewMockClient creates a new mock instance.
+func NewMockClient(ctrl *gomock.Controller) *MockClient {
+	mock := &MockClient{ctrl: ctrl}
+	mock.recorder = &MockClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClient) EXPECT() *MockClientMockRecorder {
+	return m.recorder
+}
+
+// AddFavouriteTrack mocks base method.
+func (m *MockClient) AddFavouriteTrack(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddFavouriteTrack", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddFavouriteTrack indicates an expected call of AddFavouriteTrack.
+func (mr *MockClientMockRecorder) AddFavouriteTrack(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFavouriteTrack", reflect.TypeOf((*MockClient)(nil).AddFavouriteTrack), ctx, id)
+}
+
+// CurrentUser mocks base method.
+func (m *MockClient) CurrentUser(ctx context.Context) (*User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CurrentUser", ctx)
+	ret0, _ := ret[0].(*User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CurrentUser indicates an expected call of CurrentUser.
+func (mr *MockClientMockRecorder) CurrentUser(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CurrentUser", reflect.TypeOf((*MockClient)(nil).CurrentUser), ctx)
+}
+
+// FavouriteTracks mocks base method.
+func (m *MockClient) FavouriteTracks(ctx context.Context, index, limit int) ([]Track, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FavouriteTracks", ctx, index, limit)
+	ret0, _ := ret[0].([]Track)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FavouriteTracks indicates an expected call of FavouriteTracks.
+func (mr *MockClientMockRecorder) FavouriteTracks(ctx, index, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FavouriteTracks", reflect.TypeOf((*MockClient)(nil).FavouriteTracks), ctx, index, limit)
+}
+
+// RemoveFavouriteTrack mocks base method.
+func (m *MockClient) RemoveFavouriteTrack(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFavouriteTrack", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveFavouriteTrack indicates an expected call of RemoveFavouriteTrack.
+func (mr *MockClientMockRecorder) RemoveFavouriteTrack(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFavouriteTrack", reflect.TypeOf((*MockClient)(nil).RemoveFavouriteTrack), ctx, id)
+}
+
+// SearchTracks mocks base method.
+func (m *MockClient) SearchTracks(ctx context.Context, query string, limit int) ([]Track, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTracks", ctx, query, limit)
+	ret0, _ := ret[0].([]Track)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTracks indicates an expected call of SearchTracks.
+func (mr *MockClientMockRecorder) SearchTracks(ctx, query, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTracks", reflect.TypeOf((*MockClient)(nil).SearchTracks), ctx, query, limit)
+}
+
+// TrackByISRC mocks base method.
+func (m *MockClient) TrackByISRC(ctx context.Context, isrc string) (*Track, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrackByISRC", ctx, isrc)
+	ret0, _ := ret[0].(*Track)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TrackByISRC indicates an expected call of TrackByISRC.
+func (mr *MockClientMockRecorder) TrackByISRC(ctx, isrc any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrackByISRC", reflect.TypeOf((*MockClient)(nil).TrackByISRC), ctx, isrc)
+}
@@ -0,0 +1,210 @@
+package deezer
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+
+	"github.com/dietrichm/admirer/domain"
+	"github.com/dietrichm/admirer/infrastructure/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeezer(t *testing.T) {
+	deezerTrack := Track{
+		ID:       3135556,
+		Title:    "Mr. Testy",
+		Link:     "https://www.deezer.com/track/3135556",
+		Duration: 205,
+		ISRC:     "USABC2000001",
+		TimeAdd:  1704164645,
+		Artist:   Artist{Name: "Foo"},
+		Contributors: []Artist{
+			{Name: "Foo"},
+			{Name: "Bar"},
+		},
+		Album: Album{Title: "Testing, Testing"},
+	}
+
+	track := domain.Track{
+		ID:       "3135556",
+		URI:      "https://www.deezer.com/track/3135556",
+		Artist:   "Foo",
+		Artists:  []string{"Foo", "Bar"},
+		Name:     "Mr. Testy",
+		Album:    "Testing, Testing",
+		Duration: 205 * time.Second,
+		ISRC:     "USABC2000001",
+		LovedAt:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	t.Run("returns whether service is authenticated", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		service := &Deezer{}
+		assert.False(t, service.Authenticated())
+
+		service = &Deezer{client: NewMockClient(ctrl)}
+		assert.True(t, service.Authenticated())
+	})
+
+	t.Run("creates authentication URL", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		authenticator := NewMockAuthenticator(ctrl)
+		authenticator.EXPECT().AuthURL("https://admirer.test/foo").Return("https://service.test/auth")
+
+		service := &Deezer{authenticator: authenticator}
+
+		assert.Equal(t, "https://service.test/auth", service.CreateAuthURL("https://admirer.test/foo"))
+	})
+
+	t.Run("authenticates using authorization code", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		authenticator := NewMockAuthenticator(ctrl)
+		authenticator.EXPECT().Exchange(gomock.Any(), "authcode").Return("accessToken", nil)
+
+		secrets := config.NewMockConfig(ctrl)
+		secrets.EXPECT().Set("access_token", "accessToken")
+		secrets.EXPECT().Save()
+
+		service := &Deezer{authenticator: authenticator, secrets: secrets}
+		err := service.Authenticate(context.Background(), "authcode", "https://admirer.test/foo")
+
+		assert.NoError(t, err)
+		assert.True(t, service.Authenticated())
+	})
+
+	t.Run("returns error when authentication fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		authenticator := NewMockAuthenticator(ctrl)
+		authenticator.EXPECT().Exchange(gomock.Any(), "authcode").Return("", errors.New("wrong code"))
+
+		service := &Deezer{authenticator: authenticator}
+		err := service.Authenticate(context.Background(), "authcode", "https://admirer.test/foo")
+
+		assert.EqualError(t, err, "failed to authenticate on Deezer: wrong code")
+		assert.False(t, service.Authenticated())
+	})
+
+	t.Run("returns username", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		client := NewMockClient(ctrl)
+		client.EXPECT().CurrentUser(gomock.Any()).Return(&User{Name: "Joe"}, nil)
+
+		service := &Deezer{client: client}
+		got, err := service.GetUsername(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Joe", got)
+	})
+
+	t.Run("returns favourite tracks as loved tracks", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		client := NewMockClient(ctrl)
+		client.EXPECT().FavouriteTracks(gomock.Any(), 10, 10).Return([]Track{deezerTrack}, nil)
+
+		service := &Deezer{client: client}
+		got, err := service.GetLovedTracks(context.Background(), 10, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Track{track}, got)
+	})
+
+	t.Run("returns error when reading favourite tracks fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		client := NewMockClient(ctrl)
+		client.EXPECT().FavouriteTracks(gomock.Any(), 0, 10).Return(nil, errors.New("api error"))
+
+		service := &Deezer{client: client}
+		_, err := service.GetLovedTracks(context.Background(), 10, 1)
+
+		assert.EqualError(t, err, "failed to read Deezer favourite tracks: api error")
+	})
+
+	t.Run("finds track by ISRC", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		client := NewMockClient(ctrl)
+		client.EXPECT().TrackByISRC(gomock.Any(), "USABC2000001").Return(&deezerTrack, nil)
+
+		service := &Deezer{client: client}
+		got, err := service.FindTrack(context.Background(), domain.Track{Artist: "Foo", Name: "Mr. Testy", ISRC: "USABC2000001"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.MatchByISRC, got.Method)
+		assert.Equal(t, "3135556", got.Track.ID)
+	})
+
+	t.Run("finds track by searching when ISRC is unknown", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		client := NewMockClient(ctrl)
+		client.EXPECT().TrackByISRC(gomock.Any(), "USABC2000001").Return(nil, nil)
+		client.EXPECT().SearchTracks(gomock.Any(), `artist:"Foo" track:"Mr. Testy"`, searchLimit).Return([]Track{
+			{ID: 1, Title: "Something Else", Artist: Artist{Name: "Someone"}},
+			deezerTrack,
+		}, nil)
+
+		service := &Deezer{client: client, matcher: domain.NewMatcher(domain.DefaultThreshold)}
+		got, err := service.FindTrack(context.Background(), domain.Track{Artist: "Foo", Name: "Mr. Testy", ISRC: "USABC2000001"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.MatchBySearch, got.Method)
+		assert.Equal(t, "3135556", got.Track.ID)
+	})
+
+	t.Run("searches again with normalised query when strict search finds nothing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		client := NewMockClient(ctrl)
+		client.EXPECT().SearchTracks(gomock.Any(), `artist:"Foo" track:"Mr. Testy - Remastered"`, searchLimit).Return(nil, nil)
+		client.EXPECT().SearchTracks(gomock.Any(), "foo mr testy", searchLimit).Return(nil, nil)
+
+		service := &Deezer{client: client, matcher: domain.NewMatcher(domain.DefaultThreshold)}
+		_, err := service.FindTrack(context.Background(), domain.Track{Artist: "Foo", Name: "Mr. Testy - Remastered"})
+
+		assert.ErrorIs(t, err, domain.ErrTrackNotFound)
+		assert.EqualError(t, err, "track not found on Deezer: Foo - Mr. Testy - Remastered")
+	})
+
+	t.Run("adds loved track to favourite tracks", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		client := NewMockClient(ctrl)
+		client.EXPECT().AddFavouriteTrack(gomock.Any(), "3135556")
+
+		service := &Deezer{client: client}
+
+		assert.NoError(t, service.LoveTrack(context.Background(), track))
+	})
+
+	t.Run("returns error when adding favourite track fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		client := NewMockClient(ctrl)
+		client.EXPECT().AddFavouriteTrack(gomock.Any(), "3135556").Return(errors.New("api error"))
+
+		service := &Deezer{client: client}
+
+		assert.EqualError(t, service.LoveTrack(context.Background(), track), "failed to mark track as loved on Deezer: api error")
+	})
+
+	t.Run("removes unloved track from favourite tracks", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		client := NewMockClient(ctrl)
+		client.EXPECT().RemoveFavouriteTrack(gomock.Any(), "3135556")
+
+		service := &Deezer{client: client}
+
+		assert.NoError(t, service.UnloveTrack(context.Background(), track))
+	})
+}
@@ -0,0 +1,276 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dietrichm/admirer/domain"
+)
+
+const (
+	formatCSV  = ".csv"
+	formatJSON = ".json"
+)
+
+// File is the service implementation reading and writing loved tracks in a local CSV or JSON file.
+type File struct {
+	filename string
+	format   string
+	tracks   []domain.Track
+	index    map[string]int
+	changed  bool
+}
+
+// NewFile creates a File instance for the file at the given path.
+// The format is determined by the file extension. A file which does not exist yet is created when closing the service.
+func NewFile(filename string) (*File, error) {
+	format := strings.ToLower(filepath.Ext(filename))
+	if format != formatCSV && format != formatJSON {
+		return nil, fmt.Errorf("unsupported file format %q, expected .csv or .json", filepath.Ext(filename))
+	}
+
+	service := &File{
+		filename: filename,
+		format:   format,
+	}
+
+	if err := service.read(); err != nil {
+		return nil, err
+	}
+
+	return service, nil
+}
+
+// Name returns the human-readable service name.
+func (f *File) Name() string {
+	return "file:" + f.filename
+}
+
+// Authenticated returns whether the service is logged in.
+// Files do not require authentication.
+func (f *File) Authenticated() bool {
+	return true
+}
+
+// CreateAuthURL returns an authorization URL to authorize the integration.
+func (f *File) CreateAuthURL(redirectURL string) string {
+	return ""
+}
+
+// CodeParam is the query parameter name used in the authentication callback.
+func (f *File) CodeParam() string {
+	return ""
+}
+
+// Authenticate takes an authorization code and authenticates the user.
+func (f *File) Authenticate(ctx context.Context, code string, redirectURL string) error {
+	return errors.New("files do not require logging in")
+}
+
+// GetUsername returns the name of the file.
+func (f *File) GetUsername(ctx context.Context) (string, error) {
+	return filepath.Base(f.filename), nil
+}
+
+// GetLovedTracks returns loved tracks from the file.
+func (f *File) GetLovedTracks(ctx context.Context, limit int, page int) ([]domain.Track, error) {
+	result, err := f.GetLovedTracksPage(ctx, limit, page)
+	return result.Tracks, err
+}
+
+// GetLovedTracksPage returns loved tracks from the file, along with the number of tracks in the file.
+func (f *File) GetLovedTracksPage(ctx context.Context, limit int, page int) (domain.TrackPage, error) {
+	result := domain.TrackPage{Total: len(f.tracks)}
+
+	start := (page - 1) * limit
+	if start >= len(f.tracks) {
+		return result, nil
+	}
+
+	end := start + limit
+	if end > len(f.tracks) {
+		end = len(f.tracks)
+	}
+
+	result.Tracks = f.tracks[start:end]
+	return result, nil
+}
+
+// FindTrack looks up the track in the file.
+// Tracks which are not in the file yet are matched as is, so they can be added.
+func (f *File) FindTrack(ctx context.Context, track domain.Track) (domain.Match, error) {
+	if index := f.indexOf(track); index >= 0 {
+		track = f.tracks[index]
+	}
+
+	match := domain.Match{
+		Track:      track,
+		Confidence: 1,
+		Method:     domain.MatchByName,
+	}
+	return match, nil
+}
+
+// LoveTrack adds the track to the file.
+func (f *File) LoveTrack(ctx context.Context, track domain.Track) error {
+	if f.indexOf(track) >= 0 {
+		return nil
+	}
+
+	f.tracks = append(f.tracks, track)
+	f.indexTrack(len(f.tracks)-1, track)
+	f.changed = true
+	return nil
+}
+
+// UnloveTrack removes the track from the file.
+func (f *File) UnloveTrack(ctx context.Context, track domain.Track) error {
+	index := f.indexOf(track)
+	if index < 0 {
+		return nil
+	}
+
+	f.tracks = append(f.tracks[:index], f.tracks[index+1:]...)
+	f.reindex()
+	f.changed = true
+	return nil
+}
+
+// Loved returns whether the tracks are in the file.
+func (f *File) Loved(ctx context.Context, tracks []domain.Track) ([]bool, error) {
+	loved := make([]bool, len(tracks))
+	for index, track := range tracks {
+		loved[index] = f.indexOf(track) >= 0
+	}
+	return loved, nil
+}
+
+// Close writes the file when tracks were added or removed.
+func (f *File) Close() error {
+	if !f.changed {
+		return nil
+	}
+
+	sort.SliceStable(f.tracks, func(i, j int) bool {
+		return f.tracks[i].LovedAt.After(f.tracks[j].LovedAt)
+	})
+	f.reindex()
+
+	if err := f.write(); err != nil {
+		return err
+	}
+
+	f.changed = false
+	return nil
+}
+
+// permissions returns the permissions of the existing file, or the default permissions for a new file.
+func (f *File) permissions() fs.FileMode {
+	if info, err := os.Stat(f.filename); err == nil {
+		return info.Mode().Perm()
+	}
+	return 0644
+}
+
+// indexOf returns the position of the first track in the file sharing a key with the track, or -1 when there is none.
+func (f *File) indexOf(track domain.Track) int {
+	found := -1
+	for _, key := range domain.TrackKeys(track) {
+		if index, exists := f.index[key]; exists && (found < 0 || index < found) {
+			found = index
+		}
+	}
+	return found
+}
+
+// indexTrack adds the keys of the track at the position to the index, unless an earlier track has the same key.
+func (f *File) indexTrack(position int, track domain.Track) {
+	if f.index == nil {
+		f.index = map[string]int{}
+	}
+
+	for _, key := range domain.TrackKeys(track) {
+		if _, exists := f.index[key]; !exists {
+			f.index[key] = position
+		}
+	}
+}
+
+func (f *File) reindex() {
+	f.index = map[string]int{}
+	for position, track := range f.tracks {
+		f.indexTrack(position, track)
+	}
+}
+
+func (f *File) read() error {
+	file, err := os.Open(f.filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed reading %s: %w", f.filename, err)
+	}
+	defer file.Close()
+
+	var records []Record
+	if f.format == formatCSV {
+		records, err = readCSV(file)
+	} else {
+		records, err = readJSON(file)
+	}
+	if err != nil {
+		return fmt.Errorf("failed reading %s: %w", f.filename, err)
+	}
+
+	for _, record := range records {
+		f.tracks = append(f.tracks, record.Track())
+	}
+	f.reindex()
+	return nil
+}
+
+func (f *File) write() error {
+	records := make([]Record, 0, len(f.tracks))
+	for _, track := range f.tracks {
+		records = append(records, RecordFromTrack(track))
+	}
+
+	directory := filepath.Dir(f.filename)
+	file, err := os.CreateTemp(directory, filepath.Base(f.filename)+".*")
+	if err != nil {
+		return fmt.Errorf("failed writing %s: %w", f.filename, err)
+	}
+	defer os.Remove(file.Name())
+
+	if err := file.Chmod(f.permissions()); err != nil {
+		file.Close()
+		return fmt.Errorf("failed writing %s: %w", f.filename, err)
+	}
+
+	if f.format == formatCSV {
+		err = writeCSV(file, records)
+	} else {
+		err = writeJSON(file, records)
+	}
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed writing %s: %w", f.filename, err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed writing %s: %w", f.filename, err)
+	}
+
+	if err := os.Rename(file.Name(), f.filename); err != nil {
+		return fmt.Errorf("failed writing %s: %w", f.filename, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dietrichm/admirer/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFile(t *testing.T) {
+	lovedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	track := domain.Track{
+		ID:         "sourceID",
+		URI:        "spotify:track:sourceID",
+		Artist:     "Foo",
+		Artists:    []string{"Foo", "Bar"},
+		Name:       "Mr. Testy",
+		Album:      "Testing, Testing",
+		Duration:   3*time.Minute + 25*time.Second,
+		ISRC:       "USABC2000001",
+		MBID:       "mbid",
+		ArtistMBID: "artistMBID",
+		LovedAt:    lovedAt,
+	}
+
+	t.Run("reads loved tracks from CSV file", func(t *testing.T) {
+		filename := writeFixture(t, "loved.csv", `artist,name,album,loved_at
+Foo & Bar,Mr. Testy,,2024-01-02T03:04:05Z
+"Awesome Artist","Blam, Instrumental",Album,
+`)
+
+		service, err := NewFile(filename)
+		assert.NoError(t, err)
+
+		got, err := service.GetLovedTracks(context.Background(), 10, 1)
+
+		expected := []domain.Track{
+			{Artist: "Foo & Bar", Name: "Mr. Testy", LovedAt: lovedAt},
+			{Artist: "Awesome Artist", Name: "Blam, Instrumental", Album: "Album"},
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got)
+	})
+
+	t.Run("reads loved tracks from JSON file", func(t *testing.T) {
+		filename := writeFixture(t, "loved.json", `[{"artist": "Foo & Bar", "name": "Mr. Testy", "duration_ms": 1500, "loved_at": "2024-01-02T03:04:05Z"}]`)
+
+		service, err := NewFile(filename)
+		assert.NoError(t, err)
+
+		got, err := service.GetLovedTracks(context.Background(), 10, 1)
+
+		expected := []domain.Track{
+			{Artist: "Foo & Bar", Name: "Mr. Testy", Duration: 1500 * time.Millisecond, LovedAt: lovedAt},
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got)
+	})
+
+	t.Run("returns pages of loved tracks", func(t *testing.T) {
+		filename := writeFixture(t, "loved.csv", "artist,name\nA,One\nB,Two\nC,Three\n")
+
+		service, _ := NewFile(filename)
+
+		got, _ := service.GetLovedTracks(context.Background(), 2, 2)
+		assert.Equal(t, []domain.Track{{Artist: "C", Name: "Three"}}, got)
+
+		got, _ = service.GetLovedTracks(context.Background(), 2, 3)
+		assert.Empty(t, got)
+
+		page, _ := service.GetLovedTracksPage(context.Background(), 2, 1)
+		assert.Len(t, page.Tracks, 2)
+		assert.Equal(t, 3, page.Total)
+	})
+
+	for _, extension := range []string{".csv", ".json"} {
+		t.Run("writes loved tracks to new "+extension+" file", func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "loved"+extension)
+			older := domain.Track{Artist: "Awesome Artist", Name: "Blam"}
+
+			service, err := NewFile(filename)
+			assert.NoError(t, err)
+
+			assert.NoError(t, service.LoveTrack(context.Background(), older))
+			assert.NoError(t, service.LoveTrack(context.Background(), track))
+			assert.NoError(t, service.LoveTrack(context.Background(), domain.Track{Artist: "Bar", Name: "Mr. Testy"}))
+			assert.NoError(t, service.Close())
+
+			stat, err := os.Stat(filename)
+			assert.NoError(t, err)
+			assert.Equal(t, "-rw-r--r--", stat.Mode().Perm().String())
+
+			loaded, err := NewFile(filename)
+			assert.NoError(t, err)
+
+			got, _ := loaded.GetLovedTracks(context.Background(), 10, 1)
+			assert.Equal(t, []domain.Track{track, older}, got)
+		})
+	}
+
+	t.Run("returns whether tracks are in file", func(t *testing.T) {
+		filename := writeFixture(t, "loved.csv", "artist,name\nFoo & Bar,Mr. Testy\n")
+
+		service, _ := NewFile(filename)
+
+		got, err := service.Loved(context.Background(), []domain.Track{{Artist: "Foo", Name: "Mr Testy - Remastered"}, {Artist: "Foo", Name: "Other"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{true, false}, got)
+	})
+
+	t.Run("removes unloved tracks from file", func(t *testing.T) {
+		filename := writeFixture(t, "loved.csv", "artist,name\nFoo & Bar,Mr. Testy\nAwesome Artist,Blam\n")
+
+		service, _ := NewFile(filename)
+
+		assert.NoError(t, service.UnloveTrack(context.Background(), domain.Track{Artist: "Foo & Bar", Name: "Mr. Testy"}))
+		assert.NoError(t, service.Close())
+
+		contents, err := os.ReadFile(filename)
+		assert.NoError(t, err)
+		assert.Equal(t, "artist,name,album,artists,duration_ms,isrc,mbid,artist_mbid,id,uri,loved_at\nAwesome Artist,Blam,,,,,,,,,\n", string(contents))
+	})
+
+	t.Run("finds tracks after loving and unloving tracks", func(t *testing.T) {
+		filename := writeFixture(t, "loved.csv", "artist,name,album\nFoo & Bar,Mr. Testy,Testing\nAwesome Artist,Blam,Blam\n")
+
+		service, _ := NewFile(filename)
+		ctx := context.Background()
+
+		assert.NoError(t, service.LoveTrack(ctx, domain.Track{Artist: "Someone", Name: "New", Album: "Fresh"}))
+		assert.NoError(t, service.UnloveTrack(ctx, domain.Track{Artist: "Foo", Name: "Mr. Testy"}))
+
+		got, err := service.FindTrack(ctx, domain.Track{Artist: "Awesome Artist", Name: "Blam"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Blam", got.Track.Album)
+
+		got, err = service.FindTrack(ctx, domain.Track{Artist: "Someone", Name: "New (Remastered)"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Fresh", got.Track.Album)
+
+		loved, err := service.Loved(ctx, []domain.Track{{Artist: "Foo & Bar", Name: "Mr. Testy"}, {Artist: "Someone", Name: "New"}})
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{false, true}, loved)
+	})
+
+	t.Run("does not write file when nothing changed", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "loved.csv")
+
+		service, _ := NewFile(filename)
+
+		assert.NoError(t, service.Close())
+
+		_, err := os.Stat(filename)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("returns error for unsupported file format", func(t *testing.T) {
+		_, err := NewFile("loved.txt")
+
+		assert.EqualError(t, err, `unsupported file format ".txt", expected .csv or .json`)
+	})
+
+	t.Run("returns error for invalid file", func(t *testing.T) {
+		filename := writeFixture(t, "loved.csv", "artist,title\nFoo,Bar\n")
+
+		_, err := NewFile(filename)
+
+		assert.ErrorContains(t, err, `missing "name" column`)
+	})
+}
+
+func writeFixture(t *testing.T, name string, contents string) string {
+	filename := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(filename, []byte(contents), 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return filename
+}
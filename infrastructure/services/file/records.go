@@ -0,0 +1,205 @@
+package file
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dietrichm/admirer/domain"
+)
+
+// ArtistSeparator separates multiple artists in a single CSV column.
+const ArtistSeparator = "; "
+
+// Columns are the CSV columns of a track, in the order of Record.Values.
+var Columns = []string{"artist", "name", "album", "artists", "duration_ms", "isrc", "mbid", "artist_mbid", "id", "uri", "loved_at"}
+
+// Record is a track as stored in CSV and JSON files.
+type Record struct {
+	Artist     string     `json:"artist"`
+	Name       string     `json:"name"`
+	Album      string     `json:"album,omitempty"`
+	Artists    []string   `json:"artists,omitempty"`
+	DurationMs int64      `json:"duration_ms,omitempty"`
+	ISRC       string     `json:"isrc,omitempty"`
+	MBID       string     `json:"mbid,omitempty"`
+	ArtistMBID string     `json:"artist_mbid,omitempty"`
+	ID         string     `json:"id,omitempty"`
+	URI        string     `json:"uri,omitempty"`
+	LovedAt    *time.Time `json:"loved_at,omitempty"`
+}
+
+// RecordFromTrack converts a track to a record.
+func RecordFromTrack(track domain.Track) Record {
+	record := Record{
+		Artist:     track.Artist,
+		Name:       track.Name,
+		Album:      track.Album,
+		Artists:    track.Artists,
+		DurationMs: track.Duration.Milliseconds(),
+		ISRC:       track.ISRC,
+		MBID:       track.MBID,
+		ArtistMBID: track.ArtistMBID,
+		ID:         track.ID,
+		URI:        track.URI,
+	}
+	if !track.LovedAt.IsZero() {
+		lovedAt := track.LovedAt.UTC()
+		record.LovedAt = &lovedAt
+	}
+	return record
+}
+
+// Track converts the record back to a track.
+func (r Record) Track() domain.Track {
+	track := domain.Track{
+		ID:         r.ID,
+		URI:        r.URI,
+		Artist:     r.Artist,
+		Artists:    r.Artists,
+		Name:       r.Name,
+		Album:      r.Album,
+		Duration:   time.Duration(r.DurationMs) * time.Millisecond,
+		ISRC:       r.ISRC,
+		MBID:       r.MBID,
+		ArtistMBID: r.ArtistMBID,
+	}
+	if track.Artist == "" && len(track.Artists) > 0 {
+		track.Artist = track.Artists[0]
+	}
+	if r.LovedAt != nil {
+		track.LovedAt = *r.LovedAt
+	}
+	return track
+}
+
+// Values returns the CSV columns of the record.
+func (r Record) Values() []string {
+	var duration, lovedAt string
+	if r.DurationMs > 0 {
+		duration = strconv.FormatInt(r.DurationMs, 10)
+	}
+	if r.LovedAt != nil {
+		lovedAt = r.LovedAt.Format(time.RFC3339)
+	}
+
+	return []string{
+		r.Artist,
+		r.Name,
+		r.Album,
+		strings.Join(r.Artists, ArtistSeparator),
+		duration,
+		r.ISRC,
+		r.MBID,
+		r.ArtistMBID,
+		r.ID,
+		r.URI,
+		lovedAt,
+	}
+}
+
+func readJSON(reader io.Reader) (records []Record, err error) {
+	err = json.NewDecoder(reader).Decode(&records)
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	return
+}
+
+func writeJSON(writer io.Writer, records []Record) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
+
+func readCSV(reader io.Reader) ([]Record, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+
+	header, err := csvReader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]int{}
+	for index, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = index
+	}
+	if _, exists := columns["name"]; !exists {
+		return nil, errors.New(`missing "name" column`)
+	}
+
+	var records []Record
+	for {
+		row, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		value := func(column string) string {
+			index, exists := columns[column]
+			if !exists || index >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[index])
+		}
+
+		record := Record{
+			Artist:     value("artist"),
+			Name:       value("name"),
+			Album:      value("album"),
+			ISRC:       value("isrc"),
+			MBID:       value("mbid"),
+			ArtistMBID: value("artist_mbid"),
+			ID:         value("id"),
+			URI:        value("uri"),
+		}
+		if artists := value("artists"); artists != "" {
+			record.Artists = strings.Split(artists, strings.TrimSpace(ArtistSeparator))
+			for index, artist := range record.Artists {
+				record.Artists[index] = strings.TrimSpace(artist)
+			}
+		}
+		if duration := value("duration_ms"); duration != "" {
+			if record.DurationMs, err = strconv.ParseInt(duration, 10, 64); err != nil {
+				return nil, fmt.Errorf("invalid duration %q on line %d", duration, len(records)+2)
+			}
+		}
+		if lovedAt := value("loved_at"); lovedAt != "" {
+			parsed, err := time.Parse(time.RFC3339, lovedAt)
+			if err != nil {
+				return nil, fmt.Errorf("invalid loved at time %q on line %d", lovedAt, len(records)+2)
+			}
+			record.LovedAt = &parsed
+		}
+
+		records = append(records, record)
+	}
+}
+
+func writeCSV(writer io.Writer, records []Record) error {
+	csvWriter := csv.NewWriter(writer)
+	if err := csvWriter.Write(Columns); err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		if err := csvWriter.Write(record.Values()); err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
@@ -3,12 +3,16 @@
 package lastfm
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/dietrichm/admirer/domain"
 	"github.com/dietrichm/admirer/infrastructure/config"
+	"github.com/dietrichm/admirer/infrastructure/retry"
 	"github.com/shkh/lastfm-go/lastfm"
 )
 
@@ -27,14 +31,19 @@ type UserAPI interface {
 
 // TrackAPI is our interface for a Last.fm track API.
 type TrackAPI interface {
+	GetInfo(args map[string]interface{}) (result lastfm.TrackGetInfo, err error)
 	Love(args map[string]interface{}) (err error)
+	UnLove(args map[string]interface{}) (err error)
 }
 
+const requestsPerSecond = 5
+
 // Lastfm is the external Lastfm service implementation.
 type Lastfm struct {
 	api      API
 	userAPI  UserAPI
 	trackAPI TrackAPI
+	retrier  retrier
 	secrets  config.Config
 }
 
@@ -50,10 +59,16 @@ func NewLastfm(secrets config.Config) (*Lastfm, error) {
 	api := lastfm.New(clientID, clientSecret)
 	api.SetSession(secrets.GetString("session_key"))
 
+	retrier := retrier{
+		policy:  retry.DefaultPolicy,
+		limiter: retry.NewLimiter(retry.RequestsPerSecond("LASTFM_REQUESTS_PER_SECOND", requestsPerSecond)),
+	}
+
 	return &Lastfm{
 		api:      api,
 		userAPI:  api.User,
 		trackAPI: api.Track,
+		retrier:  retrier,
 		secrets:  secrets,
 	}, nil
 }
@@ -79,7 +94,7 @@ func (l *Lastfm) CodeParam() string {
 }
 
 // Authenticate takes an authorization code and authenticates the user.
-func (l *Lastfm) Authenticate(oauthCode string, redirectURL string) error {
+func (l *Lastfm) Authenticate(ctx context.Context, oauthCode string, redirectURL string) error {
 	if err := l.api.LoginWithToken(oauthCode); err != nil {
 		return fmt.Errorf("failed to authenticate on Last.fm: %w", err)
 	}
@@ -94,8 +109,12 @@ func (l *Lastfm) Authenticate(oauthCode string, redirectURL string) error {
 }
 
 // GetUsername requests and returns the username of the logged in user.
-func (l *Lastfm) GetUsername() (string, error) {
-	user, err := l.userAPI.GetInfo(lastfm.P{})
+func (l *Lastfm) GetUsername(ctx context.Context) (string, error) {
+	var user lastfm.UserGetInfo
+	err := l.retrier.do(ctx, func() (err error) {
+		user, err = l.userAPI.GetInfo(lastfm.P{})
+		return
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed to read Last.fm profile data: %w", err)
 	}
@@ -104,43 +123,126 @@ func (l *Lastfm) GetUsername() (string, error) {
 }
 
 // GetLovedTracks returns loved tracks from the external service.
-func (l *Lastfm) GetLovedTracks(limit int, page int) (tracks []domain.Track, err error) {
-	username, err := l.GetUsername()
+func (l *Lastfm) GetLovedTracks(ctx context.Context, limit int, page int) ([]domain.Track, error) {
+	result, err := l.GetLovedTracksPage(ctx, limit, page)
+	return result.Tracks, err
+}
+
+// GetLovedTracksPage returns loved tracks from the external service, along with the total number of loved tracks.
+func (l *Lastfm) GetLovedTracksPage(ctx context.Context, limit int, page int) (result domain.TrackPage, err error) {
+	username, err := l.GetUsername(ctx)
 	if err != nil {
 		return
 	}
 
-	result, err := l.userAPI.GetLovedTracks(lastfm.P{
-		"user":  username,
-		"limit": limit,
-		"page":  page,
+	var lovedTracks lastfm.UserGetLovedTracks
+	err = l.retrier.do(ctx, func() (err error) {
+		lovedTracks, err = l.userAPI.GetLovedTracks(lastfm.P{
+			"user":  username,
+			"limit": limit,
+			"page":  page,
+		})
+		return
 	})
 	if err != nil {
-		return tracks, fmt.Errorf("failed to read Last.fm loved tracks: %w", err)
+		return result, fmt.Errorf("failed to read Last.fm loved tracks: %w", err)
 	}
 
-	for _, resultTrack := range result.Tracks {
+	for _, resultTrack := range lovedTracks.Tracks {
 		track := domain.Track{
-			Artist: resultTrack.Artist.Name,
-			Name:   resultTrack.Name,
+			URI:        resultTrack.Url,
+			Artist:     resultTrack.Artist.Name,
+			Artists:    []string{resultTrack.Artist.Name},
+			Name:       resultTrack.Name,
+			MBID:       resultTrack.Mbid,
+			ArtistMBID: resultTrack.Artist.Mbid,
 		}
-		tracks = append(tracks, track)
+		if timestamp, err := strconv.ParseInt(resultTrack.Date.Uts, 10, 64); err == nil {
+			track.LovedAt = time.Unix(timestamp, 0).UTC()
+		}
+		result.Tracks = append(result.Tracks, track)
 	}
+	result.Total = lovedTracks.Total
 	return
 }
 
-// LoveTrack marks a track as loved on the external service.
-func (l *Lastfm) LoveTrack(track domain.Track) error {
-	if err := l.trackAPI.Love(lastfm.P{
-		"track":  track.Name,
-		"artist": track.Artist,
-	}); err != nil {
+// FindTrack looks up the track on the external service.
+// Last.fm identifies tracks by artist and title, so these are used as is.
+func (l *Lastfm) FindTrack(ctx context.Context, track domain.Track) (domain.Match, error) {
+	match := domain.Match{
+		Track: domain.Track{
+			Artist: track.Artist,
+			Name:   track.Name,
+		},
+		Confidence: 1,
+		Method:     domain.MatchByName,
+	}
+	return match, nil
+}
+
+// LoveTrack marks a track found on the external service as loved.
+func (l *Lastfm) LoveTrack(ctx context.Context, track domain.Track) error {
+	err := l.retrier.do(ctx, func() error {
+		return l.trackAPI.Love(lastfm.P{
+			"track":  track.Name,
+			"artist": track.Artist,
+		})
+	})
+	if err != nil {
 		return fmt.Errorf("failed to mark track as loved on Last.fm: %w", err)
 	}
 
 	return nil
 }
 
+// UnloveTrack removes a track found on the external service from the loved tracks.
+func (l *Lastfm) UnloveTrack(ctx context.Context, track domain.Track) error {
+	err := l.retrier.do(ctx, func() error {
+		return l.trackAPI.UnLove(lastfm.P{
+			"track":  track.Name,
+			"artist": track.Artist,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to unlove track on Last.fm: %w", err)
+	}
+
+	return nil
+}
+
+// Loved returns whether the tracks found on the external service are loved by the logged in user.
+func (l *Lastfm) Loved(ctx context.Context, tracks []domain.Track) ([]bool, error) {
+	username, err := l.GetUsername(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	loved := make([]bool, len(tracks))
+	for index, track := range tracks {
+		var result lastfm.TrackGetInfo
+		err := l.retrier.do(ctx, func() (err error) {
+			result, err = l.trackAPI.GetInfo(lastfm.P{
+				"track":    track.Name,
+				"artist":   track.Artist,
+				"username": username,
+			})
+			return
+		})
+
+		var lastfmError *lastfm.LastfmError
+		if errors.As(err, &lastfmError) && lastfmError.Code == errorInvalidParameters {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Last.fm track info: %w", err)
+		}
+
+		loved[index] = result.UserLoved == "1"
+	}
+
+	return loved, nil
+}
+
 // Close persists any state before quitting the application.
 func (l *Lastfm) Close() error {
 	return nil
@@ -157,6 +157,21 @@ func (m *MockTrackAPI) EXPECT() *MockTrackAPIMockRecorder {
 	return m.recorder
 }
 
+// GetInfo mocks base method.
+func (m *MockTrackAPI) GetInfo(args map[string]any) (lastfm.TrackGetInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInfo", args)
+	ret0, _ := ret[0].(lastfm.TrackGetInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInfo indicates an expected call of GetInfo.
+func (mr *MockTrackAPIMockRecorder) GetInfo(args any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInfo", reflect.TypeOf((*MockTrackAPI)(nil).GetInfo), args)
+}
+
 // Love mocks base method.
 func (m *MockTrackAPI) Love(args map[string]any) error {
 	m.ctrl.T.Helper()
@@ -170,3 +185,17 @@ func (mr *MockTrackAPIMockRecorder) Love(args any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Love", reflect.TypeOf((*MockTrackAPI)(nil).Love), args)
 }
+
+// UnLove mocks base method.
+func (m *MockTrackAPI) UnLove(args map[string]any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnLove", args)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnLove indicates an expected call of UnLove.
+func (mr *MockTrackAPIMockRecorder) UnLove(args any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnLove", reflect.TypeOf((*MockTrackAPI)(nil).UnLove), args)
+}
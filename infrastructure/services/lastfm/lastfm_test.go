@@ -1,10 +1,13 @@
 package lastfm
 
 import (
+	"context"
 	"errors"
 	"go.uber.org/mock/gomock"
 	"os"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/dietrichm/admirer/domain"
 	"github.com/dietrichm/admirer/infrastructure/config"
@@ -68,7 +71,7 @@ func TestLastfm(t *testing.T) {
 			secrets: secrets,
 		}
 
-		err := service.Authenticate("authcode", "")
+		err := service.Authenticate(context.Background(), "authcode", "")
 
 		if err != nil {
 			t.Errorf("Unexpected error: %v", err)
@@ -83,7 +86,7 @@ func TestLastfm(t *testing.T) {
 
 		service := &Lastfm{api: api}
 
-		err := service.Authenticate("authcode", "")
+		err := service.Authenticate(context.Background(), "authcode", "")
 
 		if err == nil {
 			t.Fatal("Expected an error")
@@ -105,7 +108,7 @@ func TestLastfm(t *testing.T) {
 			api:     api,
 			secrets: secrets}
 
-		err := service.Authenticate("authcode", "")
+		err := service.Authenticate(context.Background(), "authcode", "")
 
 		if err == nil {
 			t.Fatal("Expected an error")
@@ -122,7 +125,7 @@ func TestLastfm(t *testing.T) {
 		service := &Lastfm{userAPI: userAPI}
 
 		expected := "Diana"
-		got, err := service.GetUsername()
+		got, err := service.GetUsername(context.Background())
 
 		if got != expected {
 			t.Errorf("expected %q, got %q", expected, got)
@@ -143,7 +146,7 @@ func TestLastfm(t *testing.T) {
 		service := &Lastfm{userAPI: userAPI}
 
 		expected := ""
-		got, err := service.GetUsername()
+		got, err := service.GetUsername(context.Background())
 
 		if got != expected {
 			t.Errorf("expected %q, got %q", expected, got)
@@ -182,12 +185,21 @@ func TestLastfm(t *testing.T) {
 			}{
 				{
 					Name: "Blam (Instrumental)",
+					Mbid: "trackMBID",
+					Url:  "https://www.last.fm/music/Awesome+Artist/_/Blam+(Instrumental)",
+					Date: struct {
+						Uts  string `xml:"uts,attr"`
+						Date string `xml:",chardata"`
+					}{
+						Uts: "1600000000",
+					},
 					Artist: struct {
 						Name string `xml:"name"`
 						Mbid string `xml:"mbid"`
 						Url  string `xml:"url"`
 					}{
 						Name: "Awesome Artist",
+						Mbid: "artistMBID",
 					},
 				},
 				{
@@ -216,24 +228,28 @@ func TestLastfm(t *testing.T) {
 
 		expected := []domain.Track{
 			{
-				Artist: "Awesome Artist",
-				Name:   "Blam (Instrumental)",
+				URI:        "https://www.last.fm/music/Awesome+Artist/_/Blam+(Instrumental)",
+				Artist:     "Awesome Artist",
+				Artists:    []string{"Awesome Artist"},
+				Name:       "Blam (Instrumental)",
+				MBID:       "trackMBID",
+				ArtistMBID: "artistMBID",
+				LovedAt:    time.Unix(1600000000, 0).UTC(),
 			},
 			{
-				Artist: "Foo & Bar",
-				Name:   "Mr. Testy",
+				Artist:  "Foo & Bar",
+				Artists: []string{"Foo & Bar"},
+				Name:    "Mr. Testy",
 			},
 		}
-		got, err := service.GetLovedTracks(5, 1)
+		got, err := service.GetLovedTracks(context.Background(), 5, 1)
 
 		if err != nil {
 			t.Errorf("Unexpected error: %v", err)
 		}
 
-		for index, expectedTrack := range expected {
-			if got[index] != expectedTrack {
-				t.Errorf("expected %q, got %q", expectedTrack, got[index])
-			}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
 		}
 	})
 
@@ -246,7 +262,7 @@ func TestLastfm(t *testing.T) {
 
 		service := &Lastfm{userAPI: userAPI}
 
-		got, err := service.GetLovedTracks(5, 1)
+		got, err := service.GetLovedTracks(context.Background(), 5, 1)
 
 		if err == nil {
 			t.Error("Expected an error")
@@ -268,7 +284,7 @@ func TestLastfm(t *testing.T) {
 
 		service := &Lastfm{userAPI: userAPI}
 
-		got, err := service.GetLovedTracks(5, 1)
+		got, err := service.GetLovedTracks(context.Background(), 5, 1)
 
 		if err == nil {
 			t.Error("Expected an error")
@@ -279,6 +295,34 @@ func TestLastfm(t *testing.T) {
 		}
 	})
 
+	t.Run("finds track by artist and title", func(t *testing.T) {
+		service := &Lastfm{}
+
+		track := domain.Track{
+			ID:     "sourceID",
+			Artist: "Foo & Bar",
+			Name:   "Mr. Testy",
+		}
+
+		got, err := service.FindTrack(context.Background(), track)
+
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		expected := domain.Match{
+			Track: domain.Track{
+				Artist: "Foo & Bar",
+				Name:   "Mr. Testy",
+			},
+			Confidence: 1,
+			Method:     domain.MatchByName,
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+	})
+
 	t.Run("marks track as loved", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 
@@ -297,7 +341,7 @@ func TestLastfm(t *testing.T) {
 			Name:   "Mr. Testy",
 		}
 
-		err := service.LoveTrack(track)
+		err := service.LoveTrack(context.Background(), track)
 
 		if err != nil {
 			t.Errorf("Unexpected error: %v", err)
@@ -319,7 +363,111 @@ func TestLastfm(t *testing.T) {
 			Name:   "Mr. Testy",
 		}
 
-		err := service.LoveTrack(track)
+		err := service.LoveTrack(context.Background(), track)
+
+		if err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("unloves track", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		trackAPI := NewMockTrackAPI(ctrl)
+		trackAPI.EXPECT().UnLove(lastfm.P{
+			"track":  "Mr. Testy",
+			"artist": "Foo & Bar",
+		}).Return(nil)
+
+		service := &Lastfm{
+			trackAPI: trackAPI,
+		}
+
+		err := service.UnloveTrack(context.Background(), domain.Track{Artist: "Foo & Bar", Name: "Mr. Testy"})
+
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("returns error when unloving track fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		trackAPI := NewMockTrackAPI(ctrl)
+		trackAPI.EXPECT().UnLove(gomock.Any()).Return(errors.New("api error"))
+
+		service := &Lastfm{
+			trackAPI: trackAPI,
+		}
+
+		err := service.UnloveTrack(context.Background(), domain.Track{Artist: "Foo & Bar", Name: "Mr. Testy"})
+
+		if err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("returns whether tracks are loved by user", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		userAPI := NewMockUserAPI(ctrl)
+		userAPI.EXPECT().GetInfo(gomock.Any()).Return(lastfm.UserGetInfo{Name: "foobar"}, nil)
+
+		trackAPI := NewMockTrackAPI(ctrl)
+		trackAPI.EXPECT().GetInfo(lastfm.P{
+			"track":    "Mr. Testy",
+			"artist":   "Foo & Bar",
+			"username": "foobar",
+		}).Return(lastfm.TrackGetInfo{UserLoved: "1"}, nil)
+		trackAPI.EXPECT().GetInfo(lastfm.P{
+			"track":    "Blam",
+			"artist":   "Awesome Artist",
+			"username": "foobar",
+		}).Return(lastfm.TrackGetInfo{UserLoved: "0"}, nil)
+		trackAPI.EXPECT().GetInfo(lastfm.P{
+			"track":    "Missing",
+			"artist":   "Unknown",
+			"username": "foobar",
+		}).Return(lastfm.TrackGetInfo{}, &lastfm.LastfmError{Code: errorInvalidParameters})
+
+		service := &Lastfm{
+			userAPI:  userAPI,
+			trackAPI: trackAPI,
+		}
+
+		tracks := []domain.Track{
+			{Artist: "Foo & Bar", Name: "Mr. Testy"},
+			{Artist: "Awesome Artist", Name: "Blam"},
+			{Artist: "Unknown", Name: "Missing"},
+		}
+
+		got, err := service.Loved(context.Background(), tracks)
+
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		expected := []bool{true, false, false}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("returns error when failing to read track info", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		userAPI := NewMockUserAPI(ctrl)
+		userAPI.EXPECT().GetInfo(gomock.Any()).Return(lastfm.UserGetInfo{Name: "foobar"}, nil)
+
+		trackAPI := NewMockTrackAPI(ctrl)
+		trackAPI.EXPECT().GetInfo(gomock.Any()).Return(lastfm.TrackGetInfo{}, errors.New("api error"))
+
+		service := &Lastfm{
+			userAPI:  userAPI,
+			trackAPI: trackAPI,
+		}
+
+		_, err := service.Loved(context.Background(), []domain.Track{{Artist: "Foo & Bar", Name: "Mr. Testy"}})
 
 		if err == nil {
 			t.Error("Expected an error")
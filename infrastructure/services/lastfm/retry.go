@@ -0,0 +1,50 @@
+package lastfm
+
+import (
+	"context"
+	"errors"
+	"net/url"
+
+	"github.com/dietrichm/admirer/infrastructure/retry"
+	"github.com/shkh/lastfm-go/lastfm"
+)
+
+// Last.fm error codes.
+// See https://www.last.fm/api/errorcodes.
+const (
+	errorInvalidParameters    = 6
+	errorOperationFailed      = 8
+	errorServiceOffline       = 11
+	errorTemporaryUnavailable = 16
+	errorRateLimitExceeded    = 29
+)
+
+// retrier retries Last.fm API calls within a requests per second budget.
+// The Last.fm library creates its own HTTP clients, so calls are retried rather than HTTP requests.
+// It does not take a context either, so cancelling stops waiting and retrying, but not a call in progress.
+type retrier struct {
+	policy  retry.Policy
+	limiter *retry.Limiter
+}
+
+func (r retrier) do(ctx context.Context, fn func() error) error {
+	return retry.Do(ctx, r.policy, r.limiter, retryable, fn)
+}
+
+func retryable(err error) bool {
+	var urlError *url.Error
+	if errors.As(err, &urlError) {
+		return true
+	}
+
+	var lastfmError *lastfm.LastfmError
+	if !errors.As(err, &lastfmError) {
+		return false
+	}
+
+	switch lastfmError.Code {
+	case errorOperationFailed, errorServiceOffline, errorTemporaryUnavailable, errorRateLimitExceeded:
+		return true
+	}
+	return lastfmError.Code/100 == 5
+}
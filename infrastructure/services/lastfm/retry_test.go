@@ -0,0 +1,90 @@
+package lastfm
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"testing"
+
+	"github.com/dietrichm/admirer/domain"
+	"github.com/dietrichm/admirer/infrastructure/retry"
+	"github.com/shkh/lastfm-go/lastfm"
+)
+
+func TestRetryingAPI(t *testing.T) {
+	retrier := retrier{
+		policy: retry.Policy{MaxAttempts: 3},
+	}
+
+	t.Run("retries rate limited requests", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		rateLimited := &lastfm.LastfmError{Code: errorRateLimitExceeded}
+		userAPI := NewMockUserAPI(ctrl)
+		gomock.InOrder(
+			userAPI.EXPECT().GetInfo(gomock.Any()).Return(lastfm.UserGetInfo{}, rateLimited),
+			userAPI.EXPECT().GetInfo(gomock.Any()).Return(lastfm.UserGetInfo{Name: "foobar"}, nil),
+		)
+
+		service := &Lastfm{userAPI: userAPI, retrier: retrier}
+
+		got, err := service.GetUsername(context.Background())
+
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		if got != "foobar" {
+			t.Errorf("expected %q, got %q", "foobar", got)
+		}
+	})
+
+	t.Run("retries server errors until running out of attempts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		serverError := &lastfm.LastfmError{Code: 503}
+		trackAPI := NewMockTrackAPI(ctrl)
+		trackAPI.EXPECT().Love(gomock.Any()).Times(3).Return(serverError)
+
+		service := &Lastfm{trackAPI: trackAPI, retrier: retrier}
+
+		err := service.LoveTrack(context.Background(), domain.Track{})
+
+		if !errors.Is(err, serverError) {
+			t.Errorf("expected %v, got %v", serverError, err)
+		}
+	})
+
+	t.Run("does not retry permanent errors", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		invalidParameters := &lastfm.LastfmError{Code: 6}
+		userAPI := NewMockUserAPI(ctrl)
+		userAPI.EXPECT().GetInfo(gomock.Any()).Return(lastfm.UserGetInfo{Name: "foobar"}, nil)
+		userAPI.EXPECT().GetLovedTracks(gomock.Any()).Return(lastfm.UserGetLovedTracks{}, invalidParameters)
+
+		service := &Lastfm{userAPI: userAPI, retrier: retrier}
+
+		_, err := service.GetLovedTracks(context.Background(), 10, 1)
+
+		if !errors.Is(err, invalidParameters) {
+			t.Errorf("expected %v, got %v", invalidParameters, err)
+		}
+	})
+
+	t.Run("does not call API when context is cancelled", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		trackAPI := NewMockTrackAPI(ctrl)
+		service := &Lastfm{trackAPI: trackAPI, retrier: retrier}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := service.UnloveTrack(ctx, domain.Track{})
+
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected %v, got %v", context.Canceled, err)
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package listenbrainz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/dietrichm/admirer/domain"
+)
+
+// APIError is an error response of the ListenBrainz API.
+type APIError struct {
+	Code    int    `json:"code"`
+	Message string `json:"error"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("ListenBrainz API error %d: %s", e.Code, e.Message)
+}
+
+type validateTokenResponse struct {
+	Valid    bool   `json:"valid"`
+	Username string `json:"user_name"`
+}
+
+type feedbackRequest struct {
+	RecordingMBID string `json:"recording_mbid"`
+	Score         int    `json:"score"`
+}
+
+type feedbackResponse struct {
+	TotalCount int        `json:"total_count"`
+	Feedback   []feedback `json:"feedback"`
+}
+
+type feedback struct {
+	Created       int64          `json:"created"`
+	RecordingMBID string         `json:"recording_mbid"`
+	Score         int            `json:"score"`
+	TrackMetadata *trackMetadata `json:"track_metadata"`
+}
+
+type trackMetadata struct {
+	ArtistName  string `json:"artist_name"`
+	TrackName   string `json:"track_name"`
+	ReleaseName string `json:"release_name"`
+	MBIDMapping *struct {
+		ArtistMBIDs []string `json:"artist_mbids"`
+	} `json:"mbid_mapping"`
+}
+
+func (f feedback) track() domain.Track {
+	track := domain.Track{
+		ID:   f.RecordingMBID,
+		MBID: f.RecordingMBID,
+	}
+
+	if f.RecordingMBID != "" {
+		track.URI = recordingURL + f.RecordingMBID
+	}
+
+	if f.Created > 0 {
+		track.LovedAt = time.Unix(f.Created, 0).UTC()
+	}
+
+	if metadata := f.TrackMetadata; metadata != nil {
+		track.Artist = metadata.ArtistName
+		track.Artists = []string{metadata.ArtistName}
+		track.Name = metadata.TrackName
+		track.Album = metadata.ReleaseName
+
+		if metadata.MBIDMapping != nil && len(metadata.MBIDMapping.ArtistMBIDs) > 0 {
+			track.ArtistMBID = metadata.MBIDMapping.ArtistMBIDs[0]
+		}
+	}
+
+	return track
+}
+
+type lookupResponse struct {
+	ArtistCreditName string   `json:"artist_credit_name"`
+	ArtistMBIDs      []string `json:"artist_mbids"`
+	RecordingMBID    string   `json:"recording_mbid"`
+	RecordingName    string   `json:"recording_name"`
+	ReleaseName      string   `json:"release_name"`
+}
+
+func (l lookupResponse) track() domain.Track {
+	track := domain.Track{
+		ID:      l.RecordingMBID,
+		URI:     recordingURL + l.RecordingMBID,
+		Artist:  l.ArtistCreditName,
+		Artists: []string{l.ArtistCreditName},
+		Name:    l.RecordingName,
+		Album:   l.ReleaseName,
+		MBID:    l.RecordingMBID,
+	}
+
+	if len(l.ArtistMBIDs) > 0 {
+		track.ArtistMBID = l.ArtistMBIDs[0]
+	}
+
+	return track
+}
+
+// request performs an API request authorized with the stored user token.
+func (l *ListenBrainz) request(ctx context.Context, method string, path string, query url.Values, body interface{}, result interface{}) error {
+	return l.requestWithToken(ctx, l.secrets.GetString("token"), method, path, query, body, result)
+}
+
+func (l *ListenBrainz) requestWithToken(ctx context.Context, token string, method string, path string, query url.Values, body interface{}, result interface{}) error {
+	endpoint := l.apiURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var requestBody io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		requestBody = bytes.NewReader(encoded)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, endpoint, requestBody)
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Authorization", "Token "+token)
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := l.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		apiError := &APIError{Code: response.StatusCode}
+		if err := json.NewDecoder(response.Body).Decode(apiError); err != nil || apiError.Message == "" {
+			apiError.Message = http.StatusText(response.StatusCode)
+		}
+		return apiError
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return json.NewDecoder(response.Body).Decode(result)
+}
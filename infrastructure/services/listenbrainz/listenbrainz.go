@@ -0,0 +1,280 @@
+package listenbrainz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dietrichm/admirer/domain"
+	"github.com/dietrichm/admirer/infrastructure/config"
+	"github.com/dietrichm/admirer/infrastructure/retry"
+)
+
+const (
+	defaultAPIURL     = "https://api.listenbrainz.org"
+	tokenURL          = "https://listenbrainz.org/settings/"
+	recordingURL      = "https://musicbrainz.org/recording/"
+	requestsPerSecond = 5
+
+	scoreLoved   = 1
+	scoreNeutral = 0
+)
+
+// ListenBrainz is the external ListenBrainz service implementation.
+type ListenBrainz struct {
+	apiURL     string
+	httpClient *http.Client
+	secrets    config.Config
+	matcher    domain.Matcher
+}
+
+// NewListenBrainz creates a ListenBrainz instance.
+// The API of a self-hosted instance can be used by setting the LISTENBRAINZ_API_URL environment variable.
+func NewListenBrainz(secrets config.Config) (*ListenBrainz, error) {
+	apiURL := os.Getenv("LISTENBRAINZ_API_URL")
+	if apiURL == "" {
+		apiURL = defaultAPIURL
+	}
+
+	transport := retry.NewTransport(retry.RequestsPerSecond("LISTENBRAINZ_REQUESTS_PER_SECOND", requestsPerSecond))
+
+	return &ListenBrainz{
+		apiURL:     strings.TrimRight(apiURL, "/"),
+		httpClient: &http.Client{Transport: transport},
+		secrets:    secrets,
+		matcher:    domain.NewMatcher(domain.DefaultThreshold),
+	}, nil
+}
+
+// Name returns the human-readable service name.
+func (l *ListenBrainz) Name() string {
+	return "ListenBrainz"
+}
+
+// Authenticated returns whether the service is logged in.
+func (l *ListenBrainz) Authenticated() bool {
+	return l.secrets.GetString("token") != ""
+}
+
+// CreateAuthURL returns the URL on which users find their user token.
+func (l *ListenBrainz) CreateAuthURL(redirectURL string) string {
+	return tokenURL
+}
+
+// TokenURL returns the URL on which users find their user token.
+func (l *ListenBrainz) TokenURL() string {
+	return tokenURL
+}
+
+// CodeParam is the query parameter name used in the authentication callback.
+func (l *ListenBrainz) CodeParam() string {
+	return "token"
+}
+
+// Authenticate validates the user token and stores it in the secrets.
+func (l *ListenBrainz) Authenticate(ctx context.Context, token string, redirectURL string) error {
+	username, err := l.validateToken(ctx, token)
+	if err != nil {
+		return fmt.Errorf("failed to authenticate on ListenBrainz: %w", err)
+	}
+
+	l.secrets.Set("token", token)
+	l.secrets.Set("username", username)
+
+	if err := l.secrets.Save(); err != nil {
+		return fmt.Errorf("failed to save ListenBrainz secrets: %w", err)
+	}
+
+	return nil
+}
+
+// GetUsername returns the username of the logged-in user.
+func (l *ListenBrainz) GetUsername(ctx context.Context) (string, error) {
+	if username := l.secrets.GetString("username"); username != "" {
+		return username, nil
+	}
+
+	username, err := l.validateToken(ctx, l.secrets.GetString("token"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read ListenBrainz profile data: %w", err)
+	}
+
+	l.secrets.Set("username", username)
+	if err := l.secrets.Save(); err != nil {
+		return "", fmt.Errorf("failed to save ListenBrainz secrets: %w", err)
+	}
+
+	return username, nil
+}
+
+// GetLovedTracks returns loved tracks from the external service.
+func (l *ListenBrainz) GetLovedTracks(ctx context.Context, limit int, page int) ([]domain.Track, error) {
+	result, err := l.GetLovedTracksPage(ctx, limit, page)
+	return result.Tracks, err
+}
+
+// GetLovedTracksPage returns loved tracks from the external service, along with the total number of loved tracks.
+func (l *ListenBrainz) GetLovedTracksPage(ctx context.Context, limit int, page int) (result domain.TrackPage, err error) {
+	username, err := l.GetUsername(ctx)
+	if err != nil {
+		return
+	}
+
+	query := url.Values{
+		"score":    {strconv.Itoa(scoreLoved)},
+		"count":    {strconv.Itoa(limit)},
+		"offset":   {strconv.Itoa((page - 1) * limit)},
+		"metadata": {"true"},
+	}
+
+	var response feedbackResponse
+	if err := l.request(ctx, http.MethodGet, "/1/feedback/user/"+url.PathEscape(username)+"/get-feedback", query, nil, &response); err != nil {
+		return result, fmt.Errorf("failed to read ListenBrainz loved tracks: %w", err)
+	}
+
+	for _, feedback := range response.Feedback {
+		result.Tracks = append(result.Tracks, feedback.track())
+	}
+	result.Total = response.TotalCount
+	return
+}
+
+// FindTrack looks up the track on the external service.
+// Tracks with a MusicBrainz recording identifier are used as is, other tracks are looked up by artist and title.
+func (l *ListenBrainz) FindTrack(ctx context.Context, track domain.Track) (domain.Match, error) {
+	if track.MBID != "" {
+		match := domain.Match{
+			Track: domain.Track{
+				ID:     track.MBID,
+				URI:    recordingURL + track.MBID,
+				Artist: track.Artist,
+				Name:   track.Name,
+				MBID:   track.MBID,
+			},
+			Confidence: 1,
+			Method:     domain.MatchByMBID,
+		}
+		return match, nil
+	}
+
+	query := url.Values{
+		"artist_name":    {track.Artist},
+		"recording_name": {track.Name},
+	}
+
+	var result lookupResponse
+	if err := l.request(ctx, http.MethodGet, "/1/metadata/lookup/", query, nil, &result); err != nil {
+		return domain.Match{}, fmt.Errorf("failed to look up track on ListenBrainz: %w", err)
+	}
+
+	if result.RecordingMBID == "" {
+		return domain.Match{}, domain.ErrTrackNotFound
+	}
+
+	return l.matcher.Best(track, []domain.Track{result.track()})
+}
+
+// SetMatcher sets the matcher used to accept looked up tracks.
+func (l *ListenBrainz) SetMatcher(matcher domain.Matcher) {
+	l.matcher = matcher
+}
+
+// LoveTrack marks a track found on the external service as loved.
+func (l *ListenBrainz) LoveTrack(ctx context.Context, track domain.Track) error {
+	if err := l.submitFeedback(ctx, track, scoreLoved); err != nil {
+		return fmt.Errorf("failed to mark track as loved on ListenBrainz: %w", err)
+	}
+
+	return nil
+}
+
+// UnloveTrack removes a track found on the external service from the loved tracks.
+func (l *ListenBrainz) UnloveTrack(ctx context.Context, track domain.Track) error {
+	if err := l.submitFeedback(ctx, track, scoreNeutral); err != nil {
+		return fmt.Errorf("failed to unlove track on ListenBrainz: %w", err)
+	}
+
+	return nil
+}
+
+// Loved returns whether the tracks found on the external service are loved by the logged-in user.
+func (l *ListenBrainz) Loved(ctx context.Context, tracks []domain.Track) ([]bool, error) {
+	loved := make([]bool, len(tracks))
+
+	var mbids []string
+	for _, track := range tracks {
+		if mbid := recordingMBID(track); mbid != "" {
+			mbids = append(mbids, mbid)
+		}
+	}
+	if len(mbids) == 0 {
+		return loved, nil
+	}
+
+	username, err := l.GetUsername(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{"recording_mbids": {strings.Join(mbids, ",")}}
+
+	var result feedbackResponse
+	if err := l.request(ctx, http.MethodGet, "/1/feedback/user/"+url.PathEscape(username)+"/get-feedback-for-recordings", query, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to read ListenBrainz feedback: %w", err)
+	}
+
+	scores := map[string]int{}
+	for _, feedback := range result.Feedback {
+		scores[feedback.RecordingMBID] = feedback.Score
+	}
+
+	for index, track := range tracks {
+		loved[index] = scores[recordingMBID(track)] == scoreLoved
+	}
+
+	return loved, nil
+}
+
+// Close persists any state before quitting the application.
+func (l *ListenBrainz) Close() error {
+	return nil
+}
+
+func (l *ListenBrainz) validateToken(ctx context.Context, token string) (string, error) {
+	var result validateTokenResponse
+	if err := l.requestWithToken(ctx, token, http.MethodGet, "/1/validate-token", nil, nil, &result); err != nil {
+		return "", err
+	}
+
+	if !result.Valid {
+		return "", errors.New("invalid user token")
+	}
+
+	return result.Username, nil
+}
+
+func (l *ListenBrainz) submitFeedback(ctx context.Context, track domain.Track, score int) error {
+	mbid := recordingMBID(track)
+	if mbid == "" {
+		return fmt.Errorf("no MusicBrainz recording identifier for %s", track)
+	}
+
+	body := feedbackRequest{
+		RecordingMBID: mbid,
+		Score:         score,
+	}
+
+	return l.request(ctx, http.MethodPost, "/1/feedback/recording-feedback", nil, body, nil)
+}
+
+func recordingMBID(track domain.Track) string {
+	if track.MBID != "" {
+		return track.MBID
+	}
+	return track.ID
+}
@@ -0,0 +1,294 @@
+package listenbrainz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dietrichm/admirer/domain"
+	"github.com/dietrichm/admirer/infrastructure/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListenBrainz(t *testing.T) {
+	t.Run("authenticates using user token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		server := newServer(t, func(writer http.ResponseWriter, request *http.Request) {
+			assert.Equal(t, "/1/validate-token", request.URL.Path)
+			assert.Equal(t, "Token usertoken", request.Header.Get("Authorization"))
+			writer.Write([]byte(`{"code": 200, "message": "Token valid.", "valid": true, "user_name": "joe"}`))
+		})
+
+		secrets := config.NewMockConfig(ctrl)
+		secrets.EXPECT().Set("token", "usertoken")
+		secrets.EXPECT().Set("username", "joe")
+		secrets.EXPECT().Save()
+
+		service := newService(server, secrets)
+		err := service.Authenticate(context.Background(), "usertoken", "https://admirer.test")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("returns error for invalid user token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		server := newServer(t, func(writer http.ResponseWriter, request *http.Request) {
+			writer.Write([]byte(`{"code": 200, "message": "Token invalid.", "valid": false}`))
+		})
+
+		service := newService(server, config.NewMockConfig(ctrl))
+		err := service.Authenticate(context.Background(), "usertoken", "https://admirer.test")
+
+		assert.EqualError(t, err, "failed to authenticate on ListenBrainz: invalid user token")
+	})
+
+	t.Run("returns stored username", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		secrets := config.NewMockConfig(ctrl)
+		secrets.EXPECT().GetString("username").Return("joe")
+
+		service := &ListenBrainz{secrets: secrets}
+		got, err := service.GetUsername(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "joe", got)
+	})
+
+	t.Run("looks up and saves username of token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		server := newServer(t, func(writer http.ResponseWriter, request *http.Request) {
+			assert.Equal(t, "Token usertoken", request.Header.Get("Authorization"))
+			writer.Write([]byte(`{"code": 200, "message": "Token valid.", "valid": true, "user_name": "joe"}`))
+		})
+
+		secrets := config.NewMockConfig(ctrl)
+		secrets.EXPECT().GetString("username").Return("")
+		secrets.EXPECT().GetString("token").Return("usertoken")
+		secrets.EXPECT().Set("username", "joe")
+		secrets.EXPECT().Save()
+
+		service := newService(server, secrets)
+		got, err := service.GetUsername(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "joe", got)
+	})
+
+	t.Run("returns error when failing to save username", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		server := newServer(t, func(writer http.ResponseWriter, request *http.Request) {
+			writer.Write([]byte(`{"code": 200, "message": "Token valid.", "valid": true, "user_name": "joe"}`))
+		})
+
+		secrets := config.NewMockConfig(ctrl)
+		secrets.EXPECT().GetString("username").Return("")
+		secrets.EXPECT().GetString("token").Return("usertoken")
+		secrets.EXPECT().Set("username", "joe")
+		secrets.EXPECT().Save().Return(errors.New("keyring error"))
+
+		service := newService(server, secrets)
+		_, err := service.GetUsername(context.Background())
+
+		assert.EqualError(t, err, "failed to save ListenBrainz secrets: keyring error")
+	})
+
+	t.Run("returns loved tracks", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		server := newServer(t, func(writer http.ResponseWriter, request *http.Request) {
+			assert.Equal(t, "/1/feedback/user/joe/get-feedback", request.URL.Path)
+			assert.Equal(t, "count=2&metadata=true&offset=2&score=1", request.URL.RawQuery)
+			writer.Write([]byte(`{"count": 1, "offset": 2, "total_count": 3, "feedback": [{
+				"created": 1704164645,
+				"recording_mbid": "recordingMBID",
+				"recording_msid": "recordingMSID",
+				"score": 1,
+				"track_metadata": {
+					"artist_name": "Foo & Bar",
+					"track_name": "Mr. Testy",
+					"release_name": "Testing, Testing",
+					"mbid_mapping": {"artist_mbids": ["artistMBID"], "recording_mbid": "recordingMBID"}
+				}
+			}]}`))
+		})
+
+		service := newService(server, authenticatedSecrets(ctrl))
+		got, err := service.GetLovedTracksPage(context.Background(), 2, 2)
+
+		expected := []domain.Track{
+			{
+				ID:         "recordingMBID",
+				URI:        "https://musicbrainz.org/recording/recordingMBID",
+				Artist:     "Foo & Bar",
+				Artists:    []string{"Foo & Bar"},
+				Name:       "Mr. Testy",
+				Album:      "Testing, Testing",
+				MBID:       "recordingMBID",
+				ArtistMBID: "artistMBID",
+				LovedAt:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			},
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got.Tracks)
+		assert.Equal(t, 3, got.Total)
+	})
+
+	t.Run("returns API error when failing to read loved tracks", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		server := newServer(t, func(writer http.ResponseWriter, request *http.Request) {
+			writer.WriteHeader(http.StatusUnauthorized)
+			writer.Write([]byte(`{"code": 401, "error": "Invalid authorization token."}`))
+		})
+
+		service := newService(server, authenticatedSecrets(ctrl))
+		_, err := service.GetLovedTracks(context.Background(), 10, 1)
+
+		var apiError *APIError
+		assert.True(t, errors.As(err, &apiError))
+		assert.EqualError(t, err, "failed to read ListenBrainz loved tracks: ListenBrainz API error 401: Invalid authorization token.")
+	})
+
+	t.Run("finds track by MusicBrainz identifier without lookup", func(t *testing.T) {
+		service := &ListenBrainz{}
+
+		got, err := service.FindTrack(context.Background(), domain.Track{Artist: "Foo", Name: "Bar", MBID: "recordingMBID"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.MatchByMBID, got.Method)
+		assert.Equal(t, "recordingMBID", got.Track.ID)
+	})
+
+	t.Run("finds track by looking up recording", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		server := newServer(t, func(writer http.ResponseWriter, request *http.Request) {
+			assert.Equal(t, "/1/metadata/lookup/", request.URL.Path)
+			assert.Equal(t, "Foo & Bar", request.URL.Query().Get("artist_name"))
+			assert.Equal(t, "Mr. Testy", request.URL.Query().Get("recording_name"))
+			writer.Write([]byte(`{"artist_credit_name": "Foo & Bar", "artist_mbids": ["artistMBID"], "recording_mbid": "recordingMBID", "recording_name": "Mr. Testy", "release_name": "Testing"}`))
+		})
+
+		service := newService(server, authenticatedSecrets(ctrl))
+		got, err := service.FindTrack(context.Background(), domain.Track{Artist: "Foo & Bar", Name: "Mr. Testy"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.MatchBySearch, got.Method)
+		assert.Equal(t, "recordingMBID", got.Track.MBID)
+		assert.Equal(t, "artistMBID", got.Track.ArtistMBID)
+	})
+
+	t.Run("returns error when lookup does not find recording", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		server := newServer(t, func(writer http.ResponseWriter, request *http.Request) {
+			writer.Write([]byte(`{}`))
+		})
+
+		service := newService(server, authenticatedSecrets(ctrl))
+		_, err := service.FindTrack(context.Background(), domain.Track{Artist: "Foo", Name: "Bar"})
+
+		assert.ErrorIs(t, err, domain.ErrTrackNotFound)
+	})
+
+	t.Run("returns low confidence error for different recording", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		server := newServer(t, func(writer http.ResponseWriter, request *http.Request) {
+			writer.Write([]byte(`{"artist_credit_name": "Someone Else", "recording_mbid": "recordingMBID", "recording_name": "Another Song"}`))
+		})
+
+		service := newService(server, authenticatedSecrets(ctrl))
+		_, err := service.FindTrack(context.Background(), domain.Track{Artist: "Foo", Name: "Bar"})
+
+		var lowConfidence *domain.LowConfidenceError
+		assert.True(t, errors.As(err, &lowConfidence))
+	})
+
+	for _, test := range []struct {
+		name  string
+		score int
+		call  func(service *ListenBrainz, ctx context.Context, track domain.Track) error
+	}{
+		{"loves track", 1, (*ListenBrainz).LoveTrack},
+		{"unloves track", 0, (*ListenBrainz).UnloveTrack},
+	} {
+		t.Run(test.name+" with recording feedback", func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			server := newServer(t, func(writer http.ResponseWriter, request *http.Request) {
+				assert.Equal(t, http.MethodPost, request.Method)
+				assert.Equal(t, "/1/feedback/recording-feedback", request.URL.Path)
+				assert.Equal(t, "Token usertoken", request.Header.Get("Authorization"))
+
+				var body feedbackRequest
+				assert.NoError(t, json.NewDecoder(request.Body).Decode(&body))
+				assert.Equal(t, feedbackRequest{RecordingMBID: "recordingMBID", Score: test.score}, body)
+
+				writer.Write([]byte(`{"status": "ok"}`))
+			})
+
+			service := newService(server, authenticatedSecrets(ctrl))
+			err := test.call(service, context.Background(), domain.Track{ID: "recordingMBID"})
+
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("returns error when loving track without MusicBrainz identifier", func(t *testing.T) {
+		service := &ListenBrainz{}
+
+		err := service.LoveTrack(context.Background(), domain.Track{Artist: "Foo", Name: "Bar"})
+
+		assert.EqualError(t, err, "failed to mark track as loved on ListenBrainz: no MusicBrainz recording identifier for Foo - Bar")
+	})
+
+	t.Run("returns whether tracks are loved", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		server := newServer(t, func(writer http.ResponseWriter, request *http.Request) {
+			assert.Equal(t, "/1/feedback/user/joe/get-feedback-for-recordings", request.URL.Path)
+			assert.Equal(t, "first,second", request.URL.Query().Get("recording_mbids"))
+			writer.Write([]byte(`{"feedback": [{"recording_mbid": "first", "score": 1}, {"recording_mbid": "second", "score": -1}]}`))
+		})
+
+		service := newService(server, authenticatedSecrets(ctrl))
+		got, err := service.Loved(context.Background(), []domain.Track{{ID: "first"}, {MBID: "second"}, {Name: "No identifier"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{true, false, false}, got)
+	})
+}
+
+func newServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newService(server *httptest.Server, secrets config.Config) *ListenBrainz {
+	return &ListenBrainz{
+		apiURL:     server.URL,
+		httpClient: server.Client(),
+		secrets:    secrets,
+		matcher:    domain.NewMatcher(domain.DefaultThreshold),
+	}
+}
+
+func authenticatedSecrets(ctrl *gomock.Controller) config.Config {
+	secrets := config.NewMockConfig(ctrl)
+	secrets.EXPECT().GetString("token").Return("usertoken").AnyTimes()
+	secrets.EXPECT().GetString("username").Return("joe").AnyTimes()
+	return secrets
+}
@@ -8,23 +8,50 @@ import (
 
 	"github.com/dietrichm/admirer/domain"
 	"github.com/dietrichm/admirer/infrastructure/config"
+	"github.com/dietrichm/admirer/infrastructure/services/plugin"
 )
 
 type loaderMap map[string]func(secrets config.Config) (domain.Service, error)
 
+type pathLoaderMap map[string]func(path string) (domain.Service, error)
+
 type mapServiceLoader struct {
 	services     loaderMap
+	pathServices pathLoaderMap
+	plugins      func() map[string]string
 	configLoader config.Loader
 }
 
+var replaceRegex = regexp.MustCompile("[^a-zA-Z0-9]")
+
+// InternalName returns the name under which a service is loaded and its secrets are stored, such as "lastfm" for "Last.fm".
+func InternalName(serviceName string) string {
+	return strings.ToLower(replaceRegex.ReplaceAllString(serviceName, ""))
+}
+
 func (m mapServiceLoader) ForName(serviceName string) (service domain.Service, err error) {
-	replaceRegex := regexp.MustCompile("[^a-zA-Z0-9]")
-	internalServiceName := strings.ToLower(replaceRegex.ReplaceAllString(serviceName, ""))
+	if prefix, path, found := strings.Cut(serviceName, ":"); found {
+		loader, exists := m.pathServices[strings.ToLower(prefix)]
+		if !exists {
+			return nil, fmt.Errorf("unknown service %q", prefix)
+		}
+
+		return loader(path)
+	}
+
+	internalServiceName := InternalName(serviceName)
 
 	loader, exists := m.services[internalServiceName]
 
 	if !exists {
-		return nil, fmt.Errorf("unknown service %q", serviceName)
+		path, found := m.pluginPaths()[internalServiceName]
+		if !found {
+			return nil, fmt.Errorf("unknown service %q", serviceName)
+		}
+
+		loader = func(secrets config.Config) (domain.Service, error) {
+			return plugin.NewPlugin(internalServiceName, path, secrets)
+		}
 	}
 
 	secrets, err := m.configLoader.Load("secrets-" + internalServiceName)
@@ -35,10 +62,33 @@ func (m mapServiceLoader) ForName(serviceName string) (service domain.Service, e
 	return loader(secrets)
 }
 
+// Names returns the names of the services which are loaded without a path, including discovered plugins.
 func (m mapServiceLoader) Names() (names []string) {
 	for name := range m.services {
 		names = append(names, name)
 	}
+	for name := range m.pluginPaths() {
+		if _, exists := m.services[name]; !exists {
+			names = append(names, name)
+		}
+	}
 	sort.Strings(names)
 	return
 }
+
+// pluginPaths returns the paths of discovered plugins by their internal service name.
+func (m mapServiceLoader) pluginPaths() map[string]string {
+	paths := map[string]string{}
+	if m.plugins == nil {
+		return paths
+	}
+
+	for name, path := range m.plugins() {
+		if name = InternalName(name); name != "" {
+			if _, exists := paths[name]; !exists {
+				paths[name] = path
+			}
+		}
+	}
+	return paths
+}
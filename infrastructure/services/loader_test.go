@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 	"go.uber.org/mock/gomock"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -164,6 +165,46 @@ func TestMapServiceLoader(t *testing.T) {
 		}
 	})
 
+	t.Run("returns service for name with path", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		service := domain.NewMockService(ctrl)
+
+		serviceLoader := mapServiceLoader{
+			pathServices: pathLoaderMap{
+				"foo": func(path string) (domain.Service, error) {
+					if path != "some/file.csv" {
+						t.Errorf("expected %q, got %q", "some/file.csv", path)
+					}
+					return service, nil
+				},
+			},
+		}
+
+		got, err := serviceLoader.ForName("Foo:some/file.csv")
+
+		if got != service {
+			t.Errorf("expected %v, got %v", service, got)
+		}
+
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("returns error when path loader does not exist", func(t *testing.T) {
+		serviceLoader := mapServiceLoader{
+			pathServices: pathLoaderMap{},
+		}
+
+		_, err := serviceLoader.ForName("bar:file.csv")
+
+		expected := `unknown service "bar"`
+		if err == nil || err.Error() != expected {
+			t.Errorf("expected %q, got %v", expected, err)
+		}
+	})
+
 	t.Run("returns slice of names of available services", func(t *testing.T) {
 		serviceLoader := mapServiceLoader{
 			services: loaderMap{
@@ -183,4 +224,48 @@ func TestMapServiceLoader(t *testing.T) {
 			t.Errorf("expected %q, got %q", expected, got)
 		}
 	})
+
+	t.Run("includes discovered plugins in names", func(t *testing.T) {
+		serviceLoader := mapServiceLoader{
+			services: loaderMap{
+				"foo": func(secrets config.Config) (domain.Service, error) {
+					return nil, nil
+				},
+			},
+			plugins: func() map[string]string {
+				return map[string]string{"foo": "/bin/admirer-service-foo", "My-Service": "/bin/admirer-service-My-Service"}
+			},
+		}
+
+		expected := []string{"foo", "myservice"}
+		got := serviceLoader.Names()
+
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %q, got %q", expected, got)
+		}
+	})
+
+	t.Run("loads discovered plugin by its normalised name", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		secrets := config.NewMockConfig(ctrl)
+
+		configLoader := config.NewMockLoader(ctrl)
+		configLoader.EXPECT().Load("secrets-myservice").Return(secrets, nil)
+
+		path := filepath.Join(t.TempDir(), "admirer-service-my-service")
+		serviceLoader := mapServiceLoader{
+			plugins: func() map[string]string {
+				return map[string]string{"my-service": path}
+			},
+			configLoader: configLoader,
+		}
+
+		_, err := serviceLoader.ForName("My Service")
+
+		expected := "failed to start myservice plugin: fork/exec " + path + ": no such file or directory"
+		if err == nil || err.Error() != expected {
+			t.Errorf("expected %q, got %v", expected, err)
+		}
+	})
 }
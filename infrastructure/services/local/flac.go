@@ -0,0 +1,145 @@
+package local
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+// FLAC metadata block types.
+// See https://xiph.org/flac/format.html#metadata_block.
+const (
+	flacStreamInfo    = 0
+	flacVorbisComment = 4
+	flacLastBlock     = 0x80
+	flacMaxBlockSize  = 1<<24 - 1
+)
+
+var (
+	flacMarker      = []byte("fLaC")
+	errInvalidFLAC  = errors.New("invalid FLAC file")
+	errLargeComment = errors.New("Vorbis comments too large for FLAC metadata block")
+)
+
+type flacBlock struct {
+	kind byte
+	data []byte
+}
+
+func readFLAC(reader io.ReadSeeker) (metadata, error) {
+	marker := make([]byte, len(flacMarker))
+	if _, err := io.ReadFull(reader, marker); err != nil || !bytes.Equal(marker, flacMarker) {
+		return metadata{}, errInvalidFLAC
+	}
+
+	for {
+		header := make([]byte, 4)
+		if _, err := io.ReadFull(reader, header); err != nil {
+			return metadata{}, errInvalidFLAC
+		}
+
+		kind := header[0] &^ flacLastBlock
+		length := int64(header[1])<<16 | int64(header[2])<<8 | int64(header[3])
+
+		if kind == flacVorbisComment {
+			data := make([]byte, length)
+			if _, err := io.ReadFull(reader, data); err != nil {
+				return metadata{}, errInvalidFLAC
+			}
+
+			comments, err := parseVorbisComments(data)
+			if err != nil {
+				return metadata{}, err
+			}
+			return metadata{tags: comments.tags()}, nil
+		}
+
+		if header[0]&flacLastBlock != 0 {
+			return metadata{tags: map[string][]string{}}, nil
+		}
+
+		if _, err := reader.Seek(length, io.SeekCurrent); err != nil {
+			return metadata{}, errInvalidFLAC
+		}
+	}
+}
+
+func rateFLAC(data []byte, loved bool, lovedTag string) ([]byte, error) {
+	blocks, audio, err := flacBlocks(data)
+	if err != nil {
+		return nil, err
+	}
+
+	index := -1
+	for blockIndex, block := range blocks {
+		if block.kind == flacVorbisComment {
+			index = blockIndex
+		}
+	}
+
+	comments := &vorbisComments{vendor: "admirer"}
+	if index >= 0 {
+		if comments, err = parseVorbisComments(blocks[index].data); err != nil {
+			return nil, err
+		}
+	} else {
+		// The stream info block always comes first.
+		index = 1
+		blocks = append(blocks[:index], append([]flacBlock{{kind: flacVorbisComment}}, blocks[index:]...)...)
+	}
+
+	comments.rate(loved, lovedTag)
+	blocks[index].data = comments.bytes()
+	if len(blocks[index].data) > flacMaxBlockSize {
+		return nil, errLargeComment
+	}
+
+	buffer := new(bytes.Buffer)
+	buffer.Write(flacMarker)
+	for blockIndex, block := range blocks {
+		kind := block.kind
+		if blockIndex == len(blocks)-1 {
+			kind |= flacLastBlock
+		}
+		length := len(block.data)
+		buffer.Write([]byte{kind, byte(length >> 16), byte(length >> 8), byte(length)})
+		buffer.Write(block.data)
+	}
+	buffer.Write(audio)
+
+	return buffer.Bytes(), nil
+}
+
+// flacBlocks splits the file in its metadata blocks and the audio frames following them.
+func flacBlocks(data []byte) (blocks []flacBlock, audio []byte, err error) {
+	if !bytes.HasPrefix(data, flacMarker) {
+		return nil, nil, errInvalidFLAC
+	}
+
+	offset := len(flacMarker)
+	for {
+		if offset+4 > len(data) {
+			return nil, nil, errInvalidFLAC
+		}
+
+		header := data[offset : offset+4]
+		length := int(header[1])<<16 | int(header[2])<<8 | int(header[3])
+		start := offset + 4
+		if start+length > len(data) {
+			return nil, nil, errInvalidFLAC
+		}
+
+		blocks = append(blocks, flacBlock{kind: header[0] &^ flacLastBlock, data: data[start : start+length]})
+		offset = start + length
+
+		if header[0]&flacLastBlock != 0 {
+			break
+		}
+	}
+
+	if len(blocks) == 0 || blocks[0].kind != flacStreamInfo {
+		return nil, nil, errInvalidFLAC
+	}
+
+	return blocks, data[offset:], nil
+}
@@ -0,0 +1,361 @@
+package local
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ID3v2 header flags and text encodings.
+// See https://id3.org/id3v2.4.0-structure.
+const (
+	id3HeaderSize       = 10
+	id3Unsynchronised   = 0x80
+	id3ExtendedHeader   = 0x40
+	id3Footer           = 0x10
+	id3EncodingLatin1   = 0
+	id3EncodingUTF16    = 1
+	id3EncodingUTF16BE  = 2
+	id3EncodingUTF8     = 3
+	id3PopularimeterID  = "POPM"
+	id3UserTextID       = "TXXX"
+	id3MusicBrainzOwner = "http://musicbrainz.org"
+)
+
+var (
+	id3Marker          = []byte("ID3")
+	errUnsupportedID3  = errors.New("unsupported ID3v2 tag")
+	errInvalidID3      = errors.New("invalid ID3v2 tag")
+	id3TextFrameTags   = map[string]string{"TIT2": titleTag, "TPE1": artistTag, "TALB": albumTag, "TSRC": isrcTag}
+	id3UserTextTags    = map[string]string{"MUSICBRAINZ ARTIST ID": artistMBIDTag}
+	maxID3v24FrameSize = 1<<28 - 1
+)
+
+type id3Frame struct {
+	id    string
+	flags [2]byte
+	data  []byte
+}
+
+type id3Tag struct {
+	version byte
+	frames  []id3Frame
+}
+
+func readID3(reader io.ReadSeeker) (metadata, error) {
+	header := make([]byte, id3HeaderSize)
+	if _, err := io.ReadFull(reader, header); err != nil || !bytes.HasPrefix(header, id3Marker) {
+		return metadata{tags: map[string][]string{}}, nil
+	}
+
+	data := make([]byte, id3HeaderSize+syncsafe(header[6:10]))
+	copy(data, header)
+	if _, err := io.ReadFull(reader, data[id3HeaderSize:]); err != nil {
+		return metadata{}, errInvalidID3
+	}
+
+	tag, _, err := parseID3(data)
+	if err != nil {
+		return metadata{}, err
+	}
+
+	return tag.metadata(), nil
+}
+
+func rateID3(data []byte, loved bool, lovedTag string) ([]byte, error) {
+	tag, audio, err := parseID3(data)
+	if err != nil {
+		return nil, err
+	}
+
+	tag.rate(loved, lovedTag)
+
+	encoded, err := tag.bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(encoded, audio...), nil
+}
+
+// parseID3 splits the file in its ID3v2 tag and the audio following it.
+// Files without tag result in an empty ID3v2.3 tag.
+func parseID3(data []byte) (*id3Tag, []byte, error) {
+	if !bytes.HasPrefix(data, id3Marker) {
+		return &id3Tag{version: 3}, data, nil
+	}
+
+	if len(data) < id3HeaderSize {
+		return nil, nil, errInvalidID3
+	}
+
+	version := data[3]
+	flags := data[5]
+	if version < 3 || version > 4 || flags&(id3Unsynchronised|id3ExtendedHeader|id3Footer) != 0 {
+		return nil, nil, errUnsupportedID3
+	}
+
+	end := id3HeaderSize + syncsafe(data[6:10])
+	if end > len(data) {
+		return nil, nil, errInvalidID3
+	}
+
+	tag := &id3Tag{version: version}
+	body := data[id3HeaderSize:end]
+	for len(body) >= id3HeaderSize && body[0] != 0 {
+		size := int(binary.BigEndian.Uint32(body[4:8]))
+		if version == 4 {
+			size = syncsafe(body[4:8])
+		}
+		if id3HeaderSize+size > len(body) {
+			return nil, nil, errInvalidID3
+		}
+
+		tag.frames = append(tag.frames, id3Frame{
+			id:    string(body[0:4]),
+			flags: [2]byte{body[8], body[9]},
+			data:  body[id3HeaderSize : id3HeaderSize+size],
+		})
+		body = body[id3HeaderSize+size:]
+	}
+
+	return tag, data[end:], nil
+}
+
+func (t *id3Tag) metadata() metadata {
+	result := metadata{tags: map[string][]string{}}
+
+	for _, frame := range t.frames {
+		if frame.encoded() {
+			continue
+		}
+
+		switch {
+		case frame.id == id3PopularimeterID:
+			email, rating, ok := popularimeter(frame.data)
+			if ok && email == admirerRatingUser {
+				result.admirerPopularimeter = int(rating)
+			} else if ok && result.popularimeter == 0 {
+				result.popularimeter = int(rating)
+			}
+		case frame.id == id3UserTextID:
+			description, value := userText(frame.data)
+			key := strings.ToUpper(description)
+			if tag, exists := id3UserTextTags[key]; exists {
+				key = tag
+			}
+			result.tags[key] = append(result.tags[key], value)
+		case frame.id == "UFID":
+			if owner, identifier, found := bytes.Cut(frame.data, []byte{0}); found && string(owner) == id3MusicBrainzOwner {
+				result.tags[mbidTag] = append(result.tags[mbidTag], string(identifier))
+			}
+		default:
+			if tag, exists := id3TextFrameTags[frame.id]; exists && len(frame.data) > 0 {
+				values := strings.Split(decodeText(frame.data[0], frame.data[1:]), "\x00")
+				result.tags[tag] = append(result.tags[tag], values[0])
+			}
+		}
+	}
+
+	return result
+}
+
+// rate adds or removes the frame admirer writes on loved tracks: the user defined text frame used as loved tag when configured, or otherwise a POPM frame of admirer.
+// Other frames, such as ratings and play counters of other players, are kept as is.
+func (t *id3Tag) rate(loved bool, lovedTag string) {
+	frames := t.frames[:0]
+	for _, frame := range t.frames {
+		if !frame.encoded() && frame.writtenByAdmirer(lovedTag) {
+			continue
+		}
+		frames = append(frames, frame)
+	}
+	t.frames = frames
+
+	if !loved {
+		return
+	}
+
+	if lovedTag == "" {
+		popm := append([]byte(admirerRatingUser), 0, 255)
+		t.frames = append(t.frames, id3Frame{id: id3PopularimeterID, data: popm})
+		return
+	}
+
+	encoding := t.textEncoding(lovedTag)
+	data := append([]byte{encoding}, encodeText(encoding, lovedTag)...)
+	data = append(data, t.terminator(encoding)...)
+	data = append(data, encodeText(encoding, "1")...)
+	t.frames = append(t.frames, id3Frame{id: id3UserTextID, data: data})
+}
+
+// writtenByAdmirer returns whether the frame is the POPM frame of admirer or the user defined text frame used as loved tag.
+func (f id3Frame) writtenByAdmirer(lovedTag string) bool {
+	switch f.id {
+	case id3PopularimeterID:
+		email, _, ok := popularimeter(f.data)
+		return ok && email == admirerRatingUser
+	case id3UserTextID:
+		description, _ := userText(f.data)
+		return lovedTag != "" && strings.EqualFold(description, lovedTag)
+	}
+	return false
+}
+
+func (t *id3Tag) bytes() ([]byte, error) {
+	body := new(bytes.Buffer)
+	for _, frame := range t.frames {
+		size := len(frame.data)
+		header := make([]byte, id3HeaderSize)
+		copy(header, frame.id)
+		if t.version == 4 {
+			if size > maxID3v24FrameSize {
+				return nil, errInvalidID3
+			}
+			putSyncsafe(header[4:8], size)
+		} else {
+			binary.BigEndian.PutUint32(header[4:8], uint32(size))
+		}
+		header[8], header[9] = frame.flags[0], frame.flags[1]
+
+		body.Write(header)
+		body.Write(frame.data)
+	}
+
+	if body.Len() > maxID3v24FrameSize {
+		return nil, errInvalidID3
+	}
+
+	header := make([]byte, id3HeaderSize)
+	copy(header, id3Marker)
+	header[3] = t.version
+	putSyncsafe(header[6:10], body.Len())
+
+	return append(header, body.Bytes()...), nil
+}
+
+// encoded returns whether the frame is compressed or encrypted, in which case it is kept as is.
+func (f id3Frame) encoded() bool {
+	return f.flags[1]&0xef != 0
+}
+
+// textEncoding returns Latin-1 when possible, and otherwise the Unicode encoding supported by the tag version.
+func (t *id3Tag) textEncoding(text string) byte {
+	if isLatin1(text) {
+		return id3EncodingLatin1
+	}
+	if t.version == 4 {
+		return id3EncodingUTF8
+	}
+	return id3EncodingUTF16
+}
+
+func encodeText(encoding byte, text string) []byte {
+	switch encoding {
+	case id3EncodingUTF8:
+		return []byte(text)
+	case id3EncodingUTF16:
+		encoded := []byte{0xff, 0xfe}
+		for _, unit := range utf16.Encode([]rune(text)) {
+			encoded = append(encoded, byte(unit), byte(unit>>8))
+		}
+		return encoded
+	default:
+		encoded := make([]byte, 0, len(text))
+		for _, character := range text {
+			encoded = append(encoded, byte(character))
+		}
+		return encoded
+	}
+}
+
+func (t *id3Tag) terminator(encoding byte) []byte {
+	if encoding == id3EncodingUTF16 || encoding == id3EncodingUTF16BE {
+		return []byte{0, 0}
+	}
+	return []byte{0}
+}
+
+func isLatin1(text string) bool {
+	for _, character := range text {
+		if character > 0xff {
+			return false
+		}
+	}
+	return true
+}
+
+func decodeText(encoding byte, data []byte) string {
+	switch encoding {
+	case id3EncodingUTF16, id3EncodingUTF16BE:
+		bigEndian := encoding == id3EncodingUTF16BE
+		if len(data) >= 2 && data[0] == 0xfe && data[1] == 0xff {
+			bigEndian, data = true, data[2:]
+		} else if len(data) >= 2 && data[0] == 0xff && data[1] == 0xfe {
+			bigEndian, data = false, data[2:]
+		}
+
+		units := make([]uint16, 0, len(data)/2)
+		for index := 0; index+1 < len(data); index += 2 {
+			if bigEndian {
+				units = append(units, uint16(data[index])<<8|uint16(data[index+1]))
+			} else {
+				units = append(units, uint16(data[index+1])<<8|uint16(data[index]))
+			}
+		}
+		return strings.TrimRight(string(utf16.Decode(units)), "\x00")
+	case id3EncodingUTF8:
+		return strings.TrimRight(string(data), "\x00")
+	default:
+		runes := make([]rune, 0, len(data))
+		for _, character := range data {
+			runes = append(runes, rune(character))
+		}
+		return strings.TrimRight(string(runes), "\x00")
+	}
+}
+
+// userText splits a TXXX frame in its description and value.
+func userText(data []byte) (description string, value string) {
+	if len(data) == 0 {
+		return "", ""
+	}
+
+	encoding, text := data[0], data[1:]
+	terminator := []byte{0}
+	if encoding == id3EncodingUTF16 || encoding == id3EncodingUTF16BE {
+		terminator = []byte{0, 0}
+	}
+
+	for index := 0; index+len(terminator) <= len(text); index += len(terminator) {
+		if bytes.Equal(text[index:index+len(terminator)], terminator) {
+			return decodeText(encoding, text[:index]), decodeText(encoding, text[index+len(terminator):])
+		}
+	}
+
+	return decodeText(encoding, text), ""
+}
+
+// popularimeter reads the email and rating from a POPM frame.
+func popularimeter(data []byte) (email string, rating byte, ok bool) {
+	owner, rest, found := bytes.Cut(data, []byte{0})
+	if !found || len(rest) == 0 || !utf8.Valid(owner) {
+		return "", 0, false
+	}
+	return string(owner), rest[0], true
+}
+
+func syncsafe(data []byte) int {
+	return int(data[0]&0x7f)<<21 | int(data[1]&0x7f)<<14 | int(data[2]&0x7f)<<7 | int(data[3]&0x7f)
+}
+
+func putSyncsafe(data []byte, value int) {
+	data[0] = byte(value>>21) & 0x7f
+	data[1] = byte(value>>14) & 0x7f
+	data[2] = byte(value>>7) & 0x7f
+	data[3] = byte(value) & 0x7f
+}
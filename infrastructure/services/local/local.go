@@ -0,0 +1,410 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dietrichm/admirer/domain"
+)
+
+// defaultMinRating is the rating of five stars.
+const defaultMinRating = 1.0
+
+// Local is the service implementation reading loved tracks from the tags of audio files in a directory.
+// Tracks rated with at least LOCAL_MIN_RATING, or flagged by the tag named in LOCAL_LOVED_TAG, are loved.
+type Local struct {
+	directory string
+	lovedTag  string
+	minRating float64
+	matcher   domain.Matcher
+	files     []localFile
+	index     map[string][]int
+	scanned   bool
+	scanning  sync.Mutex
+}
+
+type localFile struct {
+	track domain.Track
+	loved bool
+}
+
+// NewLocal creates a Local instance for the music library in the given directory.
+func NewLocal(directory string) (*Local, error) {
+	if directory == "" {
+		return nil, errors.New("please set LOCAL_MUSIC_DIRECTORY environment variable or use local:<directory> as service name")
+	}
+
+	info, err := os.Stat(directory)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading music library: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("failed reading music library: %s is not a directory", directory)
+	}
+
+	minRating := defaultMinRating
+	if value := os.Getenv("LOCAL_MIN_RATING"); value != "" {
+		if minRating, err = strconv.ParseFloat(value, 64); err != nil || minRating <= 0 || minRating > 1 {
+			return nil, fmt.Errorf("invalid LOCAL_MIN_RATING %q, expected a rating above 0 and up to 1", value)
+		}
+	}
+
+	return &Local{
+		directory: directory,
+		lovedTag:  os.Getenv("LOCAL_LOVED_TAG"),
+		minRating: minRating,
+		matcher:   domain.NewMatcher(domain.DefaultThreshold),
+	}, nil
+}
+
+// Name returns the service name including the directory, so the ledger keeps libraries in different directories apart.
+func (l *Local) Name() string {
+	return "local:" + l.directory
+}
+
+// Authenticated returns whether the service is logged in.
+// Local libraries do not require authentication.
+func (l *Local) Authenticated() bool {
+	return true
+}
+
+// CreateAuthURL returns an authorization URL to authorize the integration.
+func (l *Local) CreateAuthURL(redirectURL string) string {
+	return ""
+}
+
+// CodeParam is the query parameter name used in the authentication callback.
+func (l *Local) CodeParam() string {
+	return ""
+}
+
+// Authenticate takes an authorization code and authenticates the user.
+func (l *Local) Authenticate(ctx context.Context, code string, redirectURL string) error {
+	return errors.New("local libraries do not require logging in")
+}
+
+// GetUsername returns the directory of the music library.
+func (l *Local) GetUsername(ctx context.Context) (string, error) {
+	return l.directory, nil
+}
+
+// GetLovedTracks returns loved tracks from the music library, ordered by the paths of their files.
+// Tags hold no date on which a file was rated, so the tracks have no time at which they were loved.
+func (l *Local) GetLovedTracks(ctx context.Context, limit int, page int) ([]domain.Track, error) {
+	result, err := l.GetLovedTracksPage(ctx, limit, page)
+	return result.Tracks, err
+}
+
+// GetLovedTracksPage returns rated tracks from the music library, along with the number of rated tracks.
+func (l *Local) GetLovedTracksPage(ctx context.Context, limit int, page int) (domain.TrackPage, error) {
+	if err := l.scan(ctx); err != nil {
+		return domain.TrackPage{}, err
+	}
+
+	var loved []domain.Track
+	for _, file := range l.files {
+		if file.loved {
+			loved = append(loved, file.track)
+		}
+	}
+
+	result := domain.TrackPage{Total: len(loved)}
+
+	start := (page - 1) * limit
+	if start >= len(loved) {
+		return result, nil
+	}
+
+	end := start + limit
+	if end > len(loved) {
+		end = len(loved)
+	}
+
+	result.Tracks = loved[start:end]
+	return result, nil
+}
+
+// SetMatcher sets the matcher used to select files when loving tracks.
+func (l *Local) SetMatcher(matcher domain.Matcher) {
+	l.matcher = matcher
+}
+
+// FindTrack looks up the file of the track by its identifiers or its artist and title tags.
+func (l *Local) FindTrack(ctx context.Context, track domain.Track) (domain.Match, error) {
+	if err := l.scan(ctx); err != nil {
+		return domain.Match{}, err
+	}
+
+	if positions := l.index[isrcKey(track.ISRC)]; track.ISRC != "" && len(positions) > 0 {
+		return domain.Match{Track: l.files[positions[0]].track, Confidence: 1, Method: domain.MatchByISRC}, nil
+	}
+	if positions := l.index[mbidKey(track.MBID)]; track.MBID != "" && len(positions) > 0 {
+		return domain.Match{Track: l.files[positions[0]].track, Confidence: 1, Method: domain.MatchByMBID}, nil
+	}
+
+	match, err := l.matcher.Best(track, l.candidates(track))
+	if errors.Is(err, domain.ErrTrackNotFound) {
+		return match, fmt.Errorf("%w in local library: %s", domain.ErrTrackNotFound, track)
+	}
+
+	return match, err
+}
+
+// LoveTrack rates the file of a track found in the music library as loved.
+func (l *Local) LoveTrack(ctx context.Context, track domain.Track) error {
+	if err := l.rate(ctx, track, true); err != nil {
+		return fmt.Errorf("failed to mark track as loved in local library: %w", err)
+	}
+
+	return nil
+}
+
+// UnloveTrack removes the rating from the file of a track found in the music library.
+func (l *Local) UnloveTrack(ctx context.Context, track domain.Track) error {
+	if err := l.rate(ctx, track, false); err != nil {
+		return fmt.Errorf("failed to unlove track in local library: %w", err)
+	}
+
+	return nil
+}
+
+// Loved returns whether the files of the tracks found in the music library are rated as loved.
+func (l *Local) Loved(ctx context.Context, tracks []domain.Track) ([]bool, error) {
+	if err := l.scan(ctx); err != nil {
+		return nil, err
+	}
+
+	loved := make([]bool, len(tracks))
+	for index, track := range tracks {
+		if file := l.file(track.ID); file != nil {
+			loved[index] = file.loved
+		}
+	}
+
+	return loved, nil
+}
+
+// Close persists any state before quitting the application.
+func (l *Local) Close() error {
+	return nil
+}
+
+func (l *Local) rate(ctx context.Context, track domain.Track, loved bool) error {
+	if err := l.scan(ctx); err != nil {
+		return err
+	}
+
+	file := l.file(track.ID)
+	if file == nil {
+		return fmt.Errorf("no file %q in %s", track.ID, l.directory)
+	}
+
+	filename := filepath.Join(l.directory, filepath.FromSlash(track.ID))
+	if err := writeRating(filename, loved, l.lovedTag); err != nil {
+		return err
+	}
+
+	tags, err := readTags(filename)
+	if err != nil {
+		return err
+	}
+
+	file.loved = l.loved(tags)
+	if !loved && file.loved {
+		return fmt.Errorf("%s is rated as loved by another player, keeping its rating", track.ID)
+	}
+
+	return nil
+}
+
+// loved returns whether the tags rate the file with at least the minimum rating, flag it with the loved tag, or hold the rating of admirer.
+func (l *Local) loved(tags metadata) bool {
+	rating, rated := tags.rating()
+	return rated && rating >= l.minRating || tags.flagged(l.lovedTag) || tags.ratedByAdmirer()
+}
+
+func (l *Local) file(id string) *localFile {
+	if positions := l.index[idKey(id)]; len(positions) > 0 {
+		return &l.files[positions[0]]
+	}
+	return nil
+}
+
+// candidates returns the tracks of the files sharing a normalised artist and title with the track.
+// When there are none, the tracks of the files sharing either the normalised artist or title are candidates, so the matcher can find tracks with slightly different tags.
+func (l *Local) candidates(track domain.Track) []domain.Track {
+	keys := nameKeys(track)
+	if candidates := l.tracks(keys); len(candidates) > 0 {
+		return candidates
+	}
+
+	var partialKeys []string
+	for _, key := range keys {
+		artist, title, _ := strings.Cut(key, "\x00")
+		partialKeys = append(partialKeys, artistKey(artist), titleKey(title))
+	}
+	return l.tracks(partialKeys)
+}
+
+// tracks returns the tracks of the files indexed by any of the keys, in the order of the files.
+func (l *Local) tracks(keys []string) (tracks []domain.Track) {
+	seen := map[int]bool{}
+	var positions []int
+	for _, key := range keys {
+		for _, position := range l.index[key] {
+			if !seen[position] {
+				seen[position] = true
+				positions = append(positions, position)
+			}
+		}
+	}
+
+	sort.Ints(positions)
+	for _, position := range positions {
+		tracks = append(tracks, l.files[position].track)
+	}
+	return
+}
+
+// indexFiles maps the ID, ISRC, MusicBrainz identifier, normalised artists and title, and combinations of both of the tracks to the positions of their files.
+func (l *Local) indexFiles() {
+	l.index = map[string][]int{}
+	for position, file := range l.files {
+		keys := []string{idKey(file.track.ID)}
+		for _, key := range nameKeys(file.track) {
+			artist, title, _ := strings.Cut(key, "\x00")
+			keys = append(keys, key, artistKey(artist), titleKey(title))
+		}
+		if file.track.ISRC != "" {
+			keys = append(keys, isrcKey(file.track.ISRC))
+		}
+		if file.track.MBID != "" {
+			keys = append(keys, mbidKey(file.track.MBID))
+		}
+		indexed := map[string]bool{}
+		for _, key := range keys {
+			if !indexed[key] {
+				indexed[key] = true
+				l.index[key] = append(l.index[key], position)
+			}
+		}
+	}
+}
+
+func idKey(id string) string {
+	return "id:" + id
+}
+
+func artistKey(artist string) string {
+	return "artist:" + artist
+}
+
+func titleKey(title string) string {
+	return "title:" + title
+}
+
+func isrcKey(isrc string) string {
+	return "isrc:" + strings.ToUpper(isrc)
+}
+
+func mbidKey(mbid string) string {
+	return "mbid:" + mbid
+}
+
+// nameKeys returns the keys of the track combining its normalised artists with its normalised title.
+func nameKeys(track domain.Track) (keys []string) {
+	for _, key := range domain.TrackKeys(track) {
+		if !strings.HasPrefix(key, "isrc:") {
+			keys = append(keys, key)
+		}
+	}
+	return
+}
+
+// scan reads the tags of all supported audio files in the directory once, until the context is cancelled.
+// Files which cannot be read or have no title are skipped.
+// Concurrent lookups wait for the first scan to finish.
+func (l *Local) scan(ctx context.Context) error {
+	l.scanning.Lock()
+	defer l.scanning.Unlock()
+
+	if l.scanned {
+		return nil
+	}
+
+	err := filepath.WalkDir(l.directory, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		if _, supported := formats[strings.ToLower(filepath.Ext(path))]; !supported {
+			return nil
+		}
+
+		tags, err := readTags(path)
+		if err != nil || tags.get(titleTag) == "" {
+			return nil
+		}
+
+		file, err := l.localFile(path, tags)
+		if err != nil {
+			return err
+		}
+
+		l.files = append(l.files, file)
+		return nil
+	})
+	if err != nil {
+		l.files = nil
+		return fmt.Errorf("failed reading music library: %w", err)
+	}
+
+	sort.SliceStable(l.files, func(i, j int) bool {
+		return l.files[i].track.ID < l.files[j].track.ID
+	})
+	l.indexFiles()
+
+	l.scanned = true
+	return nil
+}
+
+func (l *Local) localFile(path string, tags metadata) (localFile, error) {
+	relative, err := filepath.Rel(l.directory, path)
+	if err != nil {
+		return localFile{}, err
+	}
+
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		return localFile{}, err
+	}
+
+	track := domain.Track{
+		ID:         filepath.ToSlash(relative),
+		URI:        "file://" + filepath.ToSlash(absolute),
+		Artist:     tags.get(artistTag),
+		Name:       tags.get(titleTag),
+		Album:      tags.get(albumTag),
+		ISRC:       tags.get(isrcTag),
+		MBID:       tags.get(mbidTag),
+		ArtistMBID: tags.get(artistMBIDTag),
+	}
+	for _, artist := range tags.tags[artistTag] {
+		track.Artists = append(track.Artists, strings.TrimSpace(artist))
+	}
+
+	return localFile{track: track, loved: l.loved(tags)}, nil
+}
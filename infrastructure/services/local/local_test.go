@@ -0,0 +1,190 @@
+package local
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dietrichm/admirer/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocal(t *testing.T) {
+	newLibrary := func(t *testing.T) string {
+		directory := t.TempDir()
+		vorbis := oggCodecs[0]
+		popm := &id3Tag{version: 3, frames: []id3Frame{
+			{id: "TIT2", data: append([]byte{id3EncodingLatin1}, "Blam (Instrumental)"...)},
+			{id: "TPE1", data: append([]byte{id3EncodingLatin1}, "Awesome Artist"...)},
+			{id: "POPM", data: []byte("no@email\x00\xff")},
+		}}
+		mp3, _ := popm.bytes()
+
+		writeAudioFile(t, directory, "Foo/Mr. Testy.flac", flacFile("TITLE=Mr. Testy", "ARTIST=Foo", "ARTIST=Bar", "ISRC=USABC2000001", "FMPS_RATING=1"), 0)
+		writeAudioFile(t, directory, "Awesome Artist/Blam.mp3", mp3, time.Hour)
+		writeAudioFile(t, directory, "Other/Song.ogg", oggFile(vorbis, 10, "TITLE=Other Song", "ARTIST=Someone", "FMPS_RATING=0.6"), 0)
+		writeAudioFile(t, directory, "Other/cover.jpg", []byte("not audio"), 0)
+		writeAudioFile(t, directory, "Other/Broken.flac", []byte("not flac"), 0)
+		return directory
+	}
+
+	t.Run("returns rated tracks as loved tracks ordered by path, without loved time", func(t *testing.T) {
+		directory := newLibrary(t)
+
+		service, err := NewLocal(directory)
+		assert.NoError(t, err)
+
+		got, err := service.GetLovedTracks(context.Background(), 10, 1)
+
+		assert.NoError(t, err)
+		assert.Len(t, got, 2)
+		assert.Equal(t, "Awesome Artist/Blam.mp3", got[0].ID)
+		assert.Equal(t, "Awesome Artist - Blam (Instrumental)", got[0].String())
+		assert.Equal(t, "Foo/Mr. Testy.flac", got[1].ID)
+		assert.Equal(t, []string{"Foo", "Bar"}, got[1].Artists)
+		assert.Equal(t, "USABC2000001", got[1].ISRC)
+		assert.True(t, got[0].LovedAt.IsZero())
+	})
+
+	t.Run("returns name including directory", func(t *testing.T) {
+		directory := t.TempDir()
+
+		service, err := NewLocal(directory)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "local:"+directory, service.Name())
+	})
+
+	t.Run("uses configured minimum rating and loved tag", func(t *testing.T) {
+		directory := newLibrary(t)
+		writeAudioFile(t, directory, "Flagged.flac", flacFile("TITLE=Flagged", "ARTIST=Foo", "LOVED=yes"), 0)
+		t.Setenv("LOCAL_MIN_RATING", "0.6")
+		t.Setenv("LOCAL_LOVED_TAG", "loved")
+
+		service, err := NewLocal(directory)
+		assert.NoError(t, err)
+
+		got, err := service.GetLovedTracks(context.Background(), 10, 1)
+
+		assert.NoError(t, err)
+		assert.Len(t, got, 4)
+	})
+
+	t.Run("finds track by ISRC, MusicBrainz identifier or tags", func(t *testing.T) {
+		directory := newLibrary(t)
+		writeAudioFile(t, directory, "Identified.flac", flacFile("TITLE=Identified", "ARTIST=Foo", "MUSICBRAINZ_TRACKID=recordingMBID"), 0)
+		service, _ := NewLocal(directory)
+
+		got, err := service.FindTrack(context.Background(), domain.Track{Artist: "Foo", Name: "Other", ISRC: "usabc2000001"})
+		assert.NoError(t, err)
+		assert.Equal(t, domain.MatchByISRC, got.Method)
+		assert.Equal(t, "Foo/Mr. Testy.flac", got.Track.ID)
+
+		got, err = service.FindTrack(context.Background(), domain.Track{Artist: "Other", Name: "Track", MBID: "recordingMBID"})
+		assert.NoError(t, err)
+		assert.Equal(t, domain.MatchByMBID, got.Method)
+		assert.Equal(t, "Identified.flac", got.Track.ID)
+
+		got, err = service.FindTrack(context.Background(), domain.Track{Artist: "Bar", Name: "Mr Testy"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Foo/Mr. Testy.flac", got.Track.ID)
+
+		got, err = service.FindTrack(context.Background(), domain.Track{Artist: "Someone", Name: "Othr Song"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Other/Song.ogg", got.Track.ID)
+
+		_, err = service.FindTrack(context.Background(), domain.Track{Artist: "Nobody", Name: "Nothing"})
+		assert.ErrorIs(t, err, domain.ErrTrackNotFound)
+
+		got, err = service.FindTrack(context.Background(), domain.Track{Artist: "Someone", Name: "Other Song - Remastered"})
+		assert.NoError(t, err)
+		assert.Equal(t, domain.MatchBySearch, got.Method)
+		assert.Equal(t, "Other/Song.ogg", got.Track.ID)
+	})
+
+	t.Run("loves and unloves tracks by writing rating", func(t *testing.T) {
+		directory := newLibrary(t)
+		service, _ := NewLocal(directory)
+
+		match, err := service.FindTrack(context.Background(), domain.Track{Artist: "Someone", Name: "Other Song"})
+		assert.NoError(t, err)
+		assert.NoError(t, service.LoveTrack(context.Background(), match.Track))
+
+		got, err := service.Loved(context.Background(), []domain.Track{{ID: "Other/Song.ogg"}, {ID: "Unknown.mp3"}})
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{true, false}, got)
+
+		rescanned, _ := NewLocal(directory)
+		loved, err := rescanned.GetLovedTracks(context.Background(), 10, 1)
+		assert.NoError(t, err)
+		assert.Len(t, loved, 3)
+
+		assert.NoError(t, service.UnloveTrack(context.Background(), match.Track))
+
+		got, err = service.Loved(context.Background(), []domain.Track{{ID: "Other/Song.ogg"}})
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{false}, got)
+
+		tags, err := readTags(filepath.Join(directory, "Other", "Song.ogg"))
+		assert.NoError(t, err)
+		assert.Equal(t, "0.6", tags.get(fmpsRatingTag))
+	})
+
+	t.Run("keeps rating of other players when unloving track", func(t *testing.T) {
+		directory := newLibrary(t)
+		service, _ := NewLocal(directory)
+		filename := filepath.Join(directory, "Awesome Artist", "Blam.mp3")
+		original, _ := os.ReadFile(filename)
+
+		err := service.UnloveTrack(context.Background(), domain.Track{ID: "Awesome Artist/Blam.mp3"})
+
+		assert.EqualError(t, err, "failed to unlove track in local library: Awesome Artist/Blam.mp3 is rated as loved by another player, keeping its rating")
+
+		got, err := service.Loved(context.Background(), []domain.Track{{ID: "Awesome Artist/Blam.mp3"}})
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{true}, got)
+
+		data, _ := os.ReadFile(filename)
+		assert.Equal(t, original, data)
+	})
+
+	t.Run("returns error when loving unknown file", func(t *testing.T) {
+		directory := newLibrary(t)
+		service, _ := NewLocal(directory)
+
+		err := service.LoveTrack(context.Background(), domain.Track{ID: "Unknown.mp3"})
+
+		assert.EqualError(t, err, `failed to mark track as loved in local library: no file "Unknown.mp3" in `+directory)
+	})
+
+	t.Run("returns error without directory", func(t *testing.T) {
+		_, err := NewLocal("")
+
+		assert.EqualError(t, err, "please set LOCAL_MUSIC_DIRECTORY environment variable or use local:<directory> as service name")
+	})
+
+	t.Run("returns error for invalid minimum rating", func(t *testing.T) {
+		t.Setenv("LOCAL_MIN_RATING", "5")
+
+		_, err := NewLocal(t.TempDir())
+
+		assert.EqualError(t, err, `invalid LOCAL_MIN_RATING "5", expected a rating above 0 and up to 1`)
+	})
+}
+
+func writeAudioFile(t *testing.T, directory string, name string, data []byte, age time.Duration) {
+	filename := filepath.Join(directory, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	modified := time.Now().Add(-age)
+	if err := os.Chtimes(filename, modified, modified); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,340 @@
+package local
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// Ogg page layout and codec headers.
+// See https://xiph.org/ogg/doc/framing.html, https://xiph.org/vorbis/doc/Vorbis_I_spec.html and RFC 7845.
+const (
+	oggHeaderSize     = 27
+	oggContinued      = 0x01
+	oggMaxSegments    = 255
+	oggMaxLacingValue = 255
+	oggCRCOffset      = 22
+	oggNoGranule      = ^uint64(0)
+)
+
+var (
+	oggMarker         = []byte("OggS")
+	errInvalidOgg     = errors.New("invalid Ogg file")
+	errUnsupportedOgg = errors.New("unsupported Ogg stream, expected Vorbis or Opus")
+)
+
+// oggCodec describes the header packets of a codec stored in Ogg.
+type oggCodec struct {
+	identification []byte
+	comment        []byte
+	headers        int
+	framingBit     bool
+}
+
+var oggCodecs = []oggCodec{
+	{identification: []byte("\x01vorbis"), comment: []byte("\x03vorbis"), headers: 3, framingBit: true},
+	{identification: []byte("OpusHead"), comment: []byte("OpusTags"), headers: 2},
+}
+
+type oggPage struct {
+	headerType byte
+	granule    uint64
+	serial     uint32
+	sequence   uint32
+	segments   []byte
+	data       []byte
+}
+
+func readOgg(reader io.ReadSeeker) (metadata, error) {
+	stream := oggStream{reader: reader}
+
+	identification, err := stream.packet()
+	if err != nil {
+		return metadata{}, err
+	}
+
+	codec, err := codecFor(identification)
+	if err != nil {
+		return metadata{}, err
+	}
+
+	comment, err := stream.packet()
+	if err != nil {
+		return metadata{}, err
+	}
+
+	comments, err := codec.parseComments(comment)
+	if err != nil {
+		return metadata{}, err
+	}
+
+	return metadata{tags: comments.tags()}, nil
+}
+
+func rateOgg(data []byte, loved bool, lovedTag string) ([]byte, error) {
+	var pages []oggPage
+	reader := bytes.NewReader(data)
+	for reader.Len() > 0 {
+		page, err := readOggPage(reader)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, page)
+	}
+
+	if len(pages) == 0 {
+		return nil, errInvalidOgg
+	}
+
+	codec, packets, headerPages, err := headerPackets(pages)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := codec.parseComments(packets[1])
+	if err != nil {
+		return nil, err
+	}
+	comments.rate(loved, lovedTag)
+	packets[1] = codec.commentPacket(comments)
+
+	// The identification header is alone on the first page, which is kept as is.
+	first := pages[0]
+	if !bytes.Equal(first.data, packets[0]) {
+		return nil, errUnsupportedOgg
+	}
+
+	rewritten := append([]oggPage{first}, paginate(packets[1:], first.serial, first.sequence+1)...)
+	offset := uint32(len(rewritten) - headerPages)
+
+	buffer := new(bytes.Buffer)
+	for _, page := range rewritten {
+		buffer.Write(page.bytes())
+	}
+	for _, page := range pages[headerPages:] {
+		if page.serial == first.serial {
+			page.sequence += offset
+		}
+		buffer.Write(page.bytes())
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func codecFor(identification []byte) (oggCodec, error) {
+	for _, codec := range oggCodecs {
+		if bytes.HasPrefix(identification, codec.identification) {
+			return codec, nil
+		}
+	}
+	return oggCodec{}, errUnsupportedOgg
+}
+
+func (c oggCodec) parseComments(packet []byte) (*vorbisComments, error) {
+	if !bytes.HasPrefix(packet, c.comment) {
+		return nil, errInvalidOgg
+	}
+	return parseVorbisComments(packet[len(c.comment):])
+}
+
+func (c oggCodec) commentPacket(comments *vorbisComments) []byte {
+	packet := append(append([]byte{}, c.comment...), comments.bytes()...)
+	if c.framingBit {
+		packet = append(packet, 1)
+	}
+	return packet
+}
+
+// headerPackets returns the header packets of the stream and the number of pages they take up.
+// Header packets are followed by a page boundary, so these pages contain no audio.
+func headerPackets(pages []oggPage) (codec oggCodec, packets [][]byte, headerPages int, err error) {
+	if codec, err = codecFor(pages[0].data); err != nil {
+		return
+	}
+
+	var packet []byte
+	for index, page := range pages {
+		if page.serial != pages[0].serial {
+			return codec, nil, 0, errUnsupportedOgg
+		}
+
+		offset := 0
+		for _, lacing := range page.segments {
+			packet = append(packet, page.data[offset:offset+int(lacing)]...)
+			offset += int(lacing)
+
+			if lacing < oggMaxLacingValue {
+				packets = append(packets, packet)
+				packet = nil
+			}
+		}
+
+		if len(packets) >= codec.headers {
+			if len(packets) > codec.headers || packet != nil {
+				return codec, nil, 0, errUnsupportedOgg
+			}
+			return codec, packets, index + 1, nil
+		}
+	}
+
+	return codec, nil, 0, errInvalidOgg
+}
+
+// paginate lays out the packets on new pages, starting with the given sequence number.
+func paginate(packets [][]byte, serial uint32, sequence uint32) (pages []oggPage) {
+	page := oggPage{serial: serial, sequence: sequence}
+
+	flush := func() {
+		page.granule = oggNoGranule
+		for _, lacing := range page.segments {
+			if lacing < oggMaxLacingValue {
+				page.granule = 0
+			}
+		}
+		pages = append(pages, page)
+
+		next := oggPage{serial: serial, sequence: page.sequence + 1}
+		if page.segments[len(page.segments)-1] == oggMaxLacingValue {
+			next.headerType = oggContinued
+		}
+		page = next
+	}
+
+	for _, packet := range packets {
+		offset := 0
+		for _, lacing := range lacingValues(len(packet)) {
+			if len(page.segments) == oggMaxSegments {
+				flush()
+			}
+			page.segments = append(page.segments, lacing)
+			page.data = append(page.data, packet[offset:offset+int(lacing)]...)
+			offset += int(lacing)
+		}
+	}
+
+	if len(page.segments) > 0 {
+		flush()
+	}
+
+	return pages
+}
+
+func lacingValues(length int) (values []byte) {
+	for ; length >= oggMaxLacingValue; length -= oggMaxLacingValue {
+		values = append(values, oggMaxLacingValue)
+	}
+	return append(values, byte(length))
+}
+
+func readOggPage(reader io.Reader) (oggPage, error) {
+	header := make([]byte, oggHeaderSize)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return oggPage{}, errInvalidOgg
+	}
+
+	if !bytes.HasPrefix(header, oggMarker) || header[4] != 0 {
+		return oggPage{}, errInvalidOgg
+	}
+
+	page := oggPage{
+		headerType: header[5],
+		granule:    binary.LittleEndian.Uint64(header[6:14]),
+		serial:     binary.LittleEndian.Uint32(header[14:18]),
+		sequence:   binary.LittleEndian.Uint32(header[18:22]),
+		segments:   make([]byte, header[26]),
+	}
+
+	if _, err := io.ReadFull(reader, page.segments); err != nil {
+		return oggPage{}, errInvalidOgg
+	}
+
+	length := 0
+	for _, lacing := range page.segments {
+		length += int(lacing)
+	}
+
+	page.data = make([]byte, length)
+	if _, err := io.ReadFull(reader, page.data); err != nil {
+		return oggPage{}, errInvalidOgg
+	}
+
+	return page, nil
+}
+
+func (p oggPage) bytes() []byte {
+	header := make([]byte, oggHeaderSize)
+	copy(header, oggMarker)
+	header[5] = p.headerType
+	binary.LittleEndian.PutUint64(header[6:14], p.granule)
+	binary.LittleEndian.PutUint32(header[14:18], p.serial)
+	binary.LittleEndian.PutUint32(header[18:22], p.sequence)
+	header[26] = byte(len(p.segments))
+
+	page := append(append(header, p.segments...), p.data...)
+	binary.LittleEndian.PutUint32(page[oggCRCOffset:], oggCRC(page))
+	return page
+}
+
+// oggStream reads the packets of the first logical stream in an Ogg file.
+type oggStream struct {
+	reader  io.Reader
+	serial  uint32
+	started bool
+	pending [][]byte
+	partial []byte
+}
+
+func (s *oggStream) packet() ([]byte, error) {
+	for len(s.pending) == 0 {
+		page, err := readOggPage(s.reader)
+		if err != nil {
+			return nil, err
+		}
+
+		if !s.started {
+			s.serial, s.started = page.serial, true
+		}
+		if page.serial != s.serial {
+			continue
+		}
+
+		offset := 0
+		for _, lacing := range page.segments {
+			s.partial = append(s.partial, page.data[offset:offset+int(lacing)]...)
+			offset += int(lacing)
+
+			if lacing < oggMaxLacingValue {
+				s.pending = append(s.pending, s.partial)
+				s.partial = nil
+			}
+		}
+	}
+
+	packet := s.pending[0]
+	s.pending = s.pending[1:]
+	return packet, nil
+}
+
+var oggCRCTable = func() (table [256]uint32) {
+	for index := range table {
+		remainder := uint32(index) << 24
+		for bit := 0; bit < 8; bit++ {
+			if remainder&0x80000000 != 0 {
+				remainder = remainder<<1 ^ 0x04c11db7
+			} else {
+				remainder <<= 1
+			}
+		}
+		table[index] = remainder
+	}
+	return
+}()
+
+// oggCRC computes the page checksum, for a page with its checksum field set to zero.
+func oggCRC(data []byte) (crc uint32) {
+	for _, value := range data {
+		crc = crc<<8 ^ oggCRCTable[byte(crc>>24)^value]
+	}
+	return
+}
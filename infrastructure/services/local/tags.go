@@ -0,0 +1,178 @@
+package local
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	fmpsRatingTag     = "FMPS_RATING"
+	fmpsRatingUserTag = "FMPS_RATING_USER"
+	titleTag          = "TITLE"
+	artistTag         = "ARTIST"
+	albumTag          = "ALBUM"
+	isrcTag           = "ISRC"
+	mbidTag           = "MUSICBRAINZ_TRACKID"
+	artistMBIDTag     = "MUSICBRAINZ_ARTISTID"
+
+	// admirerRatingUser owns the ratings written when loving tracks without loved tag, so other ratings are left alone.
+	admirerRatingUser = "admirer"
+)
+
+// metadata holds the tags read from an audio file, keyed by their upper case Vorbis comment names.
+type metadata struct {
+	tags map[string][]string
+	// popularimeter is the ID3 POPM rating from 1 to 255, or 0 when the file is not rated.
+	popularimeter int
+	// admirerPopularimeter is the rating of the ID3 POPM frame written by admirer, or 0 when there is none.
+	admirerPopularimeter int
+}
+
+func (m metadata) get(key string) string {
+	if values := m.tags[key]; len(values) > 0 {
+		return strings.TrimSpace(values[0])
+	}
+	return ""
+}
+
+// rating returns the rating between 0 and 1, preferring FMPS_Rating over POPM.
+func (m metadata) rating() (float64, bool) {
+	if rating, err := strconv.ParseFloat(m.get(fmpsRatingTag), 64); err == nil {
+		return rating, true
+	}
+	if m.popularimeter > 0 {
+		return stars(m.popularimeter), true
+	}
+	return 0, false
+}
+
+// ratedByAdmirer returns whether the file holds the rating admirer writes when loving a track without loved tag.
+func (m metadata) ratedByAdmirer() bool {
+	if m.admirerPopularimeter > 0 {
+		return true
+	}
+	for _, value := range m.tags[fmpsRatingUserTag] {
+		if ratedBy(value, admirerRatingUser) {
+			return true
+		}
+	}
+	return false
+}
+
+// ratedBy returns whether the FMPS_RATING_USER value holds the rating of the user.
+func ratedBy(value string, user string) bool {
+	owner, _, found := strings.Cut(value, "::")
+	return found && owner == user
+}
+
+// stars converts a POPM rating to the rating of the corresponding number of stars, as commonly used by players.
+func stars(popularimeter int) float64 {
+	switch {
+	case popularimeter >= 255:
+		return 1
+	case popularimeter >= 196:
+		return 0.8
+	case popularimeter >= 128:
+		return 0.6
+	case popularimeter >= 64:
+		return 0.4
+	default:
+		return 0.2
+	}
+}
+
+// flagged returns whether the tag holds a value marking the track as loved.
+func (m metadata) flagged(tag string) bool {
+	if tag == "" {
+		return false
+	}
+
+	switch strings.ToLower(m.get(strings.ToUpper(tag))) {
+	case "", "0", "false", "no":
+		return false
+	}
+	return true
+}
+
+// format reads tags from and writes ratings to a type of audio file.
+type format struct {
+	read func(reader io.ReadSeeker) (metadata, error)
+	rate func(data []byte, loved bool, lovedTag string) ([]byte, error)
+}
+
+var formats = map[string]format{
+	".flac": {readFLAC, rateFLAC},
+	".mp3":  {readID3, rateID3},
+	".ogg":  {readOgg, rateOgg},
+	".oga":  {readOgg, rateOgg},
+	".opus": {readOgg, rateOgg},
+}
+
+func readTags(filename string) (metadata, error) {
+	format, supported := formats[strings.ToLower(filepath.Ext(filename))]
+	if !supported {
+		return metadata{}, fmt.Errorf("unsupported audio file %s", filename)
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return metadata{}, err
+	}
+	defer file.Close()
+
+	return format.read(file)
+}
+
+// writeRating rewrites the file with a rating marking the track as loved or not.
+func writeRating(filename string, loved bool, lovedTag string) error {
+	format, supported := formats[strings.ToLower(filepath.Ext(filename))]
+	if !supported {
+		return fmt.Errorf("unsupported audio file %s", filename)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	data, err = format.rate(data, loved, lovedTag)
+	if err != nil {
+		return fmt.Errorf("failed writing tags of %s: %w", filename, err)
+	}
+
+	return replaceFile(filename, data)
+}
+
+// replaceFile writes the file through a temporary file, keeping its permissions.
+func replaceFile(filename string, data []byte) error {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if err := file.Chmod(info.Mode().Perm()); err != nil {
+		file.Close()
+		return err
+	}
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), filename)
+}
@@ -0,0 +1,247 @@
+package local
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTags(t *testing.T) {
+	t.Run("reads and rates FLAC file", func(t *testing.T) {
+		data := flacFile("TITLE=Mr. Testy", "ARTIST=Foo", "FMPS_RATING=0.4")
+
+		got, err := readFLAC(bytes.NewReader(data))
+		assert.NoError(t, err)
+		assert.Equal(t, "Mr. Testy", got.get(titleTag))
+		rating, _ := got.rating()
+		assert.Equal(t, 0.4, rating)
+
+		data, err = rateFLAC(data, true, "LOVED")
+		assert.NoError(t, err)
+		assert.True(t, bytes.HasSuffix(data, []byte("audio frames")))
+
+		got, err = readFLAC(bytes.NewReader(data))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"0.4"}, got.tags[fmpsRatingTag])
+		assert.True(t, got.flagged("loved"))
+		assert.False(t, got.ratedByAdmirer())
+		assert.Equal(t, "Mr. Testy", got.get(titleTag))
+	})
+
+	t.Run("rates Vorbis comments keeping ratings of other players", func(t *testing.T) {
+		comments := &vorbisComments{vendor: "test", fields: []string{"TITLE=Mr. Testy", "FMPS_RATING=0.4", "FMPS_RATING_USER=alice::0.6", "LOVED=yes"}}
+
+		comments.rate(true, "")
+		assert.Equal(t, []string{"TITLE=Mr. Testy", "FMPS_RATING=0.4", "FMPS_RATING_USER=alice::0.6", "LOVED=yes", "FMPS_RATING_USER=admirer::1.0"}, comments.fields)
+		assert.True(t, metadata{tags: comments.tags()}.ratedByAdmirer())
+
+		comments.rate(false, "")
+		assert.Equal(t, []string{"TITLE=Mr. Testy", "FMPS_RATING=0.4", "FMPS_RATING_USER=alice::0.6", "LOVED=yes"}, comments.fields)
+		assert.False(t, metadata{tags: comments.tags()}.ratedByAdmirer())
+	})
+
+	t.Run("adds comment block to FLAC file without comments", func(t *testing.T) {
+		data, err := rateFLAC(flacFile(), true, "")
+		assert.NoError(t, err)
+
+		blocks, audio, err := flacBlocks(data)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{flacStreamInfo, flacVorbisComment}, []byte{blocks[0].kind, blocks[1].kind})
+		assert.Equal(t, []byte("audio frames"), audio)
+	})
+
+	t.Run("reads ID3v2 frames in all text encodings", func(t *testing.T) {
+		tag := &id3Tag{version: 3, frames: []id3Frame{
+			{id: "TIT2", data: append([]byte{id3EncodingUTF16, 0xff, 0xfe}, 'M', 0, 'r', 0, '.', 0, ' ', 0, 0x7f, 0x00, 0, 0)},
+			{id: "TPE1", data: append([]byte{id3EncodingLatin1}, "Fo\xf6"...)},
+			{id: "TALB", data: append([]byte{id3EncodingUTF8}, "Tést\x00"...)},
+			{id: "TXXX", data: append([]byte{id3EncodingLatin1}, "MusicBrainz Artist Id\x00artistMBID"...)},
+			{id: "UFID", data: []byte("http://musicbrainz.org\x00recordingMBID")},
+			{id: "POPM", data: []byte("someone@example.com\x00\xc4\x00\x00\x00\x01")},
+		}}
+		encoded, err := tag.bytes()
+		assert.NoError(t, err)
+
+		got, err := readID3(bytes.NewReader(append(encoded, "audio frames"...)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Mr. \u007f", got.get(titleTag))
+		assert.Equal(t, "Foö", got.get(artistTag))
+		assert.Equal(t, "Tést", got.get(albumTag))
+		assert.Equal(t, "artistMBID", got.get(artistMBIDTag))
+		assert.Equal(t, "recordingMBID", got.get(mbidTag))
+		assert.Equal(t, 196, got.popularimeter)
+	})
+
+	t.Run("rates ID3v2 tag with loved tag keeping ratings and play counters of other players", func(t *testing.T) {
+		frames := []id3Frame{
+			{id: "TIT2", data: append([]byte{id3EncodingUTF8}, "Mr. Testy"...)},
+			{id: "POPM", data: []byte("someone@example.com\x00\x40\x00\x00\x01\x2c")},
+			{id: "POPM", data: []byte("Windows Media Player 9 Series\x00\xc4")},
+			{id: "PCNT", data: []byte{0, 0, 0, 42}},
+			{id: "TXXX", data: append([]byte{id3EncodingLatin1}, "FMPS_Rating\x000.4"...)},
+		}
+		tag := &id3Tag{version: 4, frames: frames}
+		encoded, _ := tag.bytes()
+
+		data, err := rateID3(append(encoded, "audio frames"...), true, "Loved")
+		assert.NoError(t, err)
+		assert.True(t, bytes.HasSuffix(data, []byte("audio frames")))
+
+		rated, _, err := parseID3(data)
+		assert.NoError(t, err)
+		assert.Equal(t, append(append([]id3Frame{}, frames...), id3Frame{id: "TXXX", data: append([]byte{id3EncodingLatin1}, "Loved\x001"...)}), rated.frames)
+
+		data, err = rateID3(data, false, "Loved")
+		assert.NoError(t, err)
+		assert.Equal(t, append(encoded, "audio frames"...), data)
+
+		got, err := readID3(bytes.NewReader(data))
+		assert.NoError(t, err)
+		assert.False(t, got.flagged("Loved"))
+		assert.Equal(t, 64, got.popularimeter)
+	})
+
+	t.Run("rates ID3v2 tag with POPM frame of admirer keeping other POPM frames", func(t *testing.T) {
+		frames := []id3Frame{
+			{id: "POPM", data: []byte("someone@example.com\x00\xff\x00\x00\x00\x07")},
+			{id: "POPM", data: []byte("other@example.com\x00\x01")},
+			{id: "PCNT", data: []byte{0, 0, 0, 42}},
+		}
+		tag := &id3Tag{version: 3, frames: frames}
+		encoded, _ := tag.bytes()
+
+		data, err := rateID3(append(encoded, "audio frames"...), true, "")
+		assert.NoError(t, err)
+
+		rated, _, err := parseID3(data)
+		assert.NoError(t, err)
+		assert.Equal(t, append(append([]id3Frame{}, frames...), id3Frame{id: "POPM", data: []byte("admirer\x00\xff")}), rated.frames)
+
+		got, err := readID3(bytes.NewReader(data))
+		assert.NoError(t, err)
+		assert.True(t, got.ratedByAdmirer())
+		assert.Equal(t, 255, got.popularimeter)
+
+		data, err = rateID3(data, false, "")
+		assert.NoError(t, err)
+		assert.Equal(t, append(encoded, "audio frames"...), data)
+
+		got, err = readID3(bytes.NewReader(data))
+		assert.NoError(t, err)
+		assert.False(t, got.ratedByAdmirer())
+		assert.Equal(t, 255, got.popularimeter)
+	})
+
+	t.Run("adds ID3v2 tag to MP3 file without tag", func(t *testing.T) {
+		data, err := rateID3([]byte("audio frames"), true, "")
+		assert.NoError(t, err)
+
+		got, err := readID3(bytes.NewReader(data))
+		assert.NoError(t, err)
+		assert.True(t, got.ratedByAdmirer())
+		assert.Zero(t, got.popularimeter)
+	})
+
+	t.Run("returns error for unsynchronised ID3v2 tag", func(t *testing.T) {
+		_, err := rateID3([]byte("ID3\x03\x00\x80\x00\x00\x00\x00audio"), true, "")
+
+		assert.ErrorIs(t, err, errUnsupportedID3)
+	})
+
+	for _, codec := range oggCodecs {
+		t.Run("reads and rates Ogg file with "+string(codec.comment), func(t *testing.T) {
+			data := oggFile(codec, 300, "TITLE=Mr. Testy", "ARTIST=Foo")
+
+			got, err := readOgg(bytes.NewReader(data))
+			assert.NoError(t, err)
+			assert.Equal(t, "Mr. Testy", got.get(titleTag))
+
+			comment := "COMMENT=" + string(bytes.Repeat([]byte("x"), 70000))
+			data, err = rateOgg(oggFile(codec, 300, "TITLE=Mr. Testy", comment), true, "")
+			assert.NoError(t, err)
+
+			got, err = readOgg(bytes.NewReader(data))
+			assert.NoError(t, err)
+			assert.Equal(t, "Mr. Testy", got.get(titleTag))
+			assert.True(t, got.ratedByAdmirer())
+
+			pages := oggPages(t, data)
+			last := pages[len(pages)-1]
+			assert.Equal(t, []byte("audio packet"), last.data)
+			assert.Equal(t, uint64(4800), last.granule)
+			for index, page := range pages {
+				assert.Equal(t, uint32(index), page.sequence)
+			}
+			assert.Equal(t, byte(oggContinued), pages[2].headerType)
+		})
+	}
+
+	t.Run("returns error for unsupported Ogg codec", func(t *testing.T) {
+		codec := oggCodec{identification: []byte("\x80theora"), comment: []byte("\x81theora"), headers: 3}
+
+		_, err := readOgg(bytes.NewReader(oggFile(codec, 10)))
+
+		assert.ErrorIs(t, err, errUnsupportedOgg)
+	})
+}
+
+func flacFile(comments ...string) []byte {
+	blocks := []flacBlock{{kind: flacStreamInfo, data: make([]byte, 34)}}
+	if len(comments) > 0 {
+		blocks = append(blocks, flacBlock{kind: flacVorbisComment, data: (&vorbisComments{vendor: "test", fields: comments}).bytes()})
+	}
+
+	buffer := new(bytes.Buffer)
+	buffer.Write(flacMarker)
+	for index, block := range blocks {
+		kind := block.kind
+		if index == len(blocks)-1 {
+			kind |= flacLastBlock
+		}
+		buffer.Write([]byte{kind, 0, byte(len(block.data) >> 8), byte(len(block.data))})
+		buffer.Write(block.data)
+	}
+	buffer.WriteString("audio frames")
+	return buffer.Bytes()
+}
+
+func oggFile(codec oggCodec, setupSize int, comments ...string) []byte {
+	buffer := new(bytes.Buffer)
+	identification := append(append([]byte{}, codec.identification...), make([]byte, 22)...)
+	buffer.Write(oggPage{headerType: 0x02, serial: 1234, segments: lacingValues(len(identification)), data: identification}.bytes())
+
+	packets := [][]byte{codec.commentPacket(&vorbisComments{vendor: "test", fields: comments})}
+	if codec.headers == 3 {
+		packets = append(packets, append([]byte("\x05vorbis"), make([]byte, setupSize)...))
+	}
+
+	pages := paginate(packets, 1234, 1)
+	for _, page := range pages {
+		buffer.Write(page.bytes())
+	}
+
+	audio := []byte("audio packet")
+	buffer.Write(oggPage{headerType: 0x04, granule: 4800, serial: 1234, sequence: uint32(len(pages) + 1), segments: lacingValues(len(audio)), data: audio}.bytes())
+	return buffer.Bytes()
+}
+
+// oggPages reads all pages, verifying their checksums.
+func oggPages(t *testing.T, data []byte) (pages []oggPage) {
+	reader := bytes.NewReader(data)
+	for reader.Len() > 0 {
+		offset := len(data) - reader.Len()
+		page, err := readOggPage(reader)
+		assert.NoError(t, err)
+
+		raw := append([]byte{}, data[offset:len(data)-reader.Len()]...)
+		checksum := binary.LittleEndian.Uint32(raw[oggCRCOffset:])
+		binary.LittleEndian.PutUint32(raw[oggCRCOffset:], 0)
+		assert.Equal(t, oggCRC(raw), checksum)
+
+		pages = append(pages, page)
+	}
+	return
+}
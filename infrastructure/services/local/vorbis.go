@@ -0,0 +1,106 @@
+package local
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+)
+
+var errInvalidComments = errors.New("invalid Vorbis comments")
+
+// vorbisComments are the KEY=value tags used by FLAC and Ogg files.
+type vorbisComments struct {
+	vendor string
+	fields []string
+}
+
+func parseVorbisComments(data []byte) (*vorbisComments, error) {
+	reader := bytes.NewReader(data)
+
+	vendor, err := readVorbisString(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var count uint32
+	if err := binary.Read(reader, binary.LittleEndian, &count); err != nil {
+		return nil, errInvalidComments
+	}
+
+	comments := &vorbisComments{vendor: vendor}
+	for index := uint32(0); index < count; index++ {
+		field, err := readVorbisString(reader)
+		if err != nil {
+			return nil, err
+		}
+		comments.fields = append(comments.fields, field)
+	}
+
+	return comments, nil
+}
+
+func readVorbisString(reader *bytes.Reader) (string, error) {
+	var length uint32
+	if err := binary.Read(reader, binary.LittleEndian, &length); err != nil {
+		return "", errInvalidComments
+	}
+	if int64(length) > int64(reader.Len()) {
+		return "", errInvalidComments
+	}
+
+	value := make([]byte, length)
+	reader.Read(value)
+	return string(value), nil
+}
+
+func (v *vorbisComments) bytes() []byte {
+	buffer := new(bytes.Buffer)
+	writeVorbisString(buffer, v.vendor)
+	binary.Write(buffer, binary.LittleEndian, uint32(len(v.fields)))
+	for _, field := range v.fields {
+		writeVorbisString(buffer, field)
+	}
+	return buffer.Bytes()
+}
+
+func writeVorbisString(buffer *bytes.Buffer, value string) {
+	binary.Write(buffer, binary.LittleEndian, uint32(len(value)))
+	buffer.WriteString(value)
+}
+
+// tags returns the comments keyed by their upper case field name.
+func (v *vorbisComments) tags() map[string][]string {
+	tags := map[string][]string{}
+	for _, field := range v.fields {
+		if key, value, found := strings.Cut(field, "="); found {
+			key = strings.ToUpper(key)
+			tags[key] = append(tags[key], value)
+		}
+	}
+	return tags
+}
+
+// rate adds or removes the field admirer writes on loved tracks: the loved tag when configured, or otherwise an FMPS_RATING_USER field of admirer.
+// Fields written by others, such as FMPS_RATING, are kept as is.
+func (v *vorbisComments) rate(loved bool, lovedTag string) {
+	fields := v.fields[:0]
+	for _, field := range v.fields {
+		key, value, _ := strings.Cut(field, "=")
+		if lovedTag != "" && strings.EqualFold(key, lovedTag) || strings.EqualFold(key, fmpsRatingUserTag) && ratedBy(value, admirerRatingUser) {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	v.fields = fields
+
+	if !loved {
+		return
+	}
+
+	if lovedTag != "" {
+		v.fields = append(v.fields, lovedTag+"=1")
+	} else {
+		v.fields = append(v.fields, fmpsRatingUserTag+"="+admirerRatingUser+"::1.0")
+	}
+}
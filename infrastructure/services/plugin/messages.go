@@ -0,0 +1,39 @@
+package plugin
+
+import (
+	"github.com/dietrichm/admirer/domain"
+	"github.com/dietrichm/admirer/infrastructure/services/file"
+)
+
+// trackMessage is a track as exchanged with plugins, using the fields of the file service.
+type trackMessage file.Record
+
+type matchMessage struct {
+	Track      trackMessage `json:"track"`
+	Confidence float64      `json:"confidence"`
+	Method     string       `json:"method"`
+}
+
+func messageFromTrack(track domain.Track) trackMessage {
+	return trackMessage(file.RecordFromTrack(track))
+}
+
+func (m trackMessage) track() domain.Track {
+	return file.Record(m).Track()
+}
+
+// match converts the message to a match, which defaults to full confidence found by search.
+func (m matchMessage) match() domain.Match {
+	match := domain.Match{
+		Track:      m.Track.track(),
+		Confidence: m.Confidence,
+		Method:     domain.MatchMethod(m.Method),
+	}
+	if match.Confidence == 0 {
+		match.Confidence = 1
+	}
+	if match.Method == "" {
+		match.Method = domain.MatchBySearch
+	}
+	return match
+}
@@ -0,0 +1,406 @@
+// Package plugin runs external service providers as child processes.
+//
+// A plugin is an executable named admirer-service-<name> on the PATH. It reads requests from stdin and writes
+// responses to stdout, both encoded as one JSON object per line. Each request holds an id, the name of a
+// domain.Service method and its params; the response repeats the id and holds either a result or an error.
+// The first request is Init, passing the stored secrets, and the last one is Close, after which stdin is closed.
+// A response may hold secrets, which replace the stored secrets and are saved in the keyring.
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dietrichm/admirer/domain"
+	"github.com/dietrichm/admirer/infrastructure/config"
+)
+
+// Prefix is the prefix of plugin executable names.
+const Prefix = "admirer-service-"
+
+// secretsKey is the key under which the secrets of a plugin are stored as a JSON object.
+const secretsKey = "secrets"
+
+// codeTrackNotFound is the error code plugins return when a track cannot be matched.
+const codeTrackNotFound = "track_not_found"
+
+// defaultStopTimeout is how long a plugin gets to handle Close and exit, before its process is killed.
+const defaultStopTimeout = 5 * time.Second
+
+// Plugin is the service implementation forwarding calls to a plugin process.
+type Plugin struct {
+	name        string
+	codeParam   string
+	secrets     config.Config
+	cmd         *exec.Cmd
+	stdin       io.WriteCloser
+	stderr      io.Writer
+	encoder     *json.Encoder
+	decoder     *json.Decoder
+	sequence    int
+	closed      bool
+	calling     sync.Mutex
+	stopTimeout time.Duration
+}
+
+// Error is an error returned by a plugin.
+type Error struct {
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap makes errors with the track_not_found code match domain.ErrTrackNotFound.
+func (e *Error) Unwrap() error {
+	if e.Code == codeTrackNotFound {
+		return domain.ErrTrackNotFound
+	}
+	return nil
+}
+
+type request struct {
+	ID     int         `json:"id"`
+	Method string      `json:"method"`
+	Params interface{} `json:"params,omitempty"`
+}
+
+type response struct {
+	ID      int               `json:"id"`
+	Result  json.RawMessage   `json:"result,omitempty"`
+	Error   *Error            `json:"error,omitempty"`
+	Secrets map[string]string `json:"secrets,omitempty"`
+}
+
+type initResult struct {
+	Name      string `json:"name"`
+	CodeParam string `json:"code_param"`
+}
+
+type pageParams struct {
+	Limit int `json:"limit"`
+	Page  int `json:"page"`
+}
+
+type authenticateParams struct {
+	Code        string `json:"code"`
+	RedirectURL string `json:"redirect_url"`
+}
+
+type redirectParams struct {
+	RedirectURL string `json:"redirect_url"`
+}
+
+type trackParams struct {
+	Track trackMessage `json:"track"`
+}
+
+// Discover returns the paths of plugin executables on the PATH by their name.
+// When several directories contain the same plugin, the first one is used.
+func Discover() map[string]string {
+	plugins := map[string]string{}
+
+	for _, directory := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(directory)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			name := entry.Name()
+			if runtime.GOOS == "windows" {
+				name = strings.TrimSuffix(name, ".exe")
+			}
+			if !strings.HasPrefix(name, Prefix) || len(name) == len(Prefix) {
+				continue
+			}
+
+			name = strings.TrimPrefix(name, Prefix)
+			if _, exists := plugins[name]; exists || !executable(entry) {
+				continue
+			}
+
+			plugins[name] = filepath.Join(directory, entry.Name())
+		}
+	}
+
+	return plugins
+}
+
+func executable(entry os.DirEntry) bool {
+	info, err := entry.Info()
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	return runtime.GOOS == "windows" || info.Mode().Perm()&0111 != 0
+}
+
+// NewPlugin starts the plugin executable at the given path and passes it the stored secrets.
+func NewPlugin(name string, path string, secrets config.Config) (*Plugin, error) {
+	return start(exec.Command(path), name, secrets)
+}
+
+func start(cmd *exec.Cmd, name string, secrets config.Config) (*Plugin, error) {
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start %s plugin: %w", name, err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start %s plugin: %w", name, err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s plugin: %w", name, err)
+	}
+
+	plugin := &Plugin{
+		name:        name,
+		secrets:     secrets,
+		cmd:         cmd,
+		stdin:       stdin,
+		stderr:      cmd.Stderr,
+		encoder:     json.NewEncoder(stdin),
+		decoder:     json.NewDecoder(stdout),
+		stopTimeout: defaultStopTimeout,
+	}
+
+	stored := map[string]string{}
+	if value := secrets.GetString(secretsKey); value != "" {
+		if err := json.Unmarshal([]byte(value), &stored); err != nil {
+			plugin.stop()
+			return nil, fmt.Errorf("failed to read secrets of %s plugin: %w", name, err)
+		}
+	}
+
+	var result initResult
+	if err := plugin.call(context.Background(), "Init", map[string]interface{}{"secrets": stored}, &result); err != nil {
+		plugin.stop()
+		return nil, err
+	}
+
+	if result.Name != "" {
+		plugin.name = result.Name
+	}
+	plugin.codeParam = result.CodeParam
+
+	return plugin, nil
+}
+
+// Name returns the human-readable service name.
+func (p *Plugin) Name() string {
+	return p.name
+}
+
+// Authenticated returns whether the service is logged in.
+// As the error of a failing plugin cannot be returned, it is written to stderr.
+func (p *Plugin) Authenticated() bool {
+	var authenticated bool
+	if err := p.call(context.Background(), "Authenticated", nil, &authenticated); err != nil {
+		fmt.Fprintf(p.stderr, "failed to check whether %s plugin is logged in: %v\n", p.name, err)
+		return false
+	}
+	return authenticated
+}
+
+// CreateAuthURL returns an authorization URL to authorize the integration.
+// As the error of a failing plugin cannot be returned, it is written to stderr.
+func (p *Plugin) CreateAuthURL(redirectURL string) string {
+	var authURL string
+	if err := p.call(context.Background(), "CreateAuthURL", redirectParams{RedirectURL: redirectURL}, &authURL); err != nil {
+		fmt.Fprintf(p.stderr, "failed to create authentication URL of %s plugin: %v\n", p.name, err)
+		return ""
+	}
+	return authURL
+}
+
+// CodeParam is the query parameter name used in the authentication callback.
+func (p *Plugin) CodeParam() string {
+	return p.codeParam
+}
+
+// Authenticate takes an authorization code and authenticates the user.
+func (p *Plugin) Authenticate(ctx context.Context, code string, redirectURL string) error {
+	return p.call(ctx, "Authenticate", authenticateParams{Code: code, RedirectURL: redirectURL}, nil)
+}
+
+// GetUsername retrieves the username of the authenticated user.
+func (p *Plugin) GetUsername(ctx context.Context) (username string, err error) {
+	err = p.call(ctx, "GetUsername", nil, &username)
+	return
+}
+
+// GetLovedTracks returns loved tracks from the service.
+func (p *Plugin) GetLovedTracks(ctx context.Context, limit int, page int) ([]domain.Track, error) {
+	var messages []trackMessage
+	if err := p.call(ctx, "GetLovedTracks", pageParams{Limit: limit, Page: page}, &messages); err != nil {
+		return nil, err
+	}
+
+	tracks := make([]domain.Track, 0, len(messages))
+	for _, message := range messages {
+		tracks = append(tracks, message.track())
+	}
+	return tracks, nil
+}
+
+// FindTrack looks up the track on the service.
+func (p *Plugin) FindTrack(ctx context.Context, track domain.Track) (domain.Match, error) {
+	var message matchMessage
+	if err := p.call(ctx, "FindTrack", trackParams{Track: messageFromTrack(track)}, &message); err != nil {
+		return domain.Match{}, err
+	}
+
+	return message.match(), nil
+}
+
+// LoveTrack marks a track found on the service as loved.
+func (p *Plugin) LoveTrack(ctx context.Context, track domain.Track) error {
+	return p.call(ctx, "LoveTrack", trackParams{Track: messageFromTrack(track)}, nil)
+}
+
+// UnloveTrack removes a track found on the service from the loved tracks.
+func (p *Plugin) UnloveTrack(ctx context.Context, track domain.Track) error {
+	return p.call(ctx, "UnloveTrack", trackParams{Track: messageFromTrack(track)}, nil)
+}
+
+// Close persists any state before quitting the application, and stops the plugin process.
+// A plugin which does not handle Close or exit in time is killed.
+func (p *Plugin) Close() error {
+	if p.closed {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.stopTimeout)
+	defer cancel()
+
+	err := p.call(ctx, "Close", nil, nil)
+	if p.closed {
+		return err
+	}
+
+	if stopErr := p.stop(); err == nil && stopErr != nil {
+		err = fmt.Errorf("failed to stop %s plugin: %w", p.name, stopErr)
+	}
+
+	return err
+}
+
+// call sends a request to the plugin and decodes the result of its response.
+// The plugin handles one request at a time, so the response answers the last request.
+// A cancelled context stops new requests. When it is cancelled while awaiting a response, the plugin process is killed,
+// as later responses would be out of order. Concurrent calls wait for the request in progress.
+func (p *Plugin) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	p.calling.Lock()
+	defer p.calling.Unlock()
+
+	if p.closed {
+		return fmt.Errorf("failed to call %s on %s plugin: plugin was stopped", method, p.name)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("failed to call %s on %s plugin: %w", method, p.name, err)
+	}
+
+	p.sequence++
+	if err := p.encoder.Encode(request{ID: p.sequence, Method: method, Params: params}); err != nil {
+		return fmt.Errorf("failed to call %s on %s plugin: %w", method, p.name, err)
+	}
+
+	var message response
+	decoded := make(chan error, 1)
+	go func() {
+		decoded <- p.decoder.Decode(&message)
+	}()
+
+	select {
+	case err := <-decoded:
+		if err != nil {
+			return fmt.Errorf("failed to read %s response from %s plugin: %w", method, p.name, err)
+		}
+	case <-ctx.Done():
+		p.kill()
+		return fmt.Errorf("failed to read %s response from %s plugin: %w", method, p.name, ctx.Err())
+	}
+
+	if message.ID != p.sequence {
+		return fmt.Errorf("failed to read %s response from %s plugin: expected id %d, got %d", method, p.name, p.sequence, message.ID)
+	}
+
+	if message.Secrets != nil {
+		if err := p.saveSecrets(message.Secrets); err != nil {
+			return err
+		}
+	}
+
+	if message.Error != nil {
+		return message.Error
+	}
+
+	if result != nil && len(message.Result) > 0 {
+		if err := json.Unmarshal(message.Result, result); err != nil {
+			return fmt.Errorf("failed to read %s response from %s plugin: %w", method, p.name, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *Plugin) saveSecrets(secrets map[string]string) error {
+	encoded, err := json.Marshal(secrets)
+	if err != nil {
+		return fmt.Errorf("failed to save secrets of %s plugin: %w", p.name, err)
+	}
+
+	p.secrets.Set(secretsKey, string(encoded))
+	if err := p.secrets.Save(); err != nil {
+		return fmt.Errorf("failed to save secrets of %s plugin: %w", p.name, err)
+	}
+
+	return nil
+}
+
+// stop closes stdin of the plugin process and waits for it to exit, killing it when it does not exit in time.
+func (p *Plugin) stop() error {
+	p.closed = true
+	if err := p.stdin.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+		return err
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- p.cmd.Wait()
+	}()
+
+	select {
+	case err := <-exited:
+		return err
+	case <-time.After(p.stopTimeout):
+		p.cmd.Process.Kill()
+		<-exited
+		return fmt.Errorf("plugin did not exit within %s", p.stopTimeout)
+	}
+}
+
+// kill stops a plugin process which does not respond.
+func (p *Plugin) kill() {
+	p.closed = true
+	p.cmd.Process.Kill()
+	p.stdin.Close()
+	p.cmd.Wait()
+}
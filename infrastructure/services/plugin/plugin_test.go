@@ -0,0 +1,284 @@
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dietrichm/admirer/domain"
+	"github.com/dietrichm/admirer/infrastructure/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPlugin(t *testing.T) {
+	t.Run("returns name and authentication state from plugin", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		secrets := config.NewMockConfig(ctrl)
+		secrets.EXPECT().GetString("secrets").Return(`{"token":"secret"}`)
+
+		service := startHelper(t, secrets)
+
+		assert.Equal(t, "Fake service", service.Name())
+		assert.Equal(t, "code", service.CodeParam())
+		assert.True(t, service.Authenticated())
+		assert.Equal(t, "https://fake.test/auth?redirect=http://127.0.0.1/callback", service.CreateAuthURL("http://127.0.0.1/callback"))
+		assert.NoError(t, service.Close())
+	})
+
+	t.Run("saves secrets returned by plugin", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		secrets := config.NewMockConfig(ctrl)
+		secrets.EXPECT().GetString("secrets").Return("")
+		secrets.EXPECT().Set("secrets", `{"token":"foo"}`)
+		secrets.EXPECT().Save()
+
+		service := startHelper(t, secrets)
+		assert.False(t, service.Authenticated())
+
+		err := service.Authenticate(context.Background(), "foo", "http://127.0.0.1/callback")
+
+		assert.NoError(t, err)
+		assert.True(t, service.Authenticated())
+		assert.NoError(t, service.Close())
+	})
+
+	t.Run("exchanges tracks with plugin", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		secrets := config.NewMockConfig(ctrl)
+		secrets.EXPECT().GetString("secrets").Return("")
+
+		service := startHelper(t, secrets)
+
+		tracks, err := service.GetLovedTracks(context.Background(), 10, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Track{{
+			ID:       "10-2",
+			Artist:   "Foo",
+			Artists:  []string{"Foo"},
+			Name:     "Bar",
+			Duration: 3 * time.Minute,
+			LovedAt:  time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		}}, tracks)
+
+		match, err := service.FindTrack(context.Background(), domain.Track{Artist: "Foo", Name: "Bar"})
+		assert.NoError(t, err)
+		assert.Equal(t, domain.Match{Track: domain.Track{ID: "found", Artist: "Foo", Name: "Bar"}, Confidence: 1, Method: domain.MatchBySearch}, match)
+
+		_, err = service.FindTrack(context.Background(), domain.Track{Artist: "Foo", Name: "Unknown"})
+		assert.ErrorIs(t, err, domain.ErrTrackNotFound)
+		assert.EqualError(t, err, "no track Foo - Unknown")
+
+		assert.NoError(t, service.LoveTrack(context.Background(), match.Track))
+		assert.EqualError(t, service.UnloveTrack(context.Background(), match.Track), "unsupported method UnloveTrack")
+		assert.NoError(t, service.Close())
+	})
+
+	t.Run("returns error when plugin exits", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		secrets := config.NewMockConfig(ctrl)
+		secrets.EXPECT().GetString("secrets").Return(`{"exit":"1"}`)
+
+		service := startHelper(t, secrets)
+
+		_, err := service.GetUsername(context.Background())
+
+		assert.EqualError(t, err, "failed to read GetUsername response from Fake service plugin: EOF")
+		assert.Error(t, service.Close())
+	})
+
+	t.Run("does not send requests when context is cancelled", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		secrets := config.NewMockConfig(ctrl)
+		secrets.EXPECT().GetString("secrets").Return("")
+
+		service := startHelper(t, secrets)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := service.GetLovedTracks(ctx, 10, 1)
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.NoError(t, service.Close())
+	})
+
+	t.Run("writes error of failing plugin to stderr", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		secrets := config.NewMockConfig(ctrl)
+		secrets.EXPECT().GetString("secrets").Return(`{"fail":"1"}`)
+
+		service := startHelper(t, secrets)
+		stderr := new(bytes.Buffer)
+		service.stderr = stderr
+
+		assert.False(t, service.Authenticated())
+		assert.Empty(t, service.CreateAuthURL("http://127.0.0.1/callback"))
+		assert.Equal(t, "failed to check whether Fake service plugin is logged in: plugin failure\nfailed to create authentication URL of Fake service plugin: plugin failure\n", stderr.String())
+		assert.NoError(t, service.Close())
+	})
+
+	t.Run("kills plugin when context is cancelled while awaiting response", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		secrets := config.NewMockConfig(ctrl)
+		secrets.EXPECT().GetString("secrets").Return(`{"hang":"GetUsername"}`)
+
+		service := startHelper(t, secrets)
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_, err := service.GetUsername(ctx)
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.NoError(t, service.Close())
+
+		_, err = service.GetUsername(context.Background())
+		assert.EqualError(t, err, "failed to call GetUsername on Fake service plugin: plugin was stopped")
+	})
+
+	t.Run("kills plugin which does not handle close in time", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		secrets := config.NewMockConfig(ctrl)
+		secrets.EXPECT().GetString("secrets").Return(`{"hang":"Close"}`)
+
+		service := startHelper(t, secrets)
+		service.stopTimeout = 100 * time.Millisecond
+
+		err := service.Close()
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.True(t, service.closed)
+	})
+
+	t.Run("returns error for invalid stored secrets", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		secrets := config.NewMockConfig(ctrl)
+		secrets.EXPECT().GetString("secrets").Return("invalid")
+
+		_, err := start(helperCommand(), "fake", secrets)
+
+		assert.EqualError(t, err, "failed to read secrets of fake plugin: invalid character 'i' looking for beginning of value")
+	})
+}
+
+func TestDiscover(t *testing.T) {
+	first := t.TempDir()
+	second := t.TempDir()
+	writeExecutable(t, filepath.Join(first, "admirer-service-foo"), 0755)
+	writeExecutable(t, filepath.Join(second, "admirer-service-foo"), 0755)
+	writeExecutable(t, filepath.Join(second, "admirer-service-bar"), 0755)
+	writeExecutable(t, filepath.Join(second, "admirer-service-baz"), 0644)
+	writeExecutable(t, filepath.Join(second, "admirer-service-"), 0755)
+	writeExecutable(t, filepath.Join(second, "other"), 0755)
+	t.Setenv("PATH", first+string(os.PathListSeparator)+filepath.Join(first, "missing")+string(os.PathListSeparator)+second)
+
+	got := Discover()
+
+	assert.Equal(t, map[string]string{
+		"foo": filepath.Join(first, "admirer-service-foo"),
+		"bar": filepath.Join(second, "admirer-service-bar"),
+	}, got)
+}
+
+func writeExecutable(t *testing.T, path string, mode os.FileMode) {
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), mode); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func startHelper(t *testing.T, secrets config.Config) *Plugin {
+	service, err := start(helperCommand(), "fake", secrets)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return service
+}
+
+// helperCommand runs TestHelperPlugin in a new test process, which acts as a plugin.
+func helperCommand() *exec.Cmd {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHelperPlugin$")
+	cmd.Env = append(os.Environ(), "ADMIRER_HELPER_PLUGIN=1")
+	return cmd
+}
+
+func TestHelperPlugin(t *testing.T) {
+	if os.Getenv("ADMIRER_HELPER_PLUGIN") != "1" {
+		return
+	}
+
+	var secrets map[string]string
+	scanner := bufio.NewScanner(os.Stdin)
+	encoder := json.NewEncoder(os.Stdout)
+
+	for scanner.Scan() {
+		var message struct {
+			ID     int             `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &message); err != nil {
+			os.Exit(2)
+		}
+
+		if secrets["hang"] == message.Method {
+			time.Sleep(time.Hour)
+		}
+
+		reply := map[string]interface{}{"id": message.ID}
+		switch message.Method {
+		case "Init":
+			var params struct{ Secrets map[string]string }
+			_ = json.Unmarshal(message.Params, &params)
+			secrets = params.Secrets
+			reply["result"] = initResult{Name: "Fake service", CodeParam: "code"}
+		case "Authenticated", "CreateAuthURL":
+			if secrets["fail"] != "" {
+				reply["error"] = Error{Message: "plugin failure"}
+				break
+			}
+			if message.Method == "Authenticated" {
+				reply["result"] = secrets["token"] != ""
+				break
+			}
+			var params redirectParams
+			_ = json.Unmarshal(message.Params, &params)
+			reply["result"] = "https://fake.test/auth?redirect=" + params.RedirectURL
+		case "Authenticate":
+			var params authenticateParams
+			_ = json.Unmarshal(message.Params, &params)
+			secrets = map[string]string{"token": params.Code}
+			reply["secrets"] = secrets
+		case "GetUsername":
+			if secrets["exit"] != "" {
+				os.Exit(1)
+			}
+		case "GetLovedTracks":
+			var params pageParams
+			_ = json.Unmarshal(message.Params, &params)
+			reply["result"] = json.RawMessage(`[{"id":"` + strconv.Itoa(params.Limit) + "-" + strconv.Itoa(params.Page) + `","artists":["Foo"],"name":"Bar","duration_ms":180000,"loved_at":"2020-01-02T03:04:05Z"}]`)
+		case "FindTrack":
+			var params trackParams
+			_ = json.Unmarshal(message.Params, &params)
+			if params.Track.Name == "Unknown" {
+				reply["error"] = Error{Code: codeTrackNotFound, Message: "no track " + params.Track.track().String()}
+			} else {
+				reply["result"] = matchMessage{Track: trackMessage{ID: "found", Artist: params.Track.Artist, Name: params.Track.Name}}
+			}
+		case "LoveTrack", "Close":
+		default:
+			reply["error"] = Error{Message: "unsupported method " + message.Method}
+		}
+
+		if err := encoder.Encode(reply); err != nil {
+			os.Exit(2)
+		}
+	}
+
+	os.Exit(0)
+}
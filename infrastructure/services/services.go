@@ -1,10 +1,18 @@
 package services
 
 import (
+	"os"
+
 	"github.com/dietrichm/admirer/domain"
 	"github.com/dietrichm/admirer/infrastructure/config"
+	"github.com/dietrichm/admirer/infrastructure/services/deezer"
+	"github.com/dietrichm/admirer/infrastructure/services/file"
 	"github.com/dietrichm/admirer/infrastructure/services/lastfm"
+	"github.com/dietrichm/admirer/infrastructure/services/listenbrainz"
+	"github.com/dietrichm/admirer/infrastructure/services/local"
+	"github.com/dietrichm/admirer/infrastructure/services/plugin"
 	"github.com/dietrichm/admirer/infrastructure/services/spotify"
+	"github.com/dietrichm/admirer/infrastructure/services/subsonic"
 )
 
 // AvailableServices is the configured ServiceLoader for the available services.
@@ -16,6 +24,27 @@ var AvailableServices = mapServiceLoader{
 		"lastfm": func(secrets config.Config) (domain.Service, error) {
 			return lastfm.NewLastfm(secrets)
 		},
+		"deezer": func(secrets config.Config) (domain.Service, error) {
+			return deezer.NewDeezer(secrets)
+		},
+		"listenbrainz": func(secrets config.Config) (domain.Service, error) {
+			return listenbrainz.NewListenBrainz(secrets)
+		},
+		"local": func(secrets config.Config) (domain.Service, error) {
+			return local.NewLocal(os.Getenv("LOCAL_MUSIC_DIRECTORY"))
+		},
+		"subsonic": func(secrets config.Config) (domain.Service, error) {
+			return subsonic.NewSubsonic(secrets)
+		},
+	},
+	pathServices: pathLoaderMap{
+		"file": func(path string) (domain.Service, error) {
+			return file.NewFile(path)
+		},
+		"local": func(path string) (domain.Service, error) {
+			return local.NewLocal(path)
+		},
 	},
+	plugins:      plugin.Discover,
 	configLoader: config.SecretsLoader,
 }
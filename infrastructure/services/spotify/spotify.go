@@ -15,6 +15,7 @@ import (
 
 	"github.com/dietrichm/admirer/domain"
 	"github.com/dietrichm/admirer/infrastructure/config"
+	"github.com/dietrichm/admirer/infrastructure/retry"
 	"github.com/zmb3/spotify/v2"
 	"github.com/zmb3/spotify/v2/auth"
 	"golang.org/x/oauth2"
@@ -34,6 +35,8 @@ type Client interface {
 	CurrentUsersTracks(ctx context.Context, opts ...spotify.RequestOption) (*spotify.SavedTrackPage, error)
 	Search(ctx context.Context, query string, t spotify.SearchType, opts ...spotify.RequestOption) (*spotify.SearchResult, error)
 	AddTracksToLibrary(ctx context.Context, ids ...spotify.ID) error
+	UserHasTracks(ctx context.Context, ids ...spotify.ID) ([]bool, error)
+	RemoveTracksFromLibrary(ctx context.Context, ids ...spotify.ID) error
 	GetPlaylistItems(ctx context.Context, playlistID spotify.ID, opts ...spotify.RequestOption) (*spotify.PlaylistItemPage, error)
 	CreatePlaylistForUser(ctx context.Context, userID, playlistName, description string, public bool, collaborative bool) (*spotify.FullPlaylist, error)
 	ReplacePlaylistTracks(ctx context.Context, playlistID spotify.ID, trackIDs ...spotify.ID) error
@@ -43,11 +46,19 @@ type Client interface {
 	GetRecommendations(ctx context.Context, seeds spotify.Seeds, trackAttributes *spotify.TrackAttributes, opts ...spotify.RequestOption) (*spotify.Recommendations, error)
 }
 
+const (
+	searchLimit       = 10
+	libraryBatchSize  = 50
+	requestsPerSecond = 10
+)
+
 // Spotify is the external Spotify service implementation.
 type Spotify struct {
 	authenticator Authenticator
 	client        Client
+	httpClient    *http.Client
 	secrets       config.Config
+	matcher       domain.Matcher
 }
 
 // NewSpotify creates a Spotify instance.
@@ -72,9 +83,13 @@ func NewSpotify(secrets config.Config) (*Spotify, error) {
 		),
 	)
 
+	transport := retry.NewTransport(retry.RequestsPerSecond("SPOTIFY_REQUESTS_PER_SECOND", requestsPerSecond))
+
 	service := &Spotify{
 		authenticator: authenticator,
+		httpClient:    &http.Client{Transport: transport},
 		secrets:       secrets,
+		matcher:       domain.NewMatcher(domain.DefaultThreshold),
 	}
 	service.authenticateFromSecrets(secrets)
 
@@ -103,23 +118,22 @@ func (s *Spotify) CodeParam() string {
 }
 
 // Authenticate takes an authorization code and authenticates the user.
-func (s *Spotify) Authenticate(code string, redirectURL string) error {
-	ctx := context.Background()
+func (s *Spotify) Authenticate(ctx context.Context, code string, redirectURL string) error {
 	redirectOption := oauth2.SetAuthURLParam("redirect_uri", redirectURL)
-	token, err := s.authenticator.Exchange(ctx, code, redirectOption)
+	token, err := s.authenticator.Exchange(s.clientContext(ctx), code, redirectOption)
 	if err != nil {
 		return fmt.Errorf("failed to authenticate on Spotify: %w", err)
 	}
 
-	client := s.authenticator.Client(ctx, token)
+	// The client refreshes its token for as long as it is used, so it does not stop with the login context.
+	client := s.authenticator.Client(s.clientContext(context.Background()), token)
 	s.client = spotify.New(client)
 
 	return nil
 }
 
 // GetUsername requests and returns the username of the logged-in user.
-func (s *Spotify) GetUsername() (string, error) {
-	ctx := context.Background()
+func (s *Spotify) GetUsername(ctx context.Context) (string, error) {
 	user, err := s.client.CurrentUser(ctx)
 	if err != nil {
 		return "", fmt.Errorf("failed to read Spotify profile data: %w", err)
@@ -129,51 +143,163 @@ func (s *Spotify) GetUsername() (string, error) {
 }
 
 // GetLovedTracks returns loved tracks from the external service.
-func (s *Spotify) GetLovedTracks(limit int, page int) (tracks []domain.Track, err error) {
-	ctx := context.Background()
+func (s *Spotify) GetLovedTracks(ctx context.Context, limit int, page int) ([]domain.Track, error) {
+	result, err := s.GetLovedTracksPage(ctx, limit, page)
+	return result.Tracks, err
+}
+
+// GetLovedTracksPage returns loved tracks from the external service, along with the total number of saved tracks.
+func (s *Spotify) GetLovedTracksPage(ctx context.Context, limit int, page int) (result domain.TrackPage, err error) {
 	offset := (page - 1) * limit
 	options := []spotify.RequestOption{spotify.Limit(limit), spotify.Offset(offset)}
 
-	result, err := s.client.CurrentUsersTracks(ctx, options...)
+	savedTracks, err := s.client.CurrentUsersTracks(ctx, options...)
 	if err != nil {
-		return tracks, fmt.Errorf("failed to read Spotify loved tracks: %w", err)
+		return result, fmt.Errorf("failed to read Spotify loved tracks: %w", err)
 	}
 
-	for _, resultTrack := range result.Tracks {
-		track := domain.Track{
-			Artist: resultTrack.Artists[0].Name,
-			Name:   resultTrack.Name,
+	for _, resultTrack := range savedTracks.Tracks {
+		track := trackFromSpotify(resultTrack.FullTrack)
+		if addedAt, err := time.Parse(spotify.TimestampLayout, resultTrack.AddedAt); err == nil {
+			track.LovedAt = addedAt
 		}
-		tracks = append(tracks, track)
+		result.Tracks = append(result.Tracks, track)
 	}
+	result.Total = int(savedTracks.Total)
 	return
 }
 
-// LoveTrack marks a track as loved on the external service.
-func (s *Spotify) LoveTrack(track domain.Track) error {
-	ctx := context.Background()
+// SetMatcher sets the matcher used to select search results when loving tracks.
+func (s *Spotify) SetMatcher(matcher domain.Matcher) {
+	s.matcher = matcher
+}
+
+// FindTrack looks up the track on the external service.
+func (s *Spotify) FindTrack(ctx context.Context, track domain.Track) (domain.Match, error) {
+	return s.matchTrack(ctx, track)
+}
+
+// LoveTrack marks a track found on the external service as loved.
+func (s *Spotify) LoveTrack(ctx context.Context, track domain.Track) error {
+	if err := s.client.AddTracksToLibrary(ctx, spotify.ID(track.ID)); err != nil {
+		return fmt.Errorf("failed to mark track as loved on Spotify: %w", err)
+	}
+
+	return nil
+}
+
+// UnloveTrack removes a track found on the external service from the loved tracks.
+func (s *Spotify) UnloveTrack(ctx context.Context, track domain.Track) error {
+	if err := s.client.RemoveTracksFromLibrary(ctx, spotify.ID(track.ID)); err != nil {
+		return fmt.Errorf("failed to remove track from Spotify library: %w", err)
+	}
+
+	return nil
+}
+
+// LoveTracks marks multiple tracks found on the external service as loved, adding them to the library in batches.
+func (s *Spotify) LoveTracks(ctx context.Context, tracks []domain.Track) []error {
+	errs := make([]error, len(tracks))
+
+	for number, batch := range batches(tracks) {
+		if err := s.client.AddTracksToLibrary(ctx, trackIDs(batch)...); err != nil {
+			for index := range batch {
+				errs[number*libraryBatchSize+index] = fmt.Errorf("failed to mark track as loved on Spotify: %w", err)
+			}
+		}
+	}
+
+	return errs
+}
+
+// Loved returns whether the tracks found on the external service are in the library.
+func (s *Spotify) Loved(ctx context.Context, tracks []domain.Track) ([]bool, error) {
+	var loved []bool
+
+	for _, batch := range batches(tracks) {
+		result, err := s.client.UserHasTracks(ctx, trackIDs(batch)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Spotify library: %w", err)
+		}
+		loved = append(loved, result...)
+	}
+
+	return loved, nil
+}
+
+func (s *Spotify) matchTrack(ctx context.Context, track domain.Track) (domain.Match, error) {
+	if track.ISRC != "" {
+		candidates, err := s.searchTracks(ctx, "isrc:"+track.ISRC)
+		if err != nil {
+			return domain.Match{}, err
+		}
+
+		if len(candidates) > 0 {
+			match := domain.Match{
+				Track:      candidates[0],
+				Confidence: 1,
+				Method:     domain.MatchByISRC,
+			}
+			return match, nil
+		}
+	}
+
 	query := fmt.Sprintf("artist:%q track:%q", track.Artist, track.Name)
 	query = strings.ReplaceAll(query, `\"`, "")
 
-	options := []spotify.RequestOption{
-		spotify.Limit(1),
+	candidates, err := s.searchTracks(ctx, query)
+	if err != nil {
+		return domain.Match{}, err
 	}
 
-	result, err := s.client.Search(ctx, query, spotify.SearchTypeTrack, options...)
-	if err != nil {
-		return fmt.Errorf("failed to search track on Spotify: %w", err)
+	if len(candidates) == 0 {
+		query = domain.NormalizeArtist(track.Artist) + " " + domain.NormalizeTitle(track.Name)
+		if candidates, err = s.searchTracks(ctx, query); err != nil {
+			return domain.Match{}, err
+		}
 	}
 
-	if len(result.Tracks.Tracks) == 0 {
-		return nil
+	match, err := s.matcher.Best(track, candidates)
+	if errors.Is(err, domain.ErrTrackNotFound) {
+		return match, fmt.Errorf("%w on Spotify: %s", domain.ErrTrackNotFound, track)
 	}
 
-	trackID := result.Tracks.Tracks[0].ID
-	if err := s.client.AddTracksToLibrary(ctx, trackID); err != nil {
-		return fmt.Errorf("failed to mark track as loved on Spotify: %w", err)
+	return match, err
+}
+
+// batches splits tracks in batches of the maximum size accepted by the library endpoints.
+func batches(tracks []domain.Track) (result [][]domain.Track) {
+	for start := 0; start < len(tracks); start += libraryBatchSize {
+		end := start + libraryBatchSize
+		if end > len(tracks) {
+			end = len(tracks)
+		}
+		result = append(result, tracks[start:end])
 	}
+	return
+}
 
-	return nil
+func trackIDs(tracks []domain.Track) (ids []spotify.ID) {
+	for _, track := range tracks {
+		ids = append(ids, spotify.ID(track.ID))
+	}
+	return
+}
+
+func (s *Spotify) searchTracks(ctx context.Context, query string) (tracks []domain.Track, err error) {
+	options := []spotify.RequestOption{
+		spotify.Limit(searchLimit),
+	}
+
+	result, err := s.client.Search(ctx, query, spotify.SearchTypeTrack, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search track on Spotify: %w", err)
+	}
+
+	for _, resultTrack := range result.Tracks.Tracks {
+		tracks = append(tracks, trackFromSpotify(resultTrack))
+	}
+	return
 }
 
 // Close persists any state before quitting the application.
@@ -194,6 +320,24 @@ func (s *Spotify) Close() error {
 	return nil
 }
 
+func trackFromSpotify(spotifyTrack spotify.FullTrack) domain.Track {
+	track := domain.Track{
+		ID:       spotifyTrack.ID.String(),
+		URI:      string(spotifyTrack.URI),
+		Name:     spotifyTrack.Name,
+		Album:    spotifyTrack.Album.Name,
+		Duration: spotifyTrack.TimeDuration(),
+		ISRC:     spotifyTrack.ExternalIDs["isrc"],
+	}
+	for _, artist := range spotifyTrack.Artists {
+		track.Artists = append(track.Artists, artist.Name)
+	}
+	if len(track.Artists) > 0 {
+		track.Artist = track.Artists[0]
+	}
+	return track
+}
+
 func (s *Spotify) persistToken(token *oauth2.Token) error {
 	s.secrets.Set("token_type", token.TokenType)
 	s.secrets.Set("access_token", token.AccessToken)
@@ -207,7 +351,7 @@ func (s *Spotify) persistToken(token *oauth2.Token) error {
 }
 
 func (s *Spotify) authenticateFromSecrets(secrets config.Config) {
-	ctx := context.Background()
+	ctx := s.clientContext(context.Background())
 	if !secrets.IsSet("token_type") {
 		return
 	}
@@ -228,8 +372,15 @@ func (s *Spotify) authenticateFromSecrets(secrets config.Config) {
 	s.client = spotify.New(client)
 }
 
-func (s *Spotify) GetUserId() (string, error) {
-	ctx := context.Background()
+// clientContext returns a context making OAuth2 clients use our retrying HTTP client.
+func (s *Spotify) clientContext(ctx context.Context) context.Context {
+	if s.httpClient == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, oauth2.HTTPClient, s.httpClient)
+}
+
+func (s *Spotify) GetUserId(ctx context.Context) (string, error) {
 	user, err := s.client.CurrentUser(ctx)
 	if err != nil {
 		return "", fmt.Errorf("failed to read Spotify profile data: %w", err)
@@ -238,9 +389,8 @@ func (s *Spotify) GetUserId() (string, error) {
 	return user.ID, nil
 }
 
-func (s *Spotify) DumpDiscoverWeeklyTracksToNewPlaylist(writer io.Writer) error {
-	ctx := context.Background()
-	userId, err := s.GetUserId()
+func (s *Spotify) DumpDiscoverWeeklyTracksToNewPlaylist(ctx context.Context, writer io.Writer) error {
+	userId, err := s.GetUserId(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to read Spotify profile data: %w", err)
 	}
@@ -309,9 +459,8 @@ func (s *Spotify) DumpDiscoverWeeklyTracksToNewPlaylist(writer io.Writer) error
 	return nil
 }
 
-func (s *Spotify) DiscoverDailyPlaylist(writer io.Writer) error {
-	ctx := context.Background()
-	userId, err := s.GetUserId()
+func (s *Spotify) DiscoverDailyPlaylist(ctx context.Context, writer io.Writer) error {
+	userId, err := s.GetUserId(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to read Spotify profile data: %w", err)
 	}
@@ -287,6 +287,25 @@ func (mr *MockClientMockRecorder) GetRecommendations(ctx, seeds, trackAttributes
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecommendations", reflect.TypeOf((*MockClient)(nil).GetRecommendations), varargs...)
 }
 
+// RemoveTracksFromLibrary mocks base method.
+func (m *MockClient) RemoveTracksFromLibrary(ctx context.Context, ids ...spotify.ID) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RemoveTracksFromLibrary", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveTracksFromLibrary indicates an expected call of RemoveTracksFromLibrary.
+func (mr *MockClientMockRecorder) RemoveTracksFromLibrary(ctx any, ids ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, ids...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTracksFromLibrary", reflect.TypeOf((*MockClient)(nil).RemoveTracksFromLibrary), varargs...)
+}
+
 // ReplacePlaylistTracks mocks base method.
 func (m *MockClient) ReplacePlaylistTracks(ctx context.Context, playlistID spotify.ID, trackIDs ...spotify.ID) error {
 	m.ctrl.T.Helper()
@@ -340,3 +359,23 @@ func (mr *MockClientMockRecorder) Token() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockClient)(nil).Token))
 }
+
+// UserHasTracks mocks base method.
+func (m *MockClient) UserHasTracks(ctx context.Context, ids ...spotify.ID) ([]bool, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UserHasTracks", varargs...)
+	ret0, _ := ret[0].([]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserHasTracks indicates an expected call of UserHasTracks.
+func (mr *MockClientMockRecorder) UserHasTracks(ctx any, ids ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, ids...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserHasTracks", reflect.TypeOf((*MockClient)(nil).UserHasTracks), varargs...)
+}
@@ -1,6 +1,7 @@
 package spotify
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"go.uber.org/mock/gomock"
@@ -71,7 +72,7 @@ func TestSpotify(t *testing.T) {
 			authenticator: authenticator,
 		}
 
-		err := service.Authenticate("authcode", "https://admirer.test/foo")
+		err := service.Authenticate(context.Background(), "authcode", "https://admirer.test/foo")
 
 		if err != nil {
 			t.Errorf("Unexpected error: %v", err)
@@ -82,6 +83,32 @@ func TestSpotify(t *testing.T) {
 		}
 	})
 
+	t.Run("authenticates using retrying HTTP client", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		token := &oauth2.Token{}
+		httpClient := &http.Client{}
+		usesHTTPClient := gomock.Cond(func(x any) bool {
+			ctx, ok := x.(context.Context)
+			return ok && ctx.Value(oauth2.HTTPClient) == httpClient
+		})
+
+		authenticator := NewMockAuthenticator(ctrl)
+		authenticator.EXPECT().Exchange(usesHTTPClient, "authcode", gomock.Any()).Return(token, nil)
+		authenticator.EXPECT().Client(usesHTTPClient, token).Return(httpClient)
+
+		service := &Spotify{
+			authenticator: authenticator,
+			httpClient:    httpClient,
+		}
+
+		err := service.Authenticate(context.Background(), "authcode", "https://admirer.test/foo")
+
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
 	t.Run("returns error for invalid token", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 
@@ -90,7 +117,7 @@ func TestSpotify(t *testing.T) {
 
 		service := &Spotify{authenticator: authenticator}
 
-		err := service.Authenticate("authcode", "")
+		err := service.Authenticate(context.Background(), "authcode", "")
 
 		if err == nil {
 			t.Fatal("Expected an error")
@@ -112,7 +139,7 @@ func TestSpotify(t *testing.T) {
 		service := &Spotify{client: client}
 
 		expected := "Joe"
-		got, err := service.GetUsername()
+		got, err := service.GetUsername(context.Background())
 
 		if err != nil {
 			t.Errorf("Unexpected error: %v", err)
@@ -131,7 +158,7 @@ func TestSpotify(t *testing.T) {
 
 		service := &Spotify{client: client}
 
-		got, err := service.GetUsername()
+		got, err := service.GetUsername(context.Background())
 		expected := ""
 
 		if got != expected {
@@ -179,14 +206,27 @@ func TestSpotify(t *testing.T) {
 		result := &spotify.SavedTrackPage{
 			Tracks: []spotify.SavedTrack{
 				{
+					AddedAt: "2020-09-13T12:26:40Z",
 					FullTrack: spotify.FullTrack{
 						SimpleTrack: spotify.SimpleTrack{
+							ID:  "trackID",
+							URI: "spotify:track:trackID",
 							Artists: []spotify.SimpleArtist{
 								{
 									Name: "Awesome Artist",
 								},
+								{
+									Name: "Featured Artist",
+								},
 							},
-							Name: "Blam (Instrumental)",
+							Name:     "Blam (Instrumental)",
+							Duration: 215000,
+						},
+						Album: spotify.SimpleAlbum{
+							Name: "Blam",
+						},
+						ExternalIDs: map[string]string{
+							"isrc": "USABC2000001",
 						},
 					},
 				},
@@ -213,24 +253,30 @@ func TestSpotify(t *testing.T) {
 
 		expected := []domain.Track{
 			{
-				Artist: "Awesome Artist",
-				Name:   "Blam (Instrumental)",
+				ID:       "trackID",
+				URI:      "spotify:track:trackID",
+				Artist:   "Awesome Artist",
+				Artists:  []string{"Awesome Artist", "Featured Artist"},
+				Name:     "Blam (Instrumental)",
+				Album:    "Blam",
+				Duration: 215 * time.Second,
+				ISRC:     "USABC2000001",
+				LovedAt:  time.Date(2020, time.September, 13, 12, 26, 40, 0, time.UTC),
 			},
 			{
-				Artist: "Foo & Bar",
-				Name:   "Mr. Testy",
+				Artist:  "Foo & Bar",
+				Artists: []string{"Foo & Bar"},
+				Name:    "Mr. Testy",
 			},
 		}
-		got, err := service.GetLovedTracks(5, 1)
+		got, err := service.GetLovedTracks(context.Background(), 5, 1)
 
 		if err != nil {
 			t.Errorf("Unexpected error: %v", err)
 		}
 
-		for index, expectedTrack := range expected {
-			if got[index] != expectedTrack {
-				t.Errorf("expected %q, got %q", expectedTrack, got[index])
-			}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
 		}
 	})
 
@@ -244,7 +290,7 @@ func TestSpotify(t *testing.T) {
 			client: client,
 		}
 
-		got, err := service.GetLovedTracks(5, 1)
+		got, err := service.GetLovedTracks(context.Background(), 5, 1)
 
 		if err == nil {
 			t.Error("Expected an error")
@@ -255,7 +301,7 @@ func TestSpotify(t *testing.T) {
 		}
 	})
 
-	t.Run("marks track as loved", func(t *testing.T) {
+	t.Run("finds track", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 
 		result := &spotify.SearchResult{
@@ -272,7 +318,6 @@ func TestSpotify(t *testing.T) {
 
 		client := NewMockClient(ctrl)
 		client.EXPECT().Search(gomock.Any(), `artist:"Foo & Bar The Famous Two" track:"Mr. Testy - 12 Version"`, gomock.Any(), gomock.Any()).Return(result, nil)
-		client.EXPECT().AddTracksToLibrary(gomock.Any(), []spotify.ID{"trackID"})
 
 		service := &Spotify{
 			client: client,
@@ -283,11 +328,16 @@ func TestSpotify(t *testing.T) {
 			Name:   `Mr. Testy - 12" Version`,
 		}
 
-		err := service.LoveTrack(track)
+		got, err := service.FindTrack(context.Background(), track)
 
 		if err != nil {
 			t.Errorf("Unexpected error: %v", err)
 		}
+
+		expected := "trackID"
+		if got.Track.ID != expected {
+			t.Errorf("expected %q, got %q", expected, got.Track.ID)
+		}
 	})
 
 	t.Run("returns error when failing to search track", func(t *testing.T) {
@@ -305,14 +355,14 @@ func TestSpotify(t *testing.T) {
 			Name:   "Mr. Testy",
 		}
 
-		err := service.LoveTrack(track)
+		_, err := service.FindTrack(context.Background(), track)
 
 		if err == nil {
 			t.Error("Expected an error")
 		}
 	})
 
-	t.Run("skip marking track as loved when no track is found", func(t *testing.T) {
+	t.Run("returns track not found error when no track is found", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 
 		result := &spotify.SearchResult{
@@ -322,7 +372,7 @@ func TestSpotify(t *testing.T) {
 		}
 
 		client := NewMockClient(ctrl)
-		client.EXPECT().Search(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(result, nil)
+		client.EXPECT().Search(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(2).Return(result, nil)
 
 		service := &Spotify{
 			client: client,
@@ -333,14 +383,69 @@ func TestSpotify(t *testing.T) {
 			Name:   "Mr. Testy",
 		}
 
-		err := service.LoveTrack(track)
+		_, err := service.FindTrack(context.Background(), track)
+
+		if !errors.Is(err, domain.ErrTrackNotFound) {
+			t.Errorf("expected %v, got %v", domain.ErrTrackNotFound, err)
+		}
+	})
+
+	t.Run("finds best matching track using loose search when exact search yields no results", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		emptyResult := &spotify.SearchResult{
+			Tracks: &spotify.FullTrackPage{},
+		}
+		result := &spotify.SearchResult{
+			Tracks: &spotify.FullTrackPage{
+				Tracks: []spotify.FullTrack{
+					{
+						SimpleTrack: spotify.SimpleTrack{
+							ID:      "karaokeID",
+							Name:    "Mr. Testy (Karaoke Version)",
+							Artists: []spotify.SimpleArtist{{Name: "Karaoke All Stars"}},
+						},
+					},
+					{
+						SimpleTrack: spotify.SimpleTrack{
+							ID:      "trackID",
+							Name:    "Mr. Testy - Remastered 2011",
+							Artists: []spotify.SimpleArtist{{Name: "Foo"}},
+						},
+					},
+				},
+			},
+		}
+
+		client := NewMockClient(ctrl)
+		gomock.InOrder(
+			client.EXPECT().Search(gomock.Any(), `artist:"Foo & Bar" track:"Mr. Testy (feat. Baz)"`, gomock.Any(), gomock.Any()).Return(emptyResult, nil),
+			client.EXPECT().Search(gomock.Any(), "foo and bar mr testy", gomock.Any(), gomock.Any()).Return(result, nil),
+		)
+
+		service := &Spotify{
+			client:  client,
+			matcher: domain.NewMatcher(domain.DefaultThreshold),
+		}
+
+		track := domain.Track{
+			Artist: "Foo & Bar",
+			Name:   "Mr. Testy (feat. Baz)",
+		}
+
+		got, err := service.FindTrack(context.Background(), track)
 
 		if err != nil {
 			t.Errorf("Unexpected error: %v", err)
 		}
+
+		expected := "trackID"
+		if got.Track.ID != expected {
+			t.Errorf("expected %q, got %q", expected, got.Track.ID)
+		}
 	})
 
-	t.Run("returns error when failing to add track to library", func(t *testing.T) {
+	t.Run("finds track by ISRC", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 
 		result := &spotify.SearchResult{
@@ -348,7 +453,103 @@ func TestSpotify(t *testing.T) {
 				Tracks: []spotify.FullTrack{
 					{
 						SimpleTrack: spotify.SimpleTrack{
-							ID: "trackID",
+							ID:      "trackID",
+							Name:    "Mr. Testy",
+							Artists: []spotify.SimpleArtist{{Name: "Foo & Bar"}},
+						},
+					},
+				},
+			},
+		}
+
+		client := NewMockClient(ctrl)
+		client.EXPECT().Search(gomock.Any(), "isrc:USABC2000001", gomock.Any(), gomock.Any()).Return(result, nil)
+
+		service := &Spotify{
+			client:  client,
+			matcher: domain.NewMatcher(domain.DefaultThreshold),
+		}
+
+		track := domain.Track{
+			Artist: "Foo & Bar",
+			Name:   "Mr. Testy",
+			ISRC:   "USABC2000001",
+		}
+
+		got, err := service.FindTrack(context.Background(), track)
+
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		if got.Track.ID != "trackID" {
+			t.Errorf("expected %q, got %q", "trackID", got.Track.ID)
+		}
+
+		if got.Method != domain.MatchByISRC {
+			t.Errorf("expected %q, got %q", domain.MatchByISRC, got.Method)
+		}
+	})
+
+	t.Run("falls back to searching artist and title when ISRC yields no results", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		emptyResult := &spotify.SearchResult{
+			Tracks: &spotify.FullTrackPage{},
+		}
+		result := &spotify.SearchResult{
+			Tracks: &spotify.FullTrackPage{
+				Tracks: []spotify.FullTrack{
+					{
+						SimpleTrack: spotify.SimpleTrack{
+							ID:      "trackID",
+							Name:    "Mr. Testy",
+							Artists: []spotify.SimpleArtist{{Name: "Foo & Bar"}},
+						},
+					},
+				},
+			},
+		}
+
+		client := NewMockClient(ctrl)
+		gomock.InOrder(
+			client.EXPECT().Search(gomock.Any(), "isrc:USABC2000001", gomock.Any(), gomock.Any()).Return(emptyResult, nil),
+			client.EXPECT().Search(gomock.Any(), `artist:"Foo & Bar" track:"Mr. Testy"`, gomock.Any(), gomock.Any()).Return(result, nil),
+		)
+
+		service := &Spotify{
+			client:  client,
+			matcher: domain.NewMatcher(domain.DefaultThreshold),
+		}
+
+		track := domain.Track{
+			Artist: "Foo & Bar",
+			Name:   "Mr. Testy",
+			ISRC:   "USABC2000001",
+		}
+
+		got, err := service.FindTrack(context.Background(), track)
+
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		if got.Method != domain.MatchBySearch {
+			t.Errorf("expected %q, got %q", domain.MatchBySearch, got.Method)
+		}
+	})
+
+	t.Run("returns low confidence error when best match scores below threshold", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		result := &spotify.SearchResult{
+			Tracks: &spotify.FullTrackPage{
+				Tracks: []spotify.FullTrack{
+					{
+						SimpleTrack: spotify.SimpleTrack{
+							ID:      "trackID",
+							Name:    "Mr. Tasty",
+							Artists: []spotify.SimpleArtist{{Name: "Foo & Bar"}},
 						},
 					},
 				},
@@ -357,18 +558,171 @@ func TestSpotify(t *testing.T) {
 
 		client := NewMockClient(ctrl)
 		client.EXPECT().Search(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(result, nil)
-		client.EXPECT().AddTracksToLibrary(gomock.Any(), gomock.Any()).Return(errors.New("api error"))
 
 		service := &Spotify{
 			client: client,
 		}
+		service.SetMatcher(domain.NewMatcher(0.99))
 
 		track := domain.Track{
 			Artist: "Foo & Bar",
 			Name:   "Mr. Testy",
 		}
 
-		err := service.LoveTrack(track)
+		_, err := service.FindTrack(context.Background(), track)
+
+		var lowConfidence *domain.LowConfidenceError
+		if !errors.As(err, &lowConfidence) {
+			t.Fatalf("expected low confidence error, got %v", err)
+		}
+
+		expected := "trackID"
+		if lowConfidence.Match.Track.ID != expected {
+			t.Errorf("expected %q, got %q", expected, lowConfidence.Match.Track.ID)
+		}
+	})
+
+	t.Run("marks track as loved", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		client := NewMockClient(ctrl)
+		client.EXPECT().AddTracksToLibrary(gomock.Any(), []spotify.ID{"trackID"})
+
+		service := &Spotify{
+			client: client,
+		}
+
+		err := service.LoveTrack(context.Background(), domain.Track{ID: "trackID"})
+
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("returns error when failing to add track to library", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		client := NewMockClient(ctrl)
+		client.EXPECT().AddTracksToLibrary(gomock.Any(), gomock.Any()).Return(errors.New("api error"))
+
+		service := &Spotify{
+			client: client,
+		}
+
+		err := service.LoveTrack(context.Background(), domain.Track{ID: "trackID"})
+
+		if err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("removes track from library", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		client := NewMockClient(ctrl)
+		client.EXPECT().RemoveTracksFromLibrary(gomock.Any(), []spotify.ID{"trackID"})
+
+		service := &Spotify{
+			client: client,
+		}
+
+		err := service.UnloveTrack(context.Background(), domain.Track{ID: "trackID"})
+
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("returns error when failing to remove track from library", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		client := NewMockClient(ctrl)
+		client.EXPECT().RemoveTracksFromLibrary(gomock.Any(), gomock.Any()).Return(errors.New("api error"))
+
+		service := &Spotify{
+			client: client,
+		}
+
+		err := service.UnloveTrack(context.Background(), domain.Track{ID: "trackID"})
+
+		if err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("marks multiple tracks as loved in batches", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		var tracks []domain.Track
+		var firstBatch []spotify.ID
+		for index := 0; index < 51; index++ {
+			trackID := spotify.ID(fmt.Sprintf("track%d", index))
+			tracks = append(tracks, domain.Track{ID: string(trackID)})
+			if index < 50 {
+				firstBatch = append(firstBatch, trackID)
+			}
+		}
+
+		client := NewMockClient(ctrl)
+		gomock.InOrder(
+			client.EXPECT().AddTracksToLibrary(gomock.Any(), firstBatch).Return(nil),
+			client.EXPECT().AddTracksToLibrary(gomock.Any(), []spotify.ID{"track50"}).Return(errors.New("api error")),
+		)
+
+		service := &Spotify{
+			client: client,
+		}
+
+		got := service.LoveTracks(context.Background(), tracks)
+
+		if len(got) != len(tracks) {
+			t.Fatalf("expected %d results, got %d", len(tracks), len(got))
+		}
+
+		for _, err := range got[:50] {
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}
+
+		if got[50] == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("returns whether tracks are in library", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		client := NewMockClient(ctrl)
+		client.EXPECT().UserHasTracks(gomock.Any(), []spotify.ID{"trackOne", "trackTwo"}).Return([]bool{true, false}, nil)
+
+		service := &Spotify{
+			client: client,
+		}
+
+		got, err := service.Loved(context.Background(), []domain.Track{{ID: "trackOne"}, {ID: "trackTwo"}})
+
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		expected := []bool{true, false}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("returns error when failing to read library", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		client := NewMockClient(ctrl)
+		client.EXPECT().UserHasTracks(gomock.Any(), gomock.Any()).Return(nil, errors.New("api error"))
+
+		service := &Spotify{
+			client: client,
+		}
+
+		_, err := service.Loved(context.Background(), []domain.Track{{ID: "trackOne"}})
 
 		if err == nil {
 			t.Error("Expected an error")
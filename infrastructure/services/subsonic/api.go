@@ -0,0 +1,139 @@
+package subsonic
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/dietrichm/admirer/domain"
+)
+
+const (
+	apiVersion = "1.16.1"
+	clientName = "admirer"
+)
+
+// Error is an error returned by the Subsonic API.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+type envelope struct {
+	Response response `json:"subsonic-response"`
+}
+
+type response struct {
+	Status       string    `json:"status"`
+	Error        *Error    `json:"error"`
+	Starred      *songList `json:"starred2"`
+	SearchResult *songList `json:"searchResult3"`
+}
+
+type songList struct {
+	Songs []song `json:"song"`
+}
+
+type song struct {
+	ID            string `json:"id"`
+	Title         string `json:"title"`
+	Artist        string `json:"artist"`
+	Album         string `json:"album"`
+	Duration      int    `json:"duration"`
+	Starred       string `json:"starred"`
+	MusicBrainzID string `json:"musicBrainzId"`
+}
+
+func (s song) track() domain.Track {
+	track := domain.Track{
+		ID:       s.ID,
+		Artist:   s.Artist,
+		Artists:  []string{s.Artist},
+		Name:     s.Title,
+		Album:    s.Album,
+		Duration: time.Duration(s.Duration) * time.Second,
+		MBID:     s.MusicBrainzID,
+	}
+
+	if starred, err := time.Parse(time.RFC3339, s.Starred); err == nil {
+		track.LovedAt = starred.UTC()
+	}
+
+	return track
+}
+
+// request calls the endpoint using salted token authentication.
+// See http://www.subsonic.org/pages/api.jsp.
+func (s *Subsonic) request(ctx context.Context, endpoint string, query url.Values, result *response) error {
+	salt, err := newSalt()
+	if err != nil {
+		return err
+	}
+
+	values := url.Values{
+		"u": {s.secrets.GetString("username")},
+		"t": {token(s.secrets.GetString("password"), salt)},
+		"s": {salt},
+		"v": {apiVersion},
+		"c": {clientName},
+		"f": {"json"},
+	}
+	for key, value := range query {
+		values[key] = value
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, s.secrets.GetString("server_url")+"/rest/"+endpoint+".view?"+values.Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	httpResponse, err := s.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+
+	if httpResponse.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected HTTP status %s", httpResponse.Status)
+	}
+
+	var body envelope
+	if err := json.NewDecoder(httpResponse.Body).Decode(&body); err != nil {
+		return err
+	}
+
+	if body.Response.Error != nil {
+		return body.Response.Error
+	}
+	if body.Response.Status != "ok" {
+		return fmt.Errorf("unexpected response status %q", body.Response.Status)
+	}
+
+	if result != nil {
+		*result = body.Response
+	}
+	return nil
+}
+
+func token(password string, salt string) string {
+	sum := md5.Sum([]byte(password + salt))
+	return hex.EncodeToString(sum[:])
+}
+
+func newSalt() (string, error) {
+	salt := make([]byte, 8)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(salt), nil
+}
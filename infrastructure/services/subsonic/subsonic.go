@@ -0,0 +1,253 @@
+package subsonic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/dietrichm/admirer/domain"
+	"github.com/dietrichm/admirer/infrastructure/config"
+	"github.com/dietrichm/admirer/infrastructure/retry"
+)
+
+const (
+	searchLimit       = 10
+	requestsPerSecond = 10
+)
+
+// Subsonic is the service implementation for servers compatible with the Subsonic API, such as Navidrome.
+// Starred songs are used as loved tracks.
+type Subsonic struct {
+	httpClient *http.Client
+	secrets    config.Config
+	matcher    domain.Matcher
+	starred    []domain.Track
+	password   string
+}
+
+// NewSubsonic creates a Subsonic instance.
+func NewSubsonic(secrets config.Config) (*Subsonic, error) {
+	transport := retry.NewTransport(retry.RequestsPerSecond("SUBSONIC_REQUESTS_PER_SECOND", requestsPerSecond))
+
+	return &Subsonic{
+		httpClient: &http.Client{Transport: transport},
+		secrets:    secrets,
+		matcher:    domain.NewMatcher(domain.DefaultThreshold),
+	}, nil
+}
+
+// Name returns the human-readable service name.
+func (s *Subsonic) Name() string {
+	return "Subsonic"
+}
+
+// Authenticated returns whether the service is logged in.
+func (s *Subsonic) Authenticated() bool {
+	return s.secrets.GetString("server_url") != "" && s.secrets.GetString("username") != ""
+}
+
+// CreateAuthURL returns an authorization URL to authorize the integration.
+// Subsonic servers are logged in on with credentials instead.
+func (s *Subsonic) CreateAuthURL(redirectURL string) string {
+	return ""
+}
+
+// CodeParam is the query parameter name used in the authentication callback.
+func (s *Subsonic) CodeParam() string {
+	return ""
+}
+
+// CredentialsUsage describes the credentials to log in with.
+func (s *Subsonic) CredentialsUsage() string {
+	return "<https://username@server>"
+}
+
+// SetPassword sets the password to authenticate with, which is read separately from the server URL.
+func (s *Subsonic) SetPassword(password string) {
+	s.password = password
+}
+
+// Authenticate takes the server URL including username, and verifies them on the server along with the password.
+func (s *Subsonic) Authenticate(ctx context.Context, credentials string, redirectURL string) error {
+	serverURL, err := url.Parse(credentials)
+	if err != nil || serverURL.Host == "" || serverURL.User == nil || serverURL.User.Username() == "" {
+		return fmt.Errorf("failed to authenticate on Subsonic: expected credentials as %s", s.CredentialsUsage())
+	}
+
+	if _, hasPassword := serverURL.User.Password(); hasPassword {
+		return errors.New("failed to authenticate on Subsonic: leave the password out of the server URL, it is asked for separately")
+	}
+
+	username := serverURL.User.Username()
+	serverURL.User = nil
+
+	s.secrets.Set("server_url", strings.TrimRight(serverURL.String(), "/"))
+	s.secrets.Set("username", username)
+	s.secrets.Set("password", s.password)
+
+	if err := s.request(ctx, "ping", nil, nil); err != nil {
+		return fmt.Errorf("failed to authenticate on Subsonic: %w", err)
+	}
+
+	if err := s.secrets.Save(); err != nil {
+		return fmt.Errorf("failed to save Subsonic secrets: %w", err)
+	}
+
+	return nil
+}
+
+// GetUsername returns the username of the logged-in user.
+func (s *Subsonic) GetUsername(ctx context.Context) (string, error) {
+	return s.secrets.GetString("username"), nil
+}
+
+// GetLovedTracks returns starred songs from the server, most recently starred first.
+// The Subsonic API returns all starred songs at once, so they are requested once and paged locally.
+func (s *Subsonic) GetLovedTracks(ctx context.Context, limit int, page int) ([]domain.Track, error) {
+	result, err := s.GetLovedTracksPage(ctx, limit, page)
+	return result.Tracks, err
+}
+
+// GetLovedTracksPage returns starred songs from the server, along with the number of starred songs.
+func (s *Subsonic) GetLovedTracksPage(ctx context.Context, limit int, page int) (domain.TrackPage, error) {
+	starred, err := s.starredTracks(ctx)
+	if err != nil {
+		return domain.TrackPage{}, err
+	}
+
+	result := domain.TrackPage{Total: len(starred)}
+
+	start := (page - 1) * limit
+	if start >= len(starred) {
+		return result, nil
+	}
+
+	end := start + limit
+	if end > len(starred) {
+		end = len(starred)
+	}
+
+	result.Tracks = starred[start:end]
+	return result, nil
+}
+
+// SetMatcher sets the matcher used to select search results when loving tracks.
+func (s *Subsonic) SetMatcher(matcher domain.Matcher) {
+	s.matcher = matcher
+}
+
+// FindTrack searches the track on the server.
+func (s *Subsonic) FindTrack(ctx context.Context, track domain.Track) (domain.Match, error) {
+	candidates, err := s.searchTracks(ctx, domain.NormalizeArtist(track.Artist)+" "+domain.NormalizeTitle(track.Name))
+	if err != nil {
+		return domain.Match{}, err
+	}
+
+	if len(candidates) == 0 {
+		if candidates, err = s.searchTracks(ctx, domain.NormalizeTitle(track.Name)); err != nil {
+			return domain.Match{}, err
+		}
+	}
+
+	match, err := s.matcher.Best(track, candidates)
+	if errors.Is(err, domain.ErrTrackNotFound) {
+		return match, fmt.Errorf("%w on Subsonic: %s", domain.ErrTrackNotFound, track)
+	}
+
+	return match, err
+}
+
+// LoveTrack stars a song found on the server.
+func (s *Subsonic) LoveTrack(ctx context.Context, track domain.Track) error {
+	if err := s.request(ctx, "star", url.Values{"id": {track.ID}}, nil); err != nil {
+		return fmt.Errorf("failed to mark track as loved on Subsonic: %w", err)
+	}
+
+	s.starred = nil
+	return nil
+}
+
+// UnloveTrack unstars a song found on the server.
+func (s *Subsonic) UnloveTrack(ctx context.Context, track domain.Track) error {
+	if err := s.request(ctx, "unstar", url.Values{"id": {track.ID}}, nil); err != nil {
+		return fmt.Errorf("failed to unlove track on Subsonic: %w", err)
+	}
+
+	s.starred = nil
+	return nil
+}
+
+// Loved returns whether the songs found on the server are starred.
+func (s *Subsonic) Loved(ctx context.Context, tracks []domain.Track) ([]bool, error) {
+	starred, err := s.starredTracks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := map[string]bool{}
+	for _, track := range starred {
+		ids[track.ID] = true
+	}
+
+	loved := make([]bool, len(tracks))
+	for index, track := range tracks {
+		loved[index] = ids[track.ID]
+	}
+
+	return loved, nil
+}
+
+// Close persists any state before quitting the application.
+func (s *Subsonic) Close() error {
+	return nil
+}
+
+func (s *Subsonic) starredTracks(ctx context.Context) ([]domain.Track, error) {
+	if s.starred != nil {
+		return s.starred, nil
+	}
+
+	var result response
+	if err := s.request(ctx, "getStarred2", nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to read Subsonic starred songs: %w", err)
+	}
+
+	starred := []domain.Track{}
+	if result.Starred != nil {
+		for _, song := range result.Starred.Songs {
+			starred = append(starred, song.track())
+		}
+	}
+
+	sort.SliceStable(starred, func(i, j int) bool {
+		return starred[i].LovedAt.After(starred[j].LovedAt)
+	})
+
+	s.starred = starred
+	return starred, nil
+}
+
+func (s *Subsonic) searchTracks(ctx context.Context, query string) (tracks []domain.Track, err error) {
+	values := url.Values{
+		"query":       {query},
+		"songCount":   {fmt.Sprint(searchLimit)},
+		"artistCount": {"0"},
+		"albumCount":  {"0"},
+	}
+
+	var result response
+	if err := s.request(ctx, "search3", values, &result); err != nil {
+		return nil, fmt.Errorf("failed to search track on Subsonic: %w", err)
+	}
+
+	if result.SearchResult != nil {
+		for _, song := range result.SearchResult.Songs {
+			tracks = append(tracks, song.track())
+		}
+	}
+	return
+}
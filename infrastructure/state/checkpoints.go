@@ -0,0 +1,100 @@
+package state
+
+import (
+	"strings"
+	"time"
+
+	"github.com/dietrichm/admirer/domain"
+)
+
+type checkpointRecord struct {
+	Source  string    `json:"source"`
+	Target  string    `json:"target"`
+	Page    int       `json:"page"`
+	Limit   int       `json:"limit"`
+	Artist  string    `json:"artist"`
+	Name    string    `json:"name"`
+	SavedAt time.Time `json:"saved_at"`
+}
+
+type fileCheckpointStore struct {
+	filename string
+	records  []checkpointRecord
+}
+
+// LoadCheckpoints loads the sync checkpoints from the admirer configuration directory.
+func LoadCheckpoints() (domain.CheckpointStore, error) {
+	return loadCheckpointsFromFile(stateFilename("checkpoints.json"))
+}
+
+func loadCheckpointsFromFile(filename string) (*fileCheckpointStore, error) {
+	store := &fileCheckpointStore{
+		filename: filename,
+	}
+
+	if err := readFile(filename, &store.records); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+func (f *fileCheckpointStore) Checkpoint(source string, target string) (domain.Checkpoint, bool) {
+	position := f.find(source, target)
+	if position < 0 {
+		return domain.Checkpoint{}, false
+	}
+
+	record := f.records[position]
+	checkpoint := domain.Checkpoint{
+		Source: record.Source,
+		Target: record.Target,
+		Page:   record.Page,
+		Limit:  record.Limit,
+		LastTrack: domain.Track{
+			Artist: record.Artist,
+			Name:   record.Name,
+		},
+	}
+	return checkpoint, true
+}
+
+func (f *fileCheckpointStore) Save(checkpoint domain.Checkpoint) error {
+	record := checkpointRecord{
+		Source:  checkpoint.Source,
+		Target:  checkpoint.Target,
+		Page:    checkpoint.Page,
+		Limit:   checkpoint.Limit,
+		Artist:  checkpoint.LastTrack.Artist,
+		Name:    checkpoint.LastTrack.Name,
+		SavedAt: time.Now().UTC(),
+	}
+
+	if position := f.find(checkpoint.Source, checkpoint.Target); position >= 0 {
+		f.records[position] = record
+	} else {
+		f.records = append(f.records, record)
+	}
+
+	return writeFile(f.filename, f.records)
+}
+
+func (f *fileCheckpointStore) Clear(source string, target string) error {
+	position := f.find(source, target)
+	if position < 0 {
+		return nil
+	}
+
+	f.records = append(f.records[:position], f.records[position+1:]...)
+
+	return writeFile(f.filename, f.records)
+}
+
+func (f *fileCheckpointStore) find(source string, target string) int {
+	for position, record := range f.records {
+		if strings.EqualFold(record.Source, source) && strings.EqualFold(record.Target, target) {
+			return position
+		}
+	}
+	return -1
+}
@@ -0,0 +1,64 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/dietrichm/admirer/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileCheckpointStore(t *testing.T) {
+	checkpoint := domain.Checkpoint{
+		Source: "Source",
+		Target: "Target",
+		Page:   37,
+		Limit:  50,
+		LastTrack: domain.Track{
+			Artist: "Foo & Bar",
+			Name:   "Mr. Testy",
+		},
+	}
+
+	t.Run("returns no checkpoint when file does not exist", func(t *testing.T) {
+		store, err := loadCheckpointsFromFile(filepath.Join(t.TempDir(), "checkpoints.json"))
+
+		assert.NoError(t, err)
+
+		_, exists := store.Checkpoint("Source", "Target")
+		assert.False(t, exists)
+	})
+
+	t.Run("persists checkpoint per pair of services", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "admirer", "checkpoints.json")
+		store, _ := loadCheckpointsFromFile(filename)
+
+		assert.NoError(t, store.Save(domain.Checkpoint{Source: "Source", Target: "Target", Page: 1}))
+		assert.NoError(t, store.Save(checkpoint))
+
+		loaded, err := loadCheckpointsFromFile(filename)
+		assert.NoError(t, err)
+
+		got, exists := loaded.Checkpoint("Source", "Target")
+		assert.True(t, exists)
+		assert.Equal(t, checkpoint, got)
+		assert.Len(t, loaded.records, 1)
+
+		_, exists = loaded.Checkpoint("Target", "Source")
+		assert.False(t, exists)
+	})
+
+	t.Run("clears checkpoint", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "checkpoints.json")
+		store, _ := loadCheckpointsFromFile(filename)
+
+		assert.NoError(t, store.Save(checkpoint))
+		assert.NoError(t, store.Clear("Source", "Target"))
+
+		loaded, err := loadCheckpointsFromFile(filename)
+		assert.NoError(t, err)
+
+		_, exists := loaded.Checkpoint("Source", "Target")
+		assert.False(t, exists)
+	})
+}
@@ -0,0 +1,183 @@
+package state
+
+import (
+	"strings"
+	"time"
+
+	"github.com/dietrichm/admirer/domain"
+)
+
+type ledgerRecord struct {
+	Source      string    `json:"source"`
+	Target      string    `json:"target"`
+	Artist      string    `json:"artist"`
+	Name        string    `json:"name"`
+	SourceID    string    `json:"source_id,omitempty"`
+	TargetID    string    `json:"target_id,omitempty"`
+	Failed      bool      `json:"failed,omitempty"`
+	Preexisting bool      `json:"preexisting,omitempty"`
+	SyncedAt    time.Time `json:"synced_at"`
+}
+
+type fileLedger struct {
+	filename string
+	records  []ledgerRecord
+	index    map[string]int
+}
+
+// LoadLedger loads the sync ledger from the admirer configuration directory.
+func LoadLedger() (domain.Ledger, error) {
+	return loadLedgerFromFile(stateFilename("ledger.json"))
+}
+
+func loadLedgerFromFile(filename string) (*fileLedger, error) {
+	ledger := &fileLedger{
+		filename: filename,
+		index:    map[string]int{},
+	}
+
+	if err := readFile(filename, &ledger.records); err != nil {
+		return nil, err
+	}
+
+	for position, record := range ledger.records {
+		ledger.index[record.key()] = position
+	}
+
+	return ledger, nil
+}
+
+func (f *fileLedger) Synced(source string, target string, track domain.Track) bool {
+	position, exists := f.index[ledgerKey(source, target, track.Artist, track.Name)]
+	return exists && !f.records[position].Failed
+}
+
+func (f *fileLedger) Record(source string, target string, track domain.Track, targetID string) {
+	record := ledgerRecord{
+		Source:   source,
+		Target:   target,
+		Artist:   track.Artist,
+		Name:     track.Name,
+		SourceID: track.ID,
+		TargetID: targetID,
+		SyncedAt: time.Now().UTC(),
+	}
+
+	if position, exists := f.index[record.key()]; exists {
+		f.records[position] = record
+		return
+	}
+
+	f.index[record.key()] = len(f.records)
+	f.records = append(f.records, record)
+}
+
+// RecordPreexisting records a track which was already loved on the target service, unless admirer synced it before.
+func (f *fileLedger) RecordPreexisting(source string, target string, track domain.Track, targetID string) {
+	record := ledgerRecord{
+		Source:      source,
+		Target:      target,
+		Artist:      track.Artist,
+		Name:        track.Name,
+		SourceID:    track.ID,
+		TargetID:    targetID,
+		Preexisting: true,
+		SyncedAt:    time.Now().UTC(),
+	}
+
+	if position, exists := f.index[record.key()]; exists {
+		if f.records[position].Failed {
+			f.records[position] = record
+		}
+		return
+	}
+
+	f.index[record.key()] = len(f.records)
+	f.records = append(f.records, record)
+}
+
+// RecordFailure keeps the track for retrying it, unless it was synced before.
+func (f *fileLedger) RecordFailure(source string, target string, track domain.Track) {
+	record := ledgerRecord{
+		Source:   source,
+		Target:   target,
+		Artist:   track.Artist,
+		Name:     track.Name,
+		SourceID: track.ID,
+		Failed:   true,
+		SyncedAt: time.Now().UTC(),
+	}
+
+	if position, exists := f.index[record.key()]; exists {
+		if f.records[position].Failed {
+			f.records[position] = record
+		}
+		return
+	}
+
+	f.index[record.key()] = len(f.records)
+	f.records = append(f.records, record)
+}
+
+func (f *fileLedger) Entries(source string, target string) (entries []domain.LedgerEntry) {
+	for _, record := range f.records {
+		if record.Failed || !record.between(source, target) {
+			continue
+		}
+
+		entries = append(entries, domain.LedgerEntry{
+			Track:       record.track(),
+			TargetID:    record.TargetID,
+			SyncedAt:    record.SyncedAt,
+			Preexisting: record.Preexisting,
+		})
+	}
+	return
+}
+
+func (f *fileLedger) Failures(source string, target string) (tracks []domain.Track) {
+	for _, record := range f.records {
+		if record.Failed && record.between(source, target) {
+			tracks = append(tracks, record.track())
+		}
+	}
+	return
+}
+
+func (f *fileLedger) Remove(source string, target string, track domain.Track) {
+	position, exists := f.index[ledgerKey(source, target, track.Artist, track.Name)]
+	if !exists {
+		return
+	}
+
+	f.records = append(f.records[:position], f.records[position+1:]...)
+
+	f.index = map[string]int{}
+	for position, record := range f.records {
+		f.index[record.key()] = position
+	}
+}
+
+func (f *fileLedger) Save() error {
+	return writeFile(f.filename, f.records)
+}
+
+func (r ledgerRecord) between(source string, target string) bool {
+	return strings.EqualFold(r.Source, source) && strings.EqualFold(r.Target, target)
+}
+
+func (r ledgerRecord) track() domain.Track {
+	return domain.Track{
+		ID:     r.SourceID,
+		Artist: r.Artist,
+		Name:   r.Name,
+	}
+}
+
+func (r ledgerRecord) key() string {
+	return ledgerKey(r.Source, r.Target, r.Artist, r.Name)
+}
+
+func ledgerKey(source string, target string, artist string, name string) string {
+	return strings.ToLower(strings.Join([]string{source, target, artist, name}, "\x00"))
+}
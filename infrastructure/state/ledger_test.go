@@ -0,0 +1,142 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dietrichm/admirer/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileLedger(t *testing.T) {
+	track := domain.Track{
+		ID:     "sourceID",
+		Artist: "Foo & Bar",
+		Name:   "Mr. Testy",
+	}
+
+	t.Run("returns empty ledger when file does not exist", func(t *testing.T) {
+		ledger, err := loadLedgerFromFile(filepath.Join(t.TempDir(), "ledger.json"))
+
+		assert.NoError(t, err)
+		assert.False(t, ledger.Synced("Source", "Target", track))
+	})
+
+	t.Run("returns whether track was synced between services", func(t *testing.T) {
+		ledger, _ := loadLedgerFromFile(filepath.Join(t.TempDir(), "ledger.json"))
+
+		ledger.Record("Source", "Target", track, "targetID")
+
+		assert.True(t, ledger.Synced("Source", "Target", track))
+		assert.True(t, ledger.Synced("Source", "Target", domain.Track{Artist: "FOO & BAR", Name: "mr. testy"}))
+		assert.False(t, ledger.Synced("Target", "Source", track))
+		assert.False(t, ledger.Synced("Source", "Target", domain.Track{Artist: "Foo & Bar", Name: "Other"}))
+	})
+
+	t.Run("persists recorded tracks", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "admirer", "ledger.json")
+		ledger, _ := loadLedgerFromFile(filename)
+
+		ledger.Record("Source", "Target", track, "targetID")
+		ledger.Record("Source", "Target", track, "targetID")
+
+		err := ledger.Save()
+		assert.NoError(t, err)
+
+		stat, err := os.Stat(filename)
+		assert.NoError(t, err)
+		assert.Equal(t, "-rw-------", stat.Mode().Perm().String())
+
+		loaded, err := loadLedgerFromFile(filename)
+		assert.NoError(t, err)
+		assert.True(t, loaded.Synced("Source", "Target", track))
+		assert.Len(t, loaded.records, 1)
+		assert.Equal(t, "targetID", loaded.records[0].TargetID)
+		assert.Equal(t, "sourceID", loaded.records[0].SourceID)
+		assert.False(t, loaded.records[0].SyncedAt.IsZero())
+	})
+
+	t.Run("returns entries synced between services", func(t *testing.T) {
+		ledger, _ := loadLedgerFromFile(filepath.Join(t.TempDir(), "ledger.json"))
+
+		ledger.Record("Source", "Target", track, "targetID")
+		ledger.Record("Target", "Source", domain.Track{Artist: "Other", Name: "Track"}, "otherID")
+
+		got := ledger.Entries("Source", "Target")
+
+		assert.Len(t, got, 1)
+		assert.Equal(t, track, got[0].Track)
+		assert.Equal(t, "targetID", got[0].TargetID)
+		assert.False(t, got[0].SyncedAt.IsZero())
+	})
+
+	t.Run("removes synced track", func(t *testing.T) {
+		ledger, _ := loadLedgerFromFile(filepath.Join(t.TempDir(), "ledger.json"))
+		other := domain.Track{Artist: "Other", Name: "Track"}
+
+		ledger.Record("Source", "Target", track, "targetID")
+		ledger.Record("Source", "Target", other, "otherID")
+		ledger.Remove("Source", "Target", track)
+
+		assert.False(t, ledger.Synced("Source", "Target", track))
+		assert.True(t, ledger.Synced("Source", "Target", other))
+		assert.Len(t, ledger.Entries("Source", "Target"), 1)
+	})
+
+	t.Run("keeps failed tracks until they are synced", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "ledger.json")
+		ledger, _ := loadLedgerFromFile(filename)
+		other := domain.Track{Artist: "Other", Name: "Track"}
+
+		ledger.Record("Source", "Target", other, "otherID")
+		ledger.RecordFailure("Source", "Target", track)
+		ledger.RecordFailure("Source", "Target", other)
+		assert.NoError(t, ledger.Save())
+
+		loaded, err := loadLedgerFromFile(filename)
+		assert.NoError(t, err)
+		assert.False(t, loaded.Synced("Source", "Target", track))
+		assert.True(t, loaded.Synced("Source", "Target", other))
+		assert.Equal(t, []domain.Track{track}, loaded.Failures("Source", "Target"))
+		assert.Empty(t, loaded.Failures("Target", "Source"))
+		assert.Len(t, loaded.Entries("Source", "Target"), 1)
+
+		loaded.Record("Source", "Target", track, "targetID")
+
+		assert.True(t, loaded.Synced("Source", "Target", track))
+		assert.Empty(t, loaded.Failures("Source", "Target"))
+		assert.Len(t, loaded.Entries("Source", "Target"), 2)
+	})
+
+	t.Run("records tracks which were already loved on target service", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "ledger.json")
+		ledger, _ := loadLedgerFromFile(filename)
+		other := domain.Track{Artist: "Other", Name: "Track"}
+
+		ledger.Record("Source", "Target", other, "otherID")
+		ledger.RecordPreexisting("Source", "Target", track, "targetID")
+		ledger.RecordPreexisting("Source", "Target", other, "otherID")
+		assert.NoError(t, ledger.Save())
+
+		loaded, err := loadLedgerFromFile(filename)
+		assert.NoError(t, err)
+		assert.True(t, loaded.Synced("Source", "Target", track))
+
+		entries := loaded.Entries("Source", "Target")
+		assert.Len(t, entries, 2)
+		assert.False(t, entries[0].Preexisting)
+		assert.True(t, entries[1].Preexisting)
+		assert.Equal(t, track, entries[1].Track)
+	})
+
+	t.Run("returns error for invalid file", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "ledger.json")
+		os.WriteFile(filename, []byte("$$$"), 0600)
+
+		ledger, err := loadLedgerFromFile(filename)
+
+		assert.Error(t, err)
+		assert.Nil(t, ledger)
+	})
+}
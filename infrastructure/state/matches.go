@@ -0,0 +1,163 @@
+package state
+
+import (
+	"strings"
+	"time"
+
+	"github.com/dietrichm/admirer/domain"
+)
+
+type matchRecord struct {
+	Target     string        `json:"target"`
+	Key        string        `json:"key"`
+	NotFound   bool          `json:"not_found,omitempty"`
+	ID         string        `json:"id,omitempty"`
+	URI        string        `json:"uri,omitempty"`
+	Artist     string        `json:"artist,omitempty"`
+	Artists    []string      `json:"artists,omitempty"`
+	Name       string        `json:"name,omitempty"`
+	Album      string        `json:"album,omitempty"`
+	Duration   time.Duration `json:"duration,omitempty"`
+	ISRC       string        `json:"isrc,omitempty"`
+	MBID       string        `json:"mbid,omitempty"`
+	ArtistMBID string        `json:"artist_mbid,omitempty"`
+	Method     string        `json:"method,omitempty"`
+	Confidence float64       `json:"confidence,omitempty"`
+	CachedAt   time.Time     `json:"cached_at"`
+}
+
+type fileMatchCache struct {
+	filename string
+	ttl      time.Duration
+	records  []matchRecord
+	index    map[string]int
+}
+
+// LoadMatchCache loads the cache of tracks found on target services from the admirer configuration directory.
+// Cached tracks expire after the ttl.
+func LoadMatchCache(ttl time.Duration) (domain.MatchCache, error) {
+	return loadMatchCacheFromFile(stateFilename("matches.json"), ttl)
+}
+
+func loadMatchCacheFromFile(filename string, ttl time.Duration) (*fileMatchCache, error) {
+	cache := &fileMatchCache{
+		filename: filename,
+		ttl:      ttl,
+		index:    map[string]int{},
+	}
+
+	if err := readFile(filename, &cache.records); err != nil {
+		return nil, err
+	}
+
+	for position, record := range cache.records {
+		cache.index[matchIndexKey(record.Target, record.Key)] = position
+	}
+
+	return cache, nil
+}
+
+func (f *fileMatchCache) Lookup(target string, track domain.Track) (domain.CachedMatch, bool) {
+	position, exists := f.index[matchIndexKey(target, matchKey(track))]
+	if !exists || f.expired(f.records[position]) {
+		return domain.CachedMatch{}, false
+	}
+
+	return f.records[position].cachedMatch(), true
+}
+
+func (f *fileMatchCache) Store(target string, track domain.Track, match domain.CachedMatch) {
+	record := newMatchRecord(target, matchKey(track), match)
+
+	if position, exists := f.index[matchIndexKey(record.Target, record.Key)]; exists {
+		f.records[position] = record
+		return
+	}
+
+	f.index[matchIndexKey(record.Target, record.Key)] = len(f.records)
+	f.records = append(f.records, record)
+}
+
+// Save writes the cache, leaving out expired tracks.
+func (f *fileMatchCache) Save() error {
+	records := make([]matchRecord, 0, len(f.records))
+	for _, record := range f.records {
+		if !f.expired(record) {
+			records = append(records, record)
+		}
+	}
+
+	return writeFile(f.filename, records)
+}
+
+func (f *fileMatchCache) expired(record matchRecord) bool {
+	return time.Since(record.CachedAt) >= f.ttl
+}
+
+func newMatchRecord(target string, key string, match domain.CachedMatch) matchRecord {
+	record := matchRecord{
+		Target:   target,
+		Key:      key,
+		NotFound: match.NotFound,
+		CachedAt: time.Now().UTC(),
+	}
+
+	if match.NotFound {
+		return record
+	}
+
+	track := match.Match.Track
+	record.ID = track.ID
+	record.URI = track.URI
+	record.Artist = track.Artist
+	record.Artists = track.Artists
+	record.Name = track.Name
+	record.Album = track.Album
+	record.Duration = track.Duration
+	record.ISRC = track.ISRC
+	record.MBID = track.MBID
+	record.ArtistMBID = track.ArtistMBID
+	record.Method = string(match.Match.Method)
+	record.Confidence = match.Match.Confidence
+	return record
+}
+
+func (r matchRecord) cachedMatch() domain.CachedMatch {
+	if r.NotFound {
+		return domain.CachedMatch{NotFound: true}
+	}
+
+	return domain.CachedMatch{
+		Match: domain.Match{
+			Track: domain.Track{
+				ID:         r.ID,
+				URI:        r.URI,
+				Artist:     r.Artist,
+				Artists:    r.Artists,
+				Name:       r.Name,
+				Album:      r.Album,
+				Duration:   r.Duration,
+				ISRC:       r.ISRC,
+				MBID:       r.MBID,
+				ArtistMBID: r.ArtistMBID,
+			},
+			Confidence: r.Confidence,
+			Method:     domain.MatchMethod(r.Method),
+		},
+	}
+}
+
+// matchKey identifies a track by its ISRC or MusicBrainz identifier, falling back to its normalised artist and title.
+func matchKey(track domain.Track) string {
+	if track.ISRC != "" {
+		return "isrc:" + strings.ToUpper(track.ISRC)
+	}
+	if track.MBID != "" {
+		return "mbid:" + strings.ToLower(track.MBID)
+	}
+	return "name:" + domain.NormalizeArtist(track.Artist) + "\x00" + domain.NormalizeTitle(track.Name)
+}
+
+func matchIndexKey(target string, key string) string {
+	return strings.ToLower(target) + "\x00" + key
+}
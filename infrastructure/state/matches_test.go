@@ -0,0 +1,90 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dietrichm/admirer/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileMatchCache(t *testing.T) {
+	t.Run("returns no match when file does not exist", func(t *testing.T) {
+		cache, err := loadMatchCacheFromFile(filepath.Join(t.TempDir(), "matches.json"), time.Hour)
+
+		assert.NoError(t, err)
+
+		_, exists := cache.Lookup("Spotify", domain.Track{Artist: "Foo", Name: "Bar"})
+		assert.False(t, exists)
+	})
+
+	t.Run("persists matches per target service", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "admirer", "matches.json")
+		cache, _ := loadMatchCacheFromFile(filename, time.Hour)
+
+		track := domain.Track{Artist: "Foo & Bar", Name: "Mr. Testy (Remastered)"}
+		missing := domain.Track{Artist: "Unknown", Name: "Nowhere"}
+		match := domain.Match{
+			Track: domain.Track{
+				ID:       "spotifyID",
+				Artist:   "Foo",
+				Artists:  []string{"Foo", "Bar"},
+				Name:     "Mr. Testy",
+				Duration: 3 * time.Minute,
+			},
+			Confidence: 0.9,
+			Method:     domain.MatchBySearch,
+		}
+
+		cache.Store("Spotify", track, domain.CachedMatch{Match: match})
+		cache.Store("Spotify", missing, domain.CachedMatch{NotFound: true})
+		assert.NoError(t, cache.Save())
+
+		loaded, err := loadMatchCacheFromFile(filename, time.Hour)
+		assert.NoError(t, err)
+
+		got, exists := loaded.Lookup("spotify", domain.Track{Artist: "foo & bar", Name: "Mr. Testy"})
+		assert.True(t, exists)
+		assert.Equal(t, domain.CachedMatch{Match: match}, got)
+
+		got, exists = loaded.Lookup("Spotify", missing)
+		assert.True(t, exists)
+		assert.Equal(t, domain.CachedMatch{NotFound: true}, got)
+
+		_, exists = loaded.Lookup("Last.fm", track)
+		assert.False(t, exists)
+	})
+
+	t.Run("identifies tracks by isrc", func(t *testing.T) {
+		cache, _ := loadMatchCacheFromFile(filepath.Join(t.TempDir(), "matches.json"), time.Hour)
+		match := domain.Match{Track: domain.Track{ID: "spotifyID"}, Confidence: 1, Method: domain.MatchByISRC}
+
+		cache.Store("Spotify", domain.Track{Artist: "Foo", Name: "Bar", ISRC: "usabc1234567"}, domain.CachedMatch{Match: match})
+
+		got, exists := cache.Lookup("Spotify", domain.Track{Artist: "Other", Name: "Title", ISRC: "USABC1234567"})
+		assert.True(t, exists)
+		assert.Equal(t, match, got.Match)
+
+		_, exists = cache.Lookup("Spotify", domain.Track{Artist: "Foo", Name: "Bar"})
+		assert.False(t, exists)
+	})
+
+	t.Run("leaves out expired matches", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "matches.json")
+		cache, _ := loadMatchCacheFromFile(filename, time.Hour)
+		track := domain.Track{Artist: "Foo", Name: "Bar"}
+
+		cache.Store("Spotify", track, domain.CachedMatch{NotFound: true})
+		cache.records[0].CachedAt = time.Now().Add(-2 * time.Hour)
+
+		_, exists := cache.Lookup("Spotify", track)
+		assert.False(t, exists)
+
+		assert.NoError(t, cache.Save())
+
+		loaded, err := loadMatchCacheFromFile(filename, 24*time.Hour)
+		assert.NoError(t, err)
+		assert.Empty(t, loaded.records)
+	})
+}
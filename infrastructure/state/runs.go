@@ -0,0 +1,60 @@
+package state
+
+import (
+	"time"
+
+	"github.com/dietrichm/admirer/domain"
+)
+
+type runRecord struct {
+	Key       string    `json:"key"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+type fileRunStore struct {
+	filename string
+	records  []runRecord
+}
+
+// LoadRuns loads the times of previous runs from the admirer configuration directory.
+func LoadRuns() (domain.RunStore, error) {
+	return loadRunsFromFile(stateFilename("runs.json"))
+}
+
+func loadRunsFromFile(filename string) (*fileRunStore, error) {
+	store := &fileRunStore{
+		filename: filename,
+	}
+
+	if err := readFile(filename, &store.records); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+func (f *fileRunStore) LastRun(key string) (time.Time, bool) {
+	for _, record := range f.records {
+		if record.Key == key {
+			return record.StartedAt, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (f *fileRunStore) Record(key string, startedAt time.Time) error {
+	record := runRecord{
+		Key:       key,
+		StartedAt: startedAt.UTC(),
+	}
+
+	for position := range f.records {
+		if f.records[position].Key == key {
+			f.records[position] = record
+			return writeFile(f.filename, f.records)
+		}
+	}
+
+	f.records = append(f.records, record)
+	return writeFile(f.filename, f.records)
+}
@@ -0,0 +1,43 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileRunStore(t *testing.T) {
+	t.Run("returns no run when file does not exist", func(t *testing.T) {
+		store, err := loadRunsFromFile(filepath.Join(t.TempDir(), "runs.json"))
+
+		assert.NoError(t, err)
+
+		_, exists := store.LastRun("sync:Source:Target")
+		assert.False(t, exists)
+	})
+
+	t.Run("persists last run per key", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "admirer", "runs.json")
+		store, _ := loadRunsFromFile(filename)
+		first := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		second := first.Add(24 * time.Hour)
+
+		assert.NoError(t, store.Record("sync:Source:Target", first))
+		assert.NoError(t, store.Record("list:Source", first))
+		assert.NoError(t, store.Record("sync:Source:Target", second))
+
+		loaded, err := loadRunsFromFile(filename)
+		assert.NoError(t, err)
+		assert.Len(t, loaded.records, 2)
+
+		got, exists := loaded.LastRun("sync:Source:Target")
+		assert.True(t, exists)
+		assert.Equal(t, second, got)
+
+		got, exists = loaded.LastRun("list:Source")
+		assert.True(t, exists)
+		assert.Equal(t, first, got)
+	})
+}
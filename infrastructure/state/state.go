@@ -0,0 +1,73 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	permissions          os.FileMode = 0600
+	directoryPermissions os.FileMode = 0700
+)
+
+func stateFilename(name string) string {
+	return filepath.Join(os.Getenv("HOME"), ".config", "admirer", name)
+}
+
+func readFile(filename string, value interface{}) error {
+	contents, err := os.ReadFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed reading state file: %w", err)
+	}
+
+	if err := json.Unmarshal(contents, value); err != nil {
+		return fmt.Errorf("failed reading state file %q: %w", filename, err)
+	}
+
+	return nil
+}
+
+func writeFile(filename string, value interface{}) error {
+	contents, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed encoding state: %w", err)
+	}
+
+	directory := filepath.Dir(filename)
+	if err := os.MkdirAll(directory, directoryPermissions); err != nil {
+		return fmt.Errorf("failed creating state directory: %w", err)
+	}
+
+	file, err := os.CreateTemp(directory, filepath.Base(filename)+".*")
+	if err != nil {
+		return fmt.Errorf("failed writing state file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(contents); err != nil {
+		file.Close()
+		return fmt.Errorf("failed writing state file: %w", err)
+	}
+
+	if err := file.Chmod(permissions); err != nil {
+		file.Close()
+		return fmt.Errorf("failed writing state file: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed writing state file: %w", err)
+	}
+
+	if err := os.Rename(file.Name(), filename); err != nil {
+		return fmt.Errorf("failed writing state file: %w", err)
+	}
+
+	return nil
+}